@@ -10,6 +10,9 @@ import (
 type backgroundUpdater struct {
 	ticker      *time.Ticker
 	updaterChan chan struct{}
+	// refreshChan is used by the stream retrievers to ask for a refresh of the flags
+	// before the next tick.
+	refreshChan chan struct{}
 }
 
 // newBackgroundUpdater init default value for the ticker and the channel.
@@ -32,6 +35,7 @@ func newBackgroundUpdater(pollingInterval time.Duration, useJitter bool) backgro
 	return backgroundUpdater{
 		ticker:      time.NewTicker(tickerDuration),
 		updaterChan: make(chan struct{}),
+		refreshChan: make(chan struct{}, 1),
 	}
 }
 
@@ -40,3 +44,12 @@ func (bgu *backgroundUpdater) close() {
 	bgu.ticker.Stop()
 	close(bgu.updaterChan)
 }
+
+// requestRefresh asks for a refresh of the flags, it does not block and the requests received
+// while a refresh is already pending are merged.
+func (bgu *backgroundUpdater) requestRefresh() {
+	select {
+	case bgu.refreshChan <- struct{}{}:
+	default:
+	}
+}
@@ -22,9 +22,9 @@ func NewOtelService() OtelService {
 }
 
 // Init the OpenTelemetry service
-func (s *OtelService) Init(ctx context.Context, config config.Config) error {
+func (s *OtelService) Init(ctx context.Context, conf config.Config) error {
 	// parsing the OpenTelemetry endpoint
-	u, err := url.Parse(config.OpenTelemetryOtlpEndpoint)
+	u, err := url.Parse(conf.OpenTelemetryOtlpEndpoint)
 	if err != nil {
 		return err
 	}
@@ -34,6 +34,9 @@ func (s *OtelService) Init(ctx context.Context, config config.Config) error {
 		opts = append(opts, otlptracehttp.WithInsecure())
 	}
 	opts = append(opts, otlptracehttp.WithEndpoint(u.Host))
+	if conf.OpenTelemetryOtlpCompression == config.OtlpCompressionGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
 	client := otlptracehttp.NewClient(opts...)
 
 	s.otelExporter, err = otlptrace.New(ctx, client)
@@ -46,7 +49,7 @@ func (s *OtelService) Init(ctx context.Context, config config.Config) error {
 		sdktrace.WithBatcher(s.otelExporter),
 		sdktrace.WithResource(resource.NewSchemaless(
 			attribute.String("service.name", "go-feature-flag"),
-			attribute.String("service.version", config.Version),
+			attribute.String("service.version", conf.Version),
 		)),
 	)
 	otel.SetTracerProvider(s.otelTraceProvider)
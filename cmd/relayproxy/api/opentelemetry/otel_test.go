@@ -0,0 +1,74 @@
+package opentelemetry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/cmd/relayproxy/api/opentelemetry"
+	"github.com/thomaspoignant/go-feature-flag/cmd/relayproxy/config"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOtelService_Compression(t *testing.T) {
+	tests := []struct {
+		name                string
+		compression         string
+		wantContentEncoding string
+	}{
+		{
+			name:                "no compression by default",
+			compression:         "",
+			wantContentEncoding: "",
+		},
+		{
+			name:                "explicit none compression",
+			compression:         config.OtlpCompressionNone,
+			wantContentEncoding: "",
+		},
+		{
+			name:                "gzip compression",
+			compression:         config.OtlpCompressionGzip,
+			wantContentEncoding: "gzip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var contentEncodings []string
+			collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				contentEncodings = append(contentEncodings, r.Header.Get("Content-Encoding"))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer collector.Close()
+
+			s := opentelemetry.NewOtelService()
+			err := s.Init(context.Background(), config.Config{
+				OpenTelemetryOtlpEndpoint:    collector.URL,
+				OpenTelemetryOtlpCompression: tt.compression,
+			})
+			require.NoError(t, err)
+
+			_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+			span.End()
+			provider, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+			require.True(t, ok)
+			require.NoError(t, provider.ForceFlush(context.Background()))
+			require.NoError(t, s.Stop())
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.NotEmpty(t, contentEncodings, "the collector should have received the spans")
+			for _, contentEncoding := range contentEncodings {
+				assert.Equal(t, tt.wantContentEncoding, contentEncoding)
+			}
+		})
+	}
+}
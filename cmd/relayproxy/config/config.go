@@ -187,6 +187,12 @@ type Config struct {
 	// Default: ""
 	OpenTelemetryOtlpEndpoint string `mapstructure:"openTelemetryOtlpEndpoint" koanf:"opentelemetryotlpendpoint"`
 
+	// OpenTelemetryOtlpCompression (optional) is the compression used to send the traces to the
+	// OpenTelemetry collector, it reduces the bandwidth used by the export.
+	// Accepted values are "none" and "gzip".
+	// Default: "none"
+	OpenTelemetryOtlpCompression string `mapstructure:"openTelemetryOtlpCompression" koanf:"opentelemetryotlpcompression"` //nolint: lll
+
 	// MonitoringPort (optional) is the port we are using to expose the metrics and healthchecks
 	// If not set we will use the same port as the proxy
 	MonitoringPort int `mapstructure:"monitoringPort" koanf:"monitoringport"`
@@ -255,6 +261,13 @@ func (c *Config) IsValid() error {
 		}
 	}
 
+	switch c.OpenTelemetryOtlpCompression {
+	case "", OtlpCompressionNone, OtlpCompressionGzip:
+	default:
+		return fmt.Errorf("invalid openTelemetryOtlpCompression %q, accepted values are %q and %q",
+			c.OpenTelemetryOtlpCompression, OtlpCompressionNone, OtlpCompressionGzip)
+	}
+
 	return nil
 }
 
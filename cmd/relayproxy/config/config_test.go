@@ -299,6 +299,7 @@ func TestConfig_IsValid(t *testing.T) {
 		Retrievers              *[]config.RetrieverConf
 		Exporter                *config.ExporterConf
 		Notifiers               []config.NotifierConf
+		OtlpCompression         string
 	}
 	tests := []struct {
 		name    string
@@ -382,6 +383,30 @@ func TestConfig_IsValid(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "valid gzip OpenTelemetry compression",
+			fields: fields{
+				ListenPort: 8080,
+				Retriever: &config.RetrieverConf{
+					Kind: "file",
+					Path: "../testdata/config/valid-file.yaml",
+				},
+				OtlpCompression: "gzip",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid OpenTelemetry compression",
+			fields: fields{
+				ListenPort: 8080,
+				Retriever: &config.RetrieverConf{
+					Kind: "file",
+					Path: "../testdata/config/valid-file.yaml",
+				},
+				OtlpCompression: "zstd",
+			},
+			wantErr: assert.Error,
+		},
 		{
 			name: "invalid retriever",
 			fields: fields{
@@ -446,18 +471,19 @@ func TestConfig_IsValid(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &config.Config{
-				ListenPort:              tt.fields.ListenPort,
-				HideBanner:              tt.fields.HideBanner,
-				EnableSwagger:           tt.fields.EnableSwagger,
-				Host:                    tt.fields.Host,
-				Debug:                   tt.fields.Debug,
-				PollingInterval:         tt.fields.PollingInterval,
-				FileFormat:              tt.fields.FileFormat,
-				StartWithRetrieverError: tt.fields.StartWithRetrieverError,
-				Retriever:               tt.fields.Retriever,
-				Exporter:                tt.fields.Exporter,
-				Notifiers:               tt.fields.Notifiers,
-				Retrievers:              tt.fields.Retrievers,
+				ListenPort:                   tt.fields.ListenPort,
+				HideBanner:                   tt.fields.HideBanner,
+				EnableSwagger:                tt.fields.EnableSwagger,
+				Host:                         tt.fields.Host,
+				Debug:                        tt.fields.Debug,
+				PollingInterval:              tt.fields.PollingInterval,
+				FileFormat:                   tt.fields.FileFormat,
+				StartWithRetrieverError:      tt.fields.StartWithRetrieverError,
+				Retriever:                    tt.fields.Retriever,
+				Exporter:                     tt.fields.Exporter,
+				Notifiers:                    tt.fields.Notifiers,
+				Retrievers:                   tt.fields.Retrievers,
+				OpenTelemetryOtlpCompression: tt.fields.OtlpCompression,
 			}
 			if tt.name == "empty config" {
 				c = nil
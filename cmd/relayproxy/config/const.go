@@ -1,3 +1,10 @@
 package config
 
 const OtelTracerName = "go-feature-flag"
+
+const (
+	// OtlpCompressionNone is sending the traces to the OpenTelemetry collector without compression.
+	OtlpCompressionNone = "none"
+	// OtlpCompressionGzip is compressing with gzip the traces sent to the OpenTelemetry collector.
+	OtlpCompressionGzip = "gzip"
+)
@@ -24,4 +24,7 @@ type FlagState struct {
 	// TrackEvents this flag is trackable.
 	TrackEvents bool `json:"trackEvents" example:"false"`
 	Failed      bool `json:"-"`
+
+	// Version is the version of the flag used, it is omitted if the flag has no version.
+	Version string `json:"version,omitempty" example:"1.0"`
 }
@@ -212,6 +212,18 @@ func Test_Evaluate(t *testing.T) {
 				bodyFile: "../testdata/ofrep/responses/no_targeting_key_context_with_key.json",
 			},
 		},
+		{
+			name: "Flag not found",
+			args: args{
+				bodyFile:            "../testdata/ofrep/valid_request.json",
+				configFlagsLocation: configFlagsLocation,
+				flagKey:             "unknown-flag",
+			},
+			want: want{
+				httpCode: http.StatusNotFound,
+				bodyFile: "../testdata/ofrep/responses/flag_not_found.json",
+			},
+		},
 		{
 			name: "Empty flag key",
 			args: args{
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"log"
+	"log/slog"
 	"time"
 
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 	"github.com/thomaspoignant/go-feature-flag/retriever"
+	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/thomaspoignant/go-feature-flag/notifier"
 )
@@ -30,6 +35,12 @@ type Config struct {
 	// Default: No log
 	Logger *log.Logger
 
+	// LeveledLogger (optional) structured logger use by the library, the messages are logged
+	// with a level (debug, info, warn, error) and their context as attributes.
+	// If set, it is used instead of Logger.
+	// Default: No log
+	LeveledLogger *slog.Logger
+
 	// Context (optional) used to call other services (HTTP, S3 ...)
 	// Default: context.Background()
 	Context context.Context
@@ -52,24 +63,56 @@ type Config struct {
 	// Notifiers (optional) is the list of notifiers called when a flag change
 	Notifiers []notifier.Notifier
 
-	// FileFormat (optional) is the format of the file to retrieve (available YAML, TOML and JSON)
+	// FileFormat (optional) is the format of the file to retrieve (available YAML, TOML, JSON and JSON5)
+	// JSON5 (or JSONC) is a JSON file allowing comments and trailing commas.
 	// Default: YAML
 	FileFormat string
 
 	// DataExporter (optional) is the configuration where we store how we should output the flags variations results
 	DataExporter DataExporter
 
+	// AdditionalDataExporters (optional) are data exporters used in addition to DataExporter.
+	// Each exporter has its own configuration and filters (IncludeFlags/ExcludeFlags), the events are
+	// sent to every exporter whose filters match the flag.
+	// Default: nil
+	AdditionalDataExporters []DataExporter
+
+	// DataCollectorSampleRate (optional) is the probability (between 0 and 1) for an evaluation event to be sent to
+	// the data exporter, ex: with 0.1 only 10% of the events are exported.
+	// The events exported have a SamplingWeight (1/DataCollectorSampleRate) to compute the real volume of events.
+	// Default: 0, every event is exported
+	DataCollectorSampleRate float64
+
+	// DataCollectorSampleSeed (optional) makes the sampling deterministic: the decision to keep an event is computed
+	// from the seed and the content of the event, so the same event is always kept or dropped.
+	// It is useful to have reproducible results in your tests.
+	// Default: nil, the events are sampled randomly
+	DataCollectorSampleSeed *int64
+
 	// StartWithRetrieverError (optional) If true, the SDK will start even if we did not get any flags from the retriever.
 	// It will serve only default values until all the retrievers returns the flags.
 	// The init method will not return any error if the flag file is unreachable.
 	// Default: false
 	StartWithRetrieverError bool
 
+	// PersistentFlagConfigurationFile (optional) is the path of a local file where the last flag configuration
+	// successfully retrieved is saved (the file is updated after each successful refresh).
+	// If the retrievers are not reachable when the SDK starts, the flags are loaded from this file,
+	// you can check if the SDK is serving these stale flags with IsUsingPersistedFlags.
+	// Default: "", nothing is persisted
+	PersistentFlagConfigurationFile string
+
 	// Offline (optional) If true, the SDK will not try to retrieve the flag file and will not export any data.
 	// No notification will be sent neither.
 	// Default: false
 	Offline bool
 
+	// EnableKillSwitch (optional) If true, the flag KillSwitchFlagKey (gofeatureflag.disableAll) is used as a
+	// global kill switch: when it is evaluated to true for an evaluation context, all the other flags are
+	// evaluated as disabled flags and return the default value with the reason DISABLED.
+	// Default: false
+	EnableKillSwitch bool
+
 	// EvaluationContextEnrichment (optional) will be merged with the evaluation context sent during the evaluation.
 	// It is useful to add common attributes to all the evaluation, such as a server version, environment, ...
 	//
@@ -77,6 +120,94 @@ type Config struct {
 	// if in the evaluation context you have a field with the same name, it will override the common one.
 	// Default: nil
 	EvaluationContextEnrichment map[string]interface{}
+
+	// DefaultContextAttributes (optional) are attributes merged at the root of the custom attributes of every
+	// evaluation context before evaluating the flags, so you can target them in your rules (ex: environment, region).
+	// If the evaluation context has an attribute with the same name, the value of the evaluation context is used.
+	// The evaluation context passed to the variation functions is not modified.
+	// Default: nil
+	DefaultContextAttributes map[string]interface{}
+
+	// Clock (optional) is used to stamp the creation date of the events sent to the data exporter.
+	// You can use exporter.FixedClock to have deterministic exports in your tests.
+	// Default: exporter.RealClock{}
+	Clock exporter.Clock
+
+	// ValidateConfiguration (optional) If true, the flag files are validated against the JSON schema of the flags
+	// before being loaded.
+	// An invalid flag file is rejected and the previous flags are kept in the cache.
+	// Default: false
+	ValidateConfiguration bool
+
+	// StrictValidation (optional) If true, the flag files are rejected when a rule references a variation
+	// that is not declared in the flag (variation, percentages, progressive rollout or holdout).
+	// The error lists each unknown reference, and the previous flags are kept in the cache.
+	// Without it, the rules using an unknown variation serve the SDK default value at evaluation time.
+	// Default: false
+	StrictValidation bool
+
+	// OnConfigurationChange (optional) is a function called every time the flag configuration has changed.
+	// It is called after the new flags are available in the cache, so any evaluation inside the callback
+	// will use the new configuration.
+	// The function is called asynchronously, and a panic inside the callback is recovered.
+	// Default: nil
+	OnConfigurationChange func(diff notifier.DiffCache)
+
+	// DefaultValueProvider (optional) is called when the flag requested does not exist, to compute a fallback
+	// value from the flag key and the evaluation context (ex: from a secondary source).
+	// The value returned is served with the reason FALLBACK, if the function returns an error or a value
+	// of the wrong type, the default value of the variation call is used.
+	// Default: nil
+	DefaultValueProvider func(flagKey string, evaluationCtx ffcontext.Context) (interface{}, error)
+
+	// OpenTelemetryMeterProvider (optional) if set, the counter gofeatureflag.evaluations is incremented for
+	// each flag evaluation with the attributes flag_key, variation and reason.
+	// It is independent of the data exporter and of the OpenTelemetry traces.
+	// Default: nil (no metrics)
+	OpenTelemetryMeterProvider metric.MeterProvider
+
+	// TrackPrerequisiteEvents (optional) if true, an event is sent to the data exporter for each prerequisite
+	// evaluated, in addition to the event of the evaluated flag.
+	// Default: false
+	TrackPrerequisiteEvents bool
+
+	// RequireTargetingKey (optional) if true, the evaluations with an evaluation context without key are rejected,
+	// the default value is served with the reason and the error code TARGETING_KEY_MISSING and the error
+	// ErrTargetingKeyMissing. In AllFlagsState, all the flags are marked as failed with the same reason.
+	// Without it, all the contexts without key are bucketed together in the percentage rollouts.
+	// Default: false
+	RequireTargetingKey bool
+
+	// EvaluationCacheTTL (optional) if positive, the result of an evaluation is kept in memory for this duration
+	// and reused for the next evaluations of the same flag (and same flag version) with the same evaluation context.
+	// The flags with a result depending on the date or on other flags are not cached.
+	// The cache is invalidated each time the configuration is refreshed.
+	// Default: 0, the evaluation results are not cached
+	EvaluationCacheTTL time.Duration
+
+	// EvaluationCacheSkipEvents (optional) if true, the evaluations served from the evaluation cache
+	// are not sent to the data exporter. By default, an event is sent for every evaluation call.
+	// Default: false
+	EvaluationCacheSkipEvents bool
+
+	// BucketingHasher (optional) computes the hash used to assign the users to the buckets of the percentage
+	// rollouts. Use it to align the assignments with an external system using a specific hash function.
+	// Default: nil, the internal hash function of go-feature-flag is used
+	BucketingHasher BucketingHasher
+
+	// ContextEnricher (optional) is called to augment the evaluation context before evaluating the rules
+	// (ex: add the tier of the user from a cache). It is called once per variation call, and once per
+	// AllFlagsState call for all the flags.
+	// If it returns an error or a nil context, the original evaluation context is used.
+	// The evaluation context sent to the data exporter is not modified.
+	// Default: nil
+	ContextEnricher func(evaluationCtx ffcontext.Context) (ffcontext.Context, error)
+}
+
+// BucketingHasher computes the hash of a bucketing key (ex: flag name + targeting key),
+// the user is assigned to a bucket with the hash modulo 100000.
+type BucketingHasher interface {
+	Hash(key string) uint32
 }
 
 // GetRetrievers returns a retriever.Retriever configure with the retriever available in the config.
@@ -96,3 +227,8 @@ func (c *Config) GetRetrievers() ([]retriever.Retriever, error) {
 	}
 	return retrievers, nil
 }
+
+// internalLogger returns the logger used by GO Feature Flag for this configuration.
+func (c *Config) internalLogger() *fflog.FFLogger {
+	return &fflog.FFLogger{LeveledLogger: c.LeveledLogger, LegacyLogger: c.Logger}
+}
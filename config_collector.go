@@ -18,6 +18,47 @@ type DataExporter struct {
 	// waited the FlushInterval.
 	MaxEventInMemory int64
 
+	// DeliveryGuarantee (optional) defines what happens to the events when the Exporter fails.
+	// - exporter.DeliveryAtLeastOnce keeps the events in memory and retries them during the next flush.
+	// - exporter.DeliveryBestEffort drops the events that failed to be exported.
+	// Default: exporter.DeliveryAtLeastOnce
+	DeliveryGuarantee exporter.DeliveryGuarantee
+
+	// MaxEventInRetry (optional) is the maximum number of events kept in memory to be retried when using
+	// exporter.DeliveryAtLeastOnce. When this limit is reached the oldest events are dropped.
+	// Default: 10 times MaxEventInMemory
+	MaxEventInRetry int64
+
+	// ShutdownTimeout (optional) is the maximum time Close waits for the events still in memory to be exported.
+	// Default: 10 seconds
+	ShutdownTimeout time.Duration
+
+	// SortEvents (optional) sorts the events of each batch by CreationDate and then by Key before
+	// sending them to the Exporter, it makes the exported files reproducible.
+	// Default: false, the events are exported in the order they have been collected.
+	SortEvents bool
+
+	// ValueExtract (optional) is a JSON Pointer (ex: /payment/provider) applied to the Value of each
+	// event to export only a sub value of the flag, the evaluation is not affected.
+	// If the pointer is invalid or if the path does not exist, the Value is exported as null.
+	// Default: the whole value is exported.
+	ValueExtract string
+
+	// IncludeFlags (optional) is the list of the keys of the flags to export.
+	// If set, only the events of these flags are sent to this Exporter.
+	// Default: nil, the events of all the flags are exported.
+	IncludeFlags []string
+
+	// ExcludeFlags (optional) is the list of the keys of the flags to never export with this Exporter.
+	// Default: nil
+	ExcludeFlags []string
+
+	// EventBufferSize (optional) is the size of a buffer used to send the events to this Exporter
+	// asynchronously from a dedicated goroutine. It prevents a slow Exporter from slowing down the
+	// evaluations and the other exporters, when the buffer is full the new events are dropped.
+	// Default: 0, the events are sent synchronously.
+	EventBufferSize int
+
 	// Exporter is the configuration of your exporter.
 	// You can see all available exporter in the exporter package.
 	Exporter exporter.Exporter
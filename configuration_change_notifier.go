@@ -0,0 +1,27 @@
+package ffclient
+
+import (
+	"log/slog"
+
+	"github.com/thomaspoignant/go-feature-flag/notifier"
+	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
+)
+
+// configurationChangeNotifier is a notifier calling the OnConfigurationChange callback of the config.
+type configurationChangeNotifier struct {
+	callback func(diff notifier.DiffCache)
+	logger   *fflog.FFLogger
+}
+
+// Notify is calling the callback with the differences of the cache.
+// The notification service is already calling the notifiers in a goroutine,
+// we only make sure that a panic in the callback is not crashing the application.
+func (c *configurationChangeNotifier) Notify(diff notifier.DiffCache) error {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Error("panic in the OnConfigurationChange callback", slog.Any("panic", r))
+		}
+	}()
+	c.callback(diff)
+	return nil
+}
@@ -0,0 +1,56 @@
+package ffclient
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+)
+
+// The errors returned by the variation functions, you can check the cause of a failed evaluation with errors.Is.
+var (
+	// ErrConfigNotLoaded is returned when go-feature-flag is not initialised or has not loaded the flags yet.
+	// It is a transient error, the evaluation should succeed once the configuration is loaded.
+	ErrConfigNotLoaded = errors.New("flag configuration not loaded")
+
+	// ErrFlagNotFound is returned when the flag does not exist in the configuration.
+	ErrFlagNotFound = errors.New("flag not found")
+
+	// ErrWrongVariationType is returned when the type of the flag does not match the variation function used.
+	ErrWrongVariationType = errors.New("wrong variation type")
+
+	// ErrTargetingKeyMissing is returned when Config.RequireTargetingKey is true and
+	// the evaluation context has no key.
+	ErrTargetingKeyMissing = errors.New("targeting key missing")
+)
+
+// EvaluationError is the error returned by the variation functions when the evaluation fails.
+// It wraps one of ErrConfigNotLoaded, ErrFlagNotFound, ErrWrongVariationType or ErrTargetingKeyMissing and contains
+// the error code of the evaluation.
+type EvaluationError struct {
+	// FlagKey is the key of the flag evaluated.
+	FlagKey string
+	// ErrorCode is the error code returned in the evaluation details.
+	ErrorCode flag.ErrorCode
+
+	message string
+	err     error
+}
+
+func newEvaluationError(flagKey string, errorCode flag.ErrorCode, err error, format string, args ...interface{},
+) *EvaluationError {
+	return &EvaluationError{
+		FlagKey:   flagKey,
+		ErrorCode: errorCode,
+		message:   fmt.Sprintf(format, args...),
+		err:       err,
+	}
+}
+
+func (e *EvaluationError) Error() string {
+	return e.message
+}
+
+func (e *EvaluationError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,108 @@
+package ffclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestEvaluationErrors(t *testing.T) {
+	stringFlag := &flag.InternalFlag{
+		Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+		DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+	}
+	tests := []struct {
+		name          string
+		goff          *GoFeatureFlag
+		wantErr       error
+		wantErrorCode flag.ErrorCode
+	}{
+		{
+			name:          "go-feature-flag not initialised",
+			goff:          nil,
+			wantErr:       ErrConfigNotLoaded,
+			wantErrorCode: flag.ErrorCodeProviderNotReady,
+		},
+		{
+			name:          "configuration not loaded",
+			goff:          &GoFeatureFlag{cache: NewCacheMock(nil, cache.ErrNotInitialised)},
+			wantErr:       ErrConfigNotLoaded,
+			wantErrorCode: flag.ErrorCodeProviderNotReady,
+		},
+		{
+			name:          "flag not found",
+			goff:          &GoFeatureFlag{cache: NewCacheMock(&flag.InternalFlag{}, errors.New("flag [test-flag] does not exists"))},
+			wantErr:       ErrFlagNotFound,
+			wantErrorCode: flag.ErrorCodeFlagNotFound,
+		},
+		{
+			name:          "wrong variation type",
+			goff:          &GoFeatureFlag{cache: NewCacheMock(stringFlag, nil)},
+			wantErr:       ErrWrongVariationType,
+			wantErrorCode: flag.ErrorCodeTypeMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.goff.BoolVariationDetails("test-flag", ffcontext.NewEvaluationContext("random-key"), true)
+			assert.True(t, res.Value)
+			assert.Equal(t, tt.wantErrorCode, res.ErrorCode)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			var evaluationErr *EvaluationError
+			if assert.ErrorAs(t, err, &evaluationErr) {
+				assert.Equal(t, "test-flag", evaluationErr.FlagKey)
+				assert.Equal(t, tt.wantErrorCode, evaluationErr.ErrorCode)
+			}
+		})
+	}
+}
+
+func TestRequireTargetingKey(t *testing.T) {
+	boolFlag := &flag.InternalFlag{
+		Variations:  &map[string]*interface{}{"enabled": testconvert.Interface(true)},
+		DefaultRule: &flag.Rule{VariationResult: testconvert.String("enabled")},
+	}
+	goff := &GoFeatureFlag{
+		cache:  NewCacheMock(boolFlag, nil),
+		config: Config{RequireTargetingKey: true},
+	}
+
+	res, err := goff.BoolVariationDetails("test-flag", ffcontext.NewEvaluationContext(""), false)
+	assert.False(t, res.Value)
+	assert.True(t, res.Failed)
+	assert.Equal(t, flag.ReasonTargetingKeyMissing, res.Reason)
+	assert.Equal(t, flag.ErrorCodeTargetingKeyMissing, res.ErrorCode)
+	assert.ErrorIs(t, err, ErrTargetingKeyMissing)
+	var evaluationErr *EvaluationError
+	if assert.ErrorAs(t, err, &evaluationErr) {
+		assert.Equal(t, flag.ErrorCodeTargetingKeyMissing, evaluationErr.ErrorCode)
+	}
+
+	allFlags := goff.AllFlagsState(ffcontext.NewEvaluationContext(""))
+	assert.False(t, allFlags.IsValid())
+	state := allFlags.GetFlags()["test-flag"]
+	assert.True(t, state.Failed)
+	assert.Nil(t, state.Value)
+	assert.Equal(t, flag.ReasonTargetingKeyMissing, state.Reason)
+	assert.Equal(t, flag.ErrorCodeTargetingKeyMissing, state.ErrorCode)
+
+	res, err = goff.BoolVariationDetails("test-flag", ffcontext.NewEvaluationContext("random-key"), false)
+	assert.NoError(t, err)
+	assert.True(t, res.Value)
+	assert.Equal(t, flag.ReasonStatic, res.Reason)
+	allFlags = goff.AllFlagsState(ffcontext.NewEvaluationContext("random-key"))
+	assert.True(t, allFlags.IsValid())
+	assert.Equal(t, true, allFlags.GetFlags()["test-flag"].Value)
+
+	// without the option, the contexts without key are evaluated
+	goff.config.RequireTargetingKey = false
+	res, err = goff.BoolVariationDetails("test-flag", ffcontext.NewEvaluationContext(""), false)
+	assert.NoError(t, err)
+	assert.True(t, res.Value)
+}
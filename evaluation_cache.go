@@ -0,0 +1,142 @@
+package ffclient
+
+import (
+	"container/list"
+	"encoding/json"
+	"hash/fnv"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+// maxEvaluationCacheEntries is the maximum number of evaluation results kept in memory,
+// when it is reached the oldest entry is evicted.
+const maxEvaluationCacheEntries = 100000
+
+// evaluationCacheKey identifies an evaluation result in the evaluationCache.
+type evaluationCacheKey struct {
+	flagKey      string
+	targetingKey string
+	secondaryKey string
+	version      string
+	// contextHash is the hash of all the attributes used to evaluate the rules, including the
+	// enrichment of the evaluation context and the DefaultContextAttributes.
+	contextHash uint64
+	// disabled is true if the flag is evaluated as disabled (ex: when the kill switch is on).
+	disabled bool
+}
+
+// newEvaluationCacheKey builds the key of an evaluation from the context used to evaluate the rules,
+// it returns false if the evaluation context can't be hashed.
+func newEvaluationCacheKey(
+	flagKey string, f flag.Flag, ruleCtx ffcontext.Context, flagCtx flag.Context,
+) (evaluationCacheKey, bool) {
+	// the attributes are merged the same way as during the evaluation of the flag.
+	ctxMap := utils.ContextToMap(ruleCtx)
+	maps.Copy(ctxMap, flagCtx.EvaluationContextEnrichment)
+	hash := fnv.New64a()
+	// the keys of the maps are sorted by the encoder, so the hash is stable.
+	if err := json.NewEncoder(hash).Encode(ctxMap); err != nil {
+		return evaluationCacheKey{}, false
+	}
+	return evaluationCacheKey{
+		flagKey:      flagKey,
+		targetingKey: ruleCtx.GetKey(),
+		secondaryKey: ffcontext.GetSecondaryKey(ruleCtx),
+		version:      f.GetVersion(),
+		contextHash:  hash.Sum64(),
+		disabled:     flagCtx.Disabled,
+	}, true
+}
+
+// evaluationCacheEntry is an evaluation result kept in the evaluationCache.
+type evaluationCacheEntry struct {
+	key       evaluationCacheKey
+	value     interface{}
+	details   flag.ResolutionDetails
+	expiresAt time.Time
+}
+
+// evaluationCache keeps the result of the evaluations for a short TTL, it is safe for concurrent use.
+// The entries are invalidated each time the configuration is refreshed.
+type evaluationCache struct {
+	mutex   sync.RWMutex
+	ttl     time.Duration
+	entries map[evaluationCacheKey]*list.Element
+	// order contains the entries sorted by expiration date, the oldest entry is at the front.
+	order *list.List
+	// now returns the current time, it is used to compute the expiration of the entries.
+	now func() time.Time
+}
+
+// newEvaluationCache creates an evaluationCache, it returns nil if the ttl is not positive.
+func newEvaluationCache(ttl time.Duration) *evaluationCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &evaluationCache{
+		ttl:     ttl,
+		entries: make(map[evaluationCacheKey]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// get returns the evaluation result of the flag for this targeting key if it has not expired.
+func (c *evaluationCache) get(key evaluationCacheKey) (interface{}, flag.ResolutionDetails, bool) {
+	if c == nil {
+		return nil, flag.ResolutionDetails{}, false
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, flag.ResolutionDetails{}, false
+	}
+	entry := element.Value.(*evaluationCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		return nil, flag.ResolutionDetails{}, false
+	}
+	return entry.value, entry.details, true
+}
+
+// set keeps the evaluation result of the flag for this targeting key for the duration of the TTL.
+func (c *evaluationCache) set(key evaluationCacheKey, value interface{}, details flag.ResolutionDetails) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+
+	// all the entries have the same TTL, so the front of the list is the first entry to expire.
+	for front := c.order.Front(); front != nil; front = c.order.Front() {
+		entry := front.Value.(*evaluationCacheEntry)
+		if len(c.entries) < maxEvaluationCacheEntries && now.Before(entry.expiresAt) {
+			break
+		}
+		c.order.Remove(front)
+		delete(c.entries, entry.key)
+	}
+	c.entries[key] = c.order.PushBack(
+		&evaluationCacheEntry{key: key, value: value, details: details, expiresAt: now.Add(c.ttl)})
+}
+
+// invalidate removes all the evaluation results, it is called when the configuration is refreshed.
+func (c *evaluationCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[evaluationCacheKey]*list.Element)
+	c.order.Init()
+}
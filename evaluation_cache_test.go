@@ -0,0 +1,182 @@
+package ffclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+)
+
+const evaluationCacheFlag = `cached-flag:
+  version: "%s"
+  variations:
+    A: "value-a"
+    B: "value-b"
+  defaultRule:
+    variation: %s
+`
+
+func TestEvaluationCache(t *testing.T) {
+	tests := []struct {
+		name           string
+		skipEvents     bool
+		wantNbExported int
+	}{
+		{name: "an event is sent for each evaluation call", skipEvents: false, wantNbExported: 3},
+		{name: "the cache hits are not sent to the exporter", skipEvents: true, wantNbExported: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+			require.NoError(t, os.WriteFile(flagFile, []byte(fmt.Sprintf(evaluationCacheFlag, "1", "A")), 0o600))
+
+			exp := &mock.Exporter{Bulk: true}
+			gffClient, err := New(Config{
+				PollingInterval: 10 * time.Minute,
+				Retriever:       &fileretriever.Retriever{Path: flagFile},
+				DataExporter: DataExporter{
+					FlushInterval:    10 * time.Minute,
+					MaxEventInMemory: 100,
+					Exporter:         exp,
+				},
+				EvaluationCacheTTL:        time.Minute,
+				EvaluationCacheSkipEvents: tt.skipEvents,
+			})
+			require.NoError(t, err)
+
+			user := ffcontext.NewEvaluationContext("user-key")
+			first, err := gffClient.StringVariation("cached-flag", user, "default")
+			require.NoError(t, err)
+			assert.Len(t, gffClient.evaluationCache.entries, 1, "the evaluation result should be cached")
+
+			second, err := gffClient.StringVariation("cached-flag", user, "default")
+			require.NoError(t, err)
+			assert.Equal(t, first, second, "a cache hit should return the same value")
+
+			// a refresh of the configuration invalidates the cache
+			require.NoError(t, os.WriteFile(flagFile, []byte(fmt.Sprintf(evaluationCacheFlag, "1", "B")), 0o600))
+			gffClient.refreshFlags()
+			third, err := gffClient.StringVariation("cached-flag", user, "default")
+			require.NoError(t, err)
+			assert.Equal(t, "value-b", third)
+
+			gffClient.Close()
+			assert.Len(t, exp.GetExportedEvents(), tt.wantNbExported)
+		})
+	}
+}
+
+func TestEvaluationCache_ttl(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newEvaluationCache(time.Second)
+	c.now = func() time.Time { return now }
+	key := evaluationCacheKey{flagKey: "flag", targetingKey: "user-key"}
+
+	c.set(key, "value", flag.ResolutionDetails{Variant: "A", Reason: flag.ReasonStatic, Cacheable: true})
+	value, _, hit := c.get(key)
+	assert.True(t, hit)
+	assert.Equal(t, "value", value)
+
+	now = now.Add(time.Second)
+	_, _, hit = c.get(key)
+	assert.False(t, hit, "the entry should be expired after the TTL")
+
+	assert.Nil(t, newEvaluationCache(0), "the cache is disabled without TTL")
+}
+
+func TestEvaluationCache_contextAttributes(t *testing.T) {
+	flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(flagFile, []byte(`beta-flag:
+  variations:
+    A: "value-a"
+    B: "value-b"
+  targeting:
+    - query: beta eq true and env eq "prod"
+      variation: B
+  defaultRule:
+    variation: A
+`), 0o600))
+
+	gffClient, err := New(Config{
+		PollingInterval:          10 * time.Minute,
+		Retriever:                &fileretriever.Retriever{Path: flagFile},
+		EvaluationCacheTTL:       time.Minute,
+		Environment:              "prod",
+		DefaultContextAttributes: map[string]interface{}{"beta": true},
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+
+	// the same targeting key with different attributes are different entries of the cache
+	betaUser := ffcontext.NewEvaluationContext("user-key")
+	got, err := gffClient.StringVariation("beta-flag", betaUser, "default")
+	require.NoError(t, err)
+	assert.Equal(t, "value-b", got)
+	notBetaUser := ffcontext.NewEvaluationContextBuilder("user-key").AddCustom("beta", false).Build()
+	got, err = gffClient.StringVariation("beta-flag", notBetaUser, "default")
+	require.NoError(t, err)
+	assert.Equal(t, "value-a", got)
+	got, err = gffClient.StringVariation("beta-flag", betaUser, "default")
+	require.NoError(t, err)
+	assert.Equal(t, "value-b", got)
+	assert.Len(t, gffClient.evaluationCache.entries, 2)
+}
+
+func TestEvaluationCache_eviction(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newEvaluationCache(time.Minute)
+	c.now = func() time.Time { return now }
+	details := flag.ResolutionDetails{Variant: "A", Reason: flag.ReasonStatic, Cacheable: true}
+	keyOf := func(i int) evaluationCacheKey {
+		return evaluationCacheKey{flagKey: "flag", targetingKey: fmt.Sprintf("user-%d", i)}
+	}
+
+	for i := 0; i < maxEvaluationCacheEntries; i++ {
+		c.set(keyOf(i), "value", details)
+	}
+	// refreshing an entry moves it to the end of the queue
+	c.set(keyOf(0), "value", details)
+
+	// when the cache is full, only the oldest entry is evicted
+	c.set(keyOf(maxEvaluationCacheEntries), "value", details)
+	assert.Len(t, c.entries, maxEvaluationCacheEntries)
+	_, _, hit := c.get(keyOf(1))
+	assert.False(t, hit, "the oldest entry should be evicted")
+	for _, i := range []int{0, 2, maxEvaluationCacheEntries} {
+		_, _, hit = c.get(keyOf(i))
+		assert.True(t, hit)
+	}
+
+	// the expired entries are removed when a new entry is added
+	now = now.Add(time.Minute)
+	c.set(keyOf(1), "value", details)
+	assert.Len(t, c.entries, 1)
+}
+
+func BenchmarkStringVariation_EvaluationCache(b *testing.B) {
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		b.Run(fmt.Sprintf("ttl=%s", ttl), func(b *testing.B) {
+			gffClient, err := New(Config{
+				PollingInterval:    10 * time.Minute,
+				Retriever:          &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+				EvaluationCacheTTL: ttl,
+			})
+			require.NoError(b, err)
+			defer gffClient.Close()
+
+			user := ffcontext.NewEvaluationContext("random-key")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = gffClient.BoolVariation("test-flag", user, false)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package ffclient
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// evaluationMeterName is the name of the OpenTelemetry meter used by go-feature-flag.
+	evaluationMeterName = "github.com/thomaspoignant/go-feature-flag"
+
+	// evaluationCounterName is the name of the counter incremented for each flag evaluation.
+	evaluationCounterName = "gofeatureflag.evaluations"
+)
+
+// evaluationMetrics is recording the flag evaluations as OpenTelemetry metrics.
+type evaluationMetrics struct {
+	counter metric.Int64Counter
+}
+
+// newEvaluationMetrics creates the counter of the evaluations using the meter provider.
+func newEvaluationMetrics(provider metric.MeterProvider) (*evaluationMetrics, error) {
+	counter, err := provider.Meter(evaluationMeterName).Int64Counter(
+		evaluationCounterName,
+		metric.WithDescription("Number of flag evaluations."),
+		metric.WithUnit("{evaluation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &evaluationMetrics{counter: counter}, nil
+}
+
+// record is incrementing the counter for this evaluation.
+// It does nothing if the metrics are not enabled.
+func (m *evaluationMetrics) record(flagKey string, variation string, reason string) {
+	if m == nil {
+		return
+	}
+	m.counter.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("flag_key", flagKey),
+		attribute.String("variation", variation),
+		attribute.String("reason", reason),
+	))
+}
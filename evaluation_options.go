@@ -0,0 +1,47 @@
+package ffclient
+
+import (
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+)
+
+// EvaluationOption is an option to customize a single evaluation of a flag.
+type EvaluationOption func(*evaluationOptions)
+
+// evaluationOptions contains the options of a single evaluation.
+type evaluationOptions struct {
+	// evaluationTime is the time used to evaluate the flag, time.Now() if zero.
+	evaluationTime time.Time
+	// flag is the flag to evaluate, if nil the flag is retrieved from the cache.
+	flag flag.Flag
+}
+
+// WithEvaluationTime evaluates the flag as if it was evaluated at the time t.
+// The time is used for the progressive rollouts, the scheduled rollouts, the experimentation rollouts,
+// the date operators and the expiration of the flag, it is useful to backtest a flag configuration.
+// The time only applies to this evaluation, the flags used by the other evaluations are not changed.
+func WithEvaluationTime(t time.Time) EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.evaluationTime = t
+	}
+}
+
+// withFlag evaluates the flag provided instead of retrieving it from the cache,
+// it is used to retrieve the flag only once when evaluating it for several contexts.
+func withFlag(f flag.Flag) EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.flag = f
+	}
+}
+
+// newEvaluationOptions applies all the options of an evaluation.
+func newEvaluationOptions(opts []EvaluationOption) evaluationOptions {
+	options := evaluationOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
@@ -0,0 +1,56 @@
+package ffclient
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+)
+
+// eventSampler decides which events are sent to the data exporter when Config.DataCollectorSampleRate is set.
+type eventSampler struct {
+	rate float64
+	seed *int64
+}
+
+// newEventSampler creates the sampler of the events.
+// It returns nil if every event should be exported.
+func newEventSampler(rate float64, seed *int64) *eventSampler {
+	if rate <= 0 || rate >= 1 {
+		return nil
+	}
+	return &eventSampler{rate: rate, seed: seed}
+}
+
+// sample returns true if the event should be exported, and sets its SamplingWeight.
+// It always returns true if the sampling is not enabled.
+func (s *eventSampler) sample(event *exporter.FeatureEvent) bool {
+	if s == nil {
+		return true
+	}
+	if s.draw(*event) >= s.rate {
+		return false
+	}
+	event.SamplingWeight = 1 / s.rate
+	return true
+}
+
+// draw returns a number in [0, 1), it is computed from the seed and the event when a seed is provided.
+func (s *eventSampler) draw(event exporter.FeatureEvent) float64 {
+	if s.seed == nil {
+		return rand.Float64() // nolint: gosec
+	}
+	hash := fnv.New64a()
+	_ = binary.Write(hash, binary.LittleEndian, *s.seed)
+	for _, field := range []string{
+		event.Key, event.UserKey, event.Variation, event.Source, strconv.FormatInt(event.CreationDate, 10),
+	} {
+		_, _ = hash.Write([]byte(field))
+		// separator to avoid collisions between the fields
+		_, _ = hash.Write([]byte{0})
+	}
+	// the 53 most significant bits are used to build a float64 in [0, 1)
+	return float64(hash.Sum64()>>11) / float64(uint64(1)<<53)
+}
@@ -0,0 +1,48 @@
+package ffclient
+
+import (
+	"maps"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/model"
+)
+
+// Explain evaluates the flag for this evaluation context and returns the trace of each rule considered,
+// with the result of every comparison of their queries.
+// It is strictly diagnostic, no event is sent to the data exporter and no metric is recorded.
+func Explain(flagKey string, ctx ffcontext.Context) (model.ExplainResult, error) {
+	return ff.Explain(flagKey, ctx)
+}
+
+// Explain evaluates the flag for this evaluation context and returns the trace of each rule considered,
+// with the result of every comparison of their queries.
+// It is strictly diagnostic, no event is sent to the data exporter and no metric is recorded.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) Explain(flagKey string, ctx ffcontext.Context) (model.ExplainResult, error) {
+	res, err := evaluateVariation[interface{}](g, flagKey, ctx, nil, "interface{}", true)
+	result := model.ExplainResult{
+		FlagKey:       flagKey,
+		Value:         res.Value,
+		VariationType: res.VariationType,
+		Reason:        res.Reason,
+		ErrorCode:     res.ErrorCode,
+		RuleIndex:     res.RuleIndex,
+	}
+	if err != nil || g == nil || g.config.Offline {
+		return result, err
+	}
+
+	f, err := g.getFlagFromCache(flagKey)
+	if err != nil {
+		return result, nil
+	}
+	if internalFlag, ok := f.(*flag.InternalFlag); ok {
+		flagCtx := flag.Context{
+			EvaluationContextEnrichment: maps.Clone(g.config.EvaluationContextEnrichment),
+		}
+		flagCtx.AddIntoEvaluationContextEnrichment("env", g.config.Environment)
+		result.Rules = internalFlag.Explain(g.ruleContext(ctx), flagCtx)
+	}
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package ffclient_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+)
+
+func TestExplain(t *testing.T) {
+	flagFile, err := os.CreateTemp("", "explain-*.yaml")
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(flagFile.Name()) }()
+	_ = os.WriteFile(flagFile.Name(), []byte(`explain-flag:
+  variations:
+    admin: "admin"
+    beta: "beta"
+    default: "default"
+  targeting:
+    - name: admins
+      query: email ew "@gofeatureflag.org" and role eq "admin"
+      variation: admin
+    - name: beta-testers
+      query: beta eq true
+      variation: beta
+  defaultRule:
+    variation: default
+`), os.ModePerm)
+
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile.Name()},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := ffcontext.NewEvaluationContextBuilder("user-key").
+		AddCustom("email", "john.doe@gofeatureflag.org").
+		AddCustom("role", "developer").
+		AddCustom("beta", true).
+		Build()
+	result, err := gffClient.Explain("explain-flag", ctx)
+	gffClient.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, "explain-flag", result.FlagKey)
+	assert.Equal(t, "beta", result.Value)
+	assert.Equal(t, "beta", result.VariationType)
+	assert.Equal(t, flag.ReasonTargetingMatch, result.Reason)
+	assert.Equal(t, 1, *result.RuleIndex)
+	require.Len(t, result.Rules, 3)
+
+	// the first rule fails because of the role attribute
+	admins := result.Rules[0]
+	assert.Equal(t, "admins", admins.Name)
+	assert.False(t, admins.Matched)
+	assert.Equal(t, []flag.ComparisonTrace{
+		{
+			Clause:       `email ew "@gofeatureflag.org"`,
+			Attribute:    "email",
+			Operator:     "ew",
+			Operand:      `"@gofeatureflag.org"`,
+			ContextValue: "john.doe@gofeatureflag.org",
+			Matched:      true,
+		},
+		{
+			Clause:       `role eq "admin"`,
+			Attribute:    "role",
+			Operator:     "eq",
+			Operand:      `"admin"`,
+			ContextValue: "developer",
+			Matched:      false,
+		},
+	}, admins.Comparisons)
+
+	// the second rule matches
+	betaTesters := result.Rules[1]
+	assert.Equal(t, "beta-testers", betaTesters.Name)
+	assert.True(t, betaTesters.Matched)
+	assert.Equal(t, []flag.ComparisonTrace{
+		{
+			Clause:       "beta eq true",
+			Attribute:    "beta",
+			Operator:     "eq",
+			Operand:      "true",
+			ContextValue: true,
+			Matched:      true,
+		},
+	}, betaTesters.Comparisons)
+
+	// the default rule is the last element of the trace
+	assert.Nil(t, result.Rules[2].RuleIndex)
+
+	// explain is strictly diagnostic, no event is collected
+	assert.Len(t, exp.GetExportedEvents(), 0)
+}
+
+func TestExplain_flagNotFound(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+
+	result, err := gffClient.Explain("not-existing-flag", ffcontext.NewEvaluationContext("user-key"))
+	assert.ErrorIs(t, err, ffclient.ErrFlagNotFound)
+	assert.Equal(t, flag.ErrorCodeFlagNotFound, result.ErrorCode)
+	assert.Empty(t, result.Rules)
+}
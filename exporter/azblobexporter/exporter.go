@@ -0,0 +1,184 @@
+package azblobexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/exporter/fileexporter"
+	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
+)
+
+type Exporter struct {
+	// AccountName is the name of your Azure Storage account.
+	// It is used to build the service URL https://<AccountName>.blob.core.windows.net/
+	// when no ConnectionString and no ServiceURL are provided.
+	AccountName string
+
+	// ConnectionString (optional) is the connection string of your storage account.
+	// If empty, the DefaultAzureCredential is used to authenticate
+	// (environment variables, workload identity, managed identity, Azure CLI, ...).
+	ConnectionString string
+
+	// ServiceURL (optional) is the URL of the blob service (ex: the URL of an Azurite emulator).
+	// It is ignored if a ConnectionString is provided.
+	// Default: https://<AccountName>.blob.core.windows.net/
+	ServiceURL string
+
+	// Container is the name of the container where the files are uploaded.
+	Container string
+
+	// Path allows you to specify in which directory (prefix) of the container you want to export your data.
+	Path string
+
+	// Format is the output format you want in your exported file.
+	// Available format are JSON, CSV and Parquet.
+	// Default: JSON
+	Format string
+
+	// Filename is the name of your output file
+	// You can use a templated config to define the name of your export files.
+	// Available replacement are {{ .Hostname}}, {{ .Timestamp}} and {{ .Format}}
+	// Default: "flag-variation-{{ .Hostname}}-{{ .Timestamp}}.{{ .Format}}"
+	Filename string
+
+	// CsvTemplate is used if your output format is CSV.
+	// This field will be ignored if you are using another format than CSV.
+	// You can decide which fields you want in your CSV line with a go-template syntax,
+	// please check exporter/feature_event.go to see what are the fields available.
+	// Default:
+	// {{ .Kind}};{{ .ContextKind}};{{ .UserKey}};{{ .CreationDate}};{{ .Key}};{{ .Variation}};{{ .Value}};{{ .Default}}\n
+	CsvTemplate string
+
+	// ParquetCompressionCodec is the parquet compression codec for better space efficiency.
+	// Available options https://github.com/apache/parquet-format/blob/master/Compression.md
+	// Default: SNAPPY
+	ParquetCompressionCodec string
+
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
+	client *azblob.Client
+	init   sync.Once
+}
+
+func (f *Exporter) initializeClient() error {
+	var initErr error
+	f.init.Do(func() {
+		client, err := f.newClient()
+		if err != nil {
+			initErr = fmt.Errorf("impossible to init Azure Blob Storage exporter: %v", err)
+			return
+		}
+		f.client = client
+	})
+	return initErr
+}
+
+// Export is saving a collection of events in a file.
+func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents []exporter.FeatureEvent) error {
+	if f.Container == "" {
+		return fmt.Errorf("you should specify a container. %v is invalid", f.Container)
+	}
+	if f.client == nil {
+		if initErr := f.initializeClient(); initErr != nil {
+			return initErr
+		}
+	}
+
+	// Create a temp directory to store the file we will produce
+	outputDir, err := os.MkdirTemp("", "go_feature_flag_azblob_export")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(outputDir) }()
+
+	// We call the File data exporter to get the file in the right format.
+	// Files will be put in the temp directory, so we will be able to upload them to Azure from there.
+	fileExporter := fileexporter.Exporter{
+		Format:                  f.Format,
+		OutputDir:               outputDir,
+		Filename:                f.Filename,
+		CsvTemplate:             f.CsvTemplate,
+		ParquetCompressionCodec: f.ParquetCompressionCodec,
+		FieldNameStyle:          f.FieldNameStyle,
+	}
+	err = fileExporter.Export(ctx, logger, featureEvents)
+	if err != nil {
+		return err
+	}
+
+	// Upload all the files in the folder to the container
+	files, err := os.ReadDir(outputDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		// read file
+		of, err := os.Open(outputDir + "/" + file.Name())
+		if err != nil {
+			fflog.Printf(logger, "error: [AzureExporter] impossible to open the file %s/%s", outputDir, file.Name())
+			continue
+		}
+
+		// prepend the path
+		blobName := file.Name()
+		if f.Path != "" {
+			blobName = f.Path + "/" + file.Name()
+		}
+
+		_, err = f.client.UploadFile(ctx, f.Container, blobName, of, nil)
+		_ = of.Close()
+		if err != nil {
+			return fmt.Errorf("error: [AzureExporter] impossible to upload the file %s to container %s: %v",
+				blobName, f.Container, describeError(err))
+		}
+		fflog.Printf(logger, "info: [AzureExporter] file %s uploaded.", blobName)
+	}
+	return nil
+}
+
+func (f *Exporter) IsBulk() bool {
+	return true
+}
+
+// newClient creates the azblob client using the connection string if provided
+// and the DefaultAzureCredential otherwise.
+func (f *Exporter) newClient() (*azblob.Client, error) {
+	if f.ConnectionString != "" {
+		return azblob.NewClientFromConnectionString(f.ConnectionString, nil)
+	}
+
+	serviceURL := f.ServiceURL
+	if serviceURL == "" {
+		if f.AccountName == "" {
+			return nil, fmt.Errorf("you should provide a ConnectionString, a ServiceURL or an AccountName")
+		}
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", f.AccountName)
+	}
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return azblob.NewClient(serviceURL, credential, nil)
+}
+
+// describeError adds a hint to the authentication errors returned by Azure.
+func describeError(err error) error {
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) ||
+		bloberror.HasCode(err, bloberror.AuthenticationFailed, bloberror.AuthorizationFailure,
+			bloberror.AuthorizationPermissionMismatch, bloberror.InvalidAuthenticationInfo) {
+		return fmt.Errorf("authentication failed, check the credentials of your storage account: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package azblobexporter_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/exporter/azblobexporter"
+)
+
+// azuriteKey is the well-known account key of the Azurite emulator.
+const azuriteKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+func connectionString(serverURL string) string {
+	return fmt.Sprintf("DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=%s;BlobEndpoint=%s/devstoreaccount1;",
+		azuriteKey, serverURL)
+}
+
+// fakeBlobService is a minimal blob service storing the uploaded blobs.
+type fakeBlobService struct {
+	mu    sync.Mutex
+	blobs map[string]string
+}
+
+func (f *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut || r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.blobs[r.URL.Path] = string(body)
+	f.mu.Unlock()
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestExporter_Export(t *testing.T) {
+	events := []exporter.FeatureEvent{
+		{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+			Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		path     string
+		wantPath string
+		wantBlob string
+	}{
+		{
+			name:     "json file under a prefix",
+			path:     "exports/2024",
+			wantPath: "/devstoreaccount1/events/exports/2024/flag-variation.json",
+			wantBlob: `{"kind":"feature","contextKind":"anonymousUser","userKey":"ABCD","creationDate":1617970547,"key":"random-key","variation":"Default","value":"YO","default":false,"version":"","source":"SERVER"}` + "\n",
+		},
+		{
+			name:     "csv file without prefix",
+			format:   "csv",
+			wantPath: "/devstoreaccount1/events/flag-variation.csv",
+			wantBlob: "feature;anonymousUser;ABCD;1617970547;random-key;Default;YO;false;SERVER\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeBlobService{blobs: map[string]string{}}
+			srv := httptest.NewServer(service)
+			defer srv.Close()
+
+			exp := azblobexporter.Exporter{
+				ConnectionString: connectionString(srv.URL),
+				Container:        "events",
+				Path:             tt.path,
+				Format:           tt.format,
+				Filename:         "flag-variation.{{ .Format}}",
+			}
+			err := exp.Export(context.Background(), log.New(io.Discard, "", 0), events)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{tt.wantPath: tt.wantBlob}, service.blobs)
+		})
+	}
+}
+
+func TestExporter_ExportErrors(t *testing.T) {
+	events := []exporter.FeatureEvent{{Kind: "feature", UserKey: "ABCD", Key: "random-key", Variation: "Default"}}
+
+	t.Run("authentication failed", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("x-ms-error-code", "AuthenticationFailed")
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		exp := azblobexporter.Exporter{ConnectionString: connectionString(srv.URL), Container: "events"}
+		err := exp.Export(context.Background(), nil, events)
+		assert.ErrorContains(t, err, "authentication failed, check the credentials of your storage account")
+	})
+
+	t.Run("missing container", func(t *testing.T) {
+		exp := azblobexporter.Exporter{ConnectionString: connectionString("http://127.0.0.1:1")}
+		err := exp.Export(context.Background(), nil, events)
+		assert.Error(t, err)
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		exp := azblobexporter.Exporter{Container: "events"}
+		err := exp.Export(context.Background(), nil, events)
+		assert.ErrorContains(t, err, "impossible to init Azure Blob Storage exporter")
+	})
+}
+
+func TestExporter_IsBulk(t *testing.T) {
+	exp := azblobexporter.Exporter{}
+	assert.True(t, exp.IsBulk())
+}
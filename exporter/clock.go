@@ -0,0 +1,27 @@
+package exporter
+
+import "time"
+
+// Clock is used to get the current time when stamping the events.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// RealClock is the default Clock, it returns the wall-clock time.
+type RealClock struct{}
+
+// Now returns the current wall-clock time.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock is a Clock always returning the same time, it is useful to have deterministic exports and tests.
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now returns the fixed time of the clock.
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
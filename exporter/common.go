@@ -2,18 +2,30 @@ package exporter
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
+	"unicode"
 )
 
 const DefaultCsvTemplate = "{{ .Kind}};{{ .ContextKind}};{{ .UserKey}};{{ .CreationDate}};{{ .Key}};{{ .Variation}};" +
 	"{{ .Value}};{{ .Default}};{{ .Source}}\n"
 const DefaultFilenameTemplate = "flag-variation-{{ .Hostname}}-{{ .Timestamp}}.{{ .Format}}"
 
+// DefaultRotatingFilenameTemplate is the default filename template used when the files are rotated,
+// the sequence avoids writing in the same file if 2 rotations happen during the same second.
+const DefaultRotatingFilenameTemplate = "flag-variation-{{ .Hostname}}-{{ .Timestamp}}-{{ .Sequence}}.{{ .Format}}"
+
+// DefaultCsvColumns is the list of columns used when exporting the events in CSV with columns.
+var DefaultCsvColumns = []string{
+	"kind", "contextKind", "userKey", "creationDate", "key", "variation", "value", "default", "version", "source",
+}
+
 // ParseTemplate is parsing the template given by the config or use the default template
 func ParseTemplate(name string, templateToParse string, defaultTemplate string) *template.Template {
 	if templateToParse == "" {
@@ -28,6 +40,12 @@ func ParseTemplate(name string, templateToParse string, defaultTemplate string)
 
 // ComputeFilename is computing the filename to use for the export file
 func ComputeFilename(template *template.Template, format string) (string, error) {
+	return ComputeFilenameWithSequence(template, format, 0)
+}
+
+// ComputeFilenameWithSequence is computing the filename to use for the export file,
+// the sequence is available in the template as {{ .Sequence}}.
+func ComputeFilenameWithSequence(template *template.Template, format string, sequence int) (string, error) {
 	hostname, _ := os.Hostname()
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 	format = strings.ToLower(format)
@@ -37,10 +55,12 @@ func ComputeFilename(template *template.Template, format string) (string, error)
 		Hostname  string
 		Timestamp string
 		Format    string
+		Sequence  int
 	}{
 		Hostname:  hostname,
 		Timestamp: timestamp,
 		Format:    format,
+		Sequence:  sequence,
 	})
 	return buf.String(), err
 }
@@ -52,7 +72,141 @@ func FormatEventInCSV(csvTemplate *template.Template, event FeatureEvent) ([]byt
 }
 
 func FormatEventInJSON(event FeatureEvent) ([]byte, error) {
-	b, err := json.Marshal(event)
+	return FormatEventInJSONWithStyle(event, FieldNameStyleCamel)
+}
+
+// FormatEventInJSONWithStyle is formatting the event in a JSON line using the field names of the style.
+func FormatEventInJSONWithStyle(event FeatureEvent, style FieldNameStyle) ([]byte, error) {
+	b, err := MarshalEventInJSON(event, style)
 	b = append(b, []byte("\n")...)
 	return b, err
 }
+
+// FieldNameStyle is the style of the field names used when the events are serialized in JSON.
+type FieldNameStyle = string
+
+const (
+	// FieldNameStyleCamel uses the camelCase field names (ex: userKey, creationDate), this is the default style.
+	FieldNameStyleCamel FieldNameStyle = "camel"
+
+	// FieldNameStyleSnake uses the snake_case field names (ex: user_key, creation_date).
+	FieldNameStyleSnake FieldNameStyle = "snake"
+)
+
+// MarshalEventInJSON is serializing the event in JSON using the field names of the style.
+// Only the field names of the event are changed, the keys inside the value are kept as they are.
+func MarshalEventInJSON(event FeatureEvent, style FieldNameStyle) ([]byte, error) {
+	b, err := json.Marshal(event)
+	if err != nil || !strings.EqualFold(style, FieldNameStyleSnake) {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	snakeFields := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		snakeFields[toSnakeCase(name)] = value
+	}
+	return json.Marshal(snakeFields)
+}
+
+// toSnakeCase converts a camelCase name to snake_case.
+func toSnakeCase(name string) string {
+	var builder strings.Builder
+	for index, char := range name {
+		if unicode.IsUpper(char) {
+			if index > 0 {
+				builder.WriteRune('_')
+			}
+			char = unicode.ToLower(char)
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// FormatEventsInCSVColumns is formatting the events in CSV, each column is a field of the FeatureEvent
+// (using the JSON name of the field, ex: kind,userKey,key,variation,value,creationDate).
+// If withHeader is true, the first line contains the name of the columns.
+// Non-scalar values are JSON-encoded in their cell.
+func FormatEventsInCSVColumns(columns []string, events []FeatureEvent, withHeader bool) ([]byte, error) {
+	if len(columns) == 0 {
+		columns = DefaultCsvColumns
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if withHeader {
+		if err := w.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+	for _, event := range events {
+		record := make([]string, 0, len(columns))
+		for _, column := range columns {
+			cell, err := csvCell(column, event)
+			if err != nil {
+				return nil, err
+			}
+			record = append(record, cell)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// csvCell returns the value of a column for an event.
+func csvCell(column string, event FeatureEvent) (string, error) {
+	switch column {
+	case "kind":
+		return event.Kind, nil
+	case "contextKind":
+		return event.ContextKind, nil
+	case "userKey":
+		return event.UserKey, nil
+	case "creationDate":
+		return strconv.FormatInt(event.CreationDate, 10), nil
+	case "key":
+		return event.Key, nil
+	case "variation":
+		return event.Variation, nil
+	case "value":
+		return csvValue(event.Value)
+	case "default":
+		return strconv.FormatBool(event.Default), nil
+	case "version":
+		return event.Version, nil
+	case "source":
+		return event.Source, nil
+	case "environment":
+		return event.Environment, nil
+	case "configGeneration":
+		return strconv.FormatInt(event.ConfigGeneration, 10), nil
+	case "configHash":
+		return event.ConfigHash, nil
+	case "samplingWeight":
+		return strconv.FormatFloat(event.SamplingWeight, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unknown CSV column: %s", column)
+	}
+}
+
+// csvValue returns the scalar values as they are and JSON-encodes the others.
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	default:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+}
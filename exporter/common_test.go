@@ -116,6 +116,14 @@ func TestComputeFilename(t *testing.T) {
 	}
 }
 
+func TestComputeFilenameWithSequence(t *testing.T) {
+	hostname, _ := os.Hostname()
+	template := exporter.ParseTemplate("filenameFormat", "", exporter.DefaultRotatingFilenameTemplate)
+	got, err := exporter.ComputeFilenameWithSequence(template, "JSON", 3)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^flag-variation-"+hostname+"-[0-9]*-3\\.json$", got)
+}
+
 func TestFormatEventInCSV(t *testing.T) {
 	type args struct {
 		csvTemplate *template.Template
@@ -170,6 +178,24 @@ func TestFormatEventInJSON(t *testing.T) {
 			want:    "{\"kind\":\"feature\",\"contextKind\":\"anonymousUser\",\"userKey\":\"ABCD\",\"creationDate\":1617970547,\"key\":\"random-key\",\"variation\":\"Default\",\"value\":\"YO\",\"default\":false,\"version\":\"\",\"source\":\"SERVER\"}\n",
 			wantErr: assert.NoError,
 		},
+		{
+			name: "with environment",
+			args: args{event: exporter.FeatureEvent{
+				Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+				Variation: "Default", Value: "YO", Default: false, Source: "SERVER", Environment: "production",
+			}},
+			want:    "{\"kind\":\"feature\",\"contextKind\":\"anonymousUser\",\"userKey\":\"ABCD\",\"creationDate\":1617970547,\"key\":\"random-key\",\"variation\":\"Default\",\"value\":\"YO\",\"default\":false,\"version\":\"\",\"source\":\"SERVER\",\"environment\":\"production\"}\n",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "with sampling weight",
+			args: args{event: exporter.FeatureEvent{
+				Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+				Variation: "Default", Value: "YO", Default: false, Source: "SERVER", SamplingWeight: 2,
+			}},
+			want:    "{\"kind\":\"feature\",\"contextKind\":\"anonymousUser\",\"userKey\":\"ABCD\",\"creationDate\":1617970547,\"key\":\"random-key\",\"variation\":\"Default\",\"value\":\"YO\",\"default\":false,\"version\":\"\",\"source\":\"SERVER\",\"samplingWeight\":2}\n",
+			wantErr: assert.NoError,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -181,3 +207,106 @@ func TestFormatEventInJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalEventInJSON(t *testing.T) {
+	event := exporter.FeatureEvent{
+		Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+		Variation: "Default", Value: map[string]interface{}{"nestedKey": "YO"}, Default: false, Source: "SERVER",
+		ConfigGeneration: 2,
+	}
+	tests := []struct {
+		name  string
+		style exporter.FieldNameStyle
+		want  string
+	}{
+		{
+			name:  "default style",
+			style: "",
+			want:  `{"kind":"feature","contextKind":"anonymousUser","userKey":"ABCD","creationDate":1617970547,"key":"random-key","variation":"Default","value":{"nestedKey":"YO"},"default":false,"version":"","source":"SERVER","configGeneration":2}`,
+		},
+		{
+			name:  "camel case",
+			style: exporter.FieldNameStyleCamel,
+			want:  `{"kind":"feature","contextKind":"anonymousUser","userKey":"ABCD","creationDate":1617970547,"key":"random-key","variation":"Default","value":{"nestedKey":"YO"},"default":false,"version":"","source":"SERVER","configGeneration":2}`,
+		},
+		{
+			name:  "snake case keeps the keys of the value",
+			style: exporter.FieldNameStyleSnake,
+			want:  `{"config_generation":2,"context_kind":"anonymousUser","creation_date":1617970547,"default":false,"key":"random-key","kind":"feature","source":"SERVER","user_key":"ABCD","value":{"nestedKey":"YO"},"variation":"Default","version":""}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exporter.MarshalEventInJSON(event, tt.style)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestFormatEventsInCSVColumns(t *testing.T) {
+	events := []exporter.FeatureEvent{
+		{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+			Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+		},
+		{
+			Kind: "feature", ContextKind: "user", UserKey: "EFGH", CreationDate: 1617970701, Key: "struct-key",
+			Variation: "Struct", Value: map[string]interface{}{"name": "john, doe", "age": 42}, Default: false,
+			Version: "1.0.0", Source: "SERVER",
+		},
+		{
+			Kind: "feature", ContextKind: "user", UserKey: "IJKL", CreationDate: 1617970702, Key: "number-key",
+			Variation: "Number", Value: 12.5, Default: true, Source: "SERVER",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		columns    []string
+		withHeader bool
+		want       string
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "custom columns with header",
+			columns:    []string{"kind", "userKey", "key", "variation", "value", "creationDate"},
+			withHeader: true,
+			want: "kind,userKey,key,variation,value,creationDate\n" +
+				"feature,ABCD,random-key,Default,YO,1617970547\n" +
+				"feature,EFGH,struct-key,Struct,\"{\"\"age\"\":42,\"\"name\"\":\"\"john, doe\"\"}\",1617970701\n" +
+				"feature,IJKL,number-key,Number,12.5,1617970702\n",
+			wantErr: assert.NoError,
+		},
+		{
+			name:       "custom columns without header",
+			columns:    []string{"userKey", "default"},
+			withHeader: false,
+			want:       "ABCD,false\nEFGH,false\nIJKL,true\n",
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "default columns",
+			withHeader: true,
+			want: "kind,contextKind,userKey,creationDate,key,variation,value,default,version,source\n" +
+				"feature,anonymousUser,ABCD,1617970547,random-key,Default,YO,false,,SERVER\n" +
+				"feature,user,EFGH,1617970701,struct-key,Struct,\"{\"\"age\"\":42,\"\"name\"\":\"\"john, doe\"\"}\",false,1.0.0,SERVER\n" +
+				"feature,user,IJKL,1617970702,number-key,Number,12.5,true,,SERVER\n",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"kind", "unknown"},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exporter.FormatEventsInCSVColumns(tt.columns, events, tt.withHeader)
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
@@ -2,20 +2,110 @@ package exporter
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
 	"log"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
 	defaultFlushInterval    = 60 * time.Second
 	defaultMaxEventInMemory = int64(100000)
+	// defaultRetryBufferFactor is used to compute the default size of the retry buffer
+	// based on the maximum number of events in memory.
+	defaultRetryBufferFactor = int64(10)
+	defaultShutdownTimeout   = 10 * time.Second
 )
 
+// SchedulerOption is a function that allows to configure optional settings of the Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithDeliveryGuarantee allows to configure what happens to the events when the export fails.
+// maxEventInRetry is the maximum number of events kept in memory for retry when using DeliveryAtLeastOnce,
+// if 0 we use 10 times the maximum number of events in memory.
+// An unknown delivery guarantee is ignored and DeliveryAtLeastOnce is used.
+func WithDeliveryGuarantee(deliveryGuarantee DeliveryGuarantee, maxEventInRetry int64) SchedulerOption {
+	return func(s *Scheduler) {
+		switch deliveryGuarantee {
+		case DeliveryAtLeastOnce, DeliveryBestEffort:
+			s.deliveryGuarantee = deliveryGuarantee
+		case "":
+			// we keep the default delivery guarantee
+		default:
+			fflog.Printf(s.logger, "warning: unknown delivery guarantee %q, %s is used\n",
+				deliveryGuarantee, s.deliveryGuarantee)
+		}
+		if maxEventInRetry > 0 {
+			s.maxEventInRetry = maxEventInRetry
+		}
+	}
+}
+
+// WithShutdownTimeout allows to configure the maximum time Close waits for the last export to complete.
+// if 0 we use the default timeout of 10 seconds.
+func WithShutdownTimeout(shutdownTimeout time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if shutdownTimeout > 0 {
+			s.shutdownTimeout = shutdownTimeout
+		}
+	}
+}
+
+// WithSortEvents allows to sort the events of each batch by CreationDate and then by Key before
+// calling the exporter, it makes the content exported reproducible.
+func WithSortEvents(sortEvents bool) SchedulerOption {
+	return func(s *Scheduler) {
+		s.sortEvents = sortEvents
+	}
+}
+
+// WithValueExtract allows to replace the Value of each event by the sub value located by a
+// JSON Pointer (RFC 6901, ex: /payment/provider) before the export.
+// If the pointer is invalid or if the path does not exist, the Value is exported as null.
+func WithValueExtract(valueExtract string) SchedulerOption {
+	return func(s *Scheduler) {
+		s.valueExtract = valueExtract
+	}
+}
+
+// WithFlagFilter allows to export only the events of some flags.
+// If include is not empty, only the events of the flags in include are exported.
+// The events of the flags in exclude are never exported.
+func WithFlagFilter(include []string, exclude []string) SchedulerOption {
+	return func(s *Scheduler) {
+		if len(include) > 0 {
+			s.includeFlags = make(map[string]struct{}, len(include))
+			for _, key := range include {
+				s.includeFlags[key] = struct{}{}
+			}
+		}
+		if len(exclude) > 0 {
+			s.excludeFlags = make(map[string]struct{}, len(exclude))
+			for _, key := range exclude {
+				s.excludeFlags[key] = struct{}{}
+			}
+		}
+	}
+}
+
+// WithEventBuffer allows to add the events asynchronously, the events are queued in a buffer of size
+// bufferSize and a dedicated goroutine adds them to the scheduler.
+// It prevents a slow exporter from slowing down the caller of AddEvent, when the buffer is full the
+// new events are dropped. If bufferSize is 0, the events are added synchronously.
+func WithEventBuffer(bufferSize int) SchedulerOption {
+	return func(s *Scheduler) {
+		if bufferSize > 0 {
+			s.eventBuffer = make(chan FeatureEvent, bufferSize)
+		}
+	}
+}
+
 // NewScheduler allows to create a new instance of Scheduler ready to be used to export data.
 func NewScheduler(ctx context.Context, flushInterval time.Duration, maxEventInMemory int64,
-	exp Exporter, logger *log.Logger,
+	exp Exporter, logger *log.Logger, opts ...SchedulerOption,
 ) *Scheduler {
 	if ctx == nil {
 		ctx = context.Background()
@@ -29,16 +119,27 @@ func NewScheduler(ctx context.Context, flushInterval time.Duration, maxEventInMe
 		maxEventInMemory = defaultMaxEventInMemory
 	}
 
-	return &Scheduler{
-		localCache:      make([]FeatureEvent, 0),
-		mutex:           sync.Mutex{},
-		maxEventInCache: maxEventInMemory,
-		exporter:        exp,
-		daemonChan:      make(chan struct{}),
-		ticker:          time.NewTicker(flushInterval),
-		logger:          logger,
-		ctx:             ctx,
+	scheduler := &Scheduler{
+		localCache:        make([]FeatureEvent, 0),
+		mutex:             sync.Mutex{},
+		maxEventInCache:   maxEventInMemory,
+		exporter:          exp,
+		daemonChan:        make(chan struct{}),
+		ticker:            time.NewTicker(flushInterval),
+		logger:            logger,
+		ctx:               ctx,
+		deliveryGuarantee: DeliveryAtLeastOnce,
+		maxEventInRetry:   maxEventInMemory * defaultRetryBufferFactor,
+		shutdownTimeout:   defaultShutdownTimeout,
 	}
+	for _, opt := range opts {
+		opt(scheduler)
+	}
+	if scheduler.eventBuffer != nil {
+		scheduler.eventBufferDone = make(chan struct{})
+		go scheduler.consumeEventBuffer()
+	}
+	return scheduler
 }
 
 // Scheduler is the struct that handle the data collection.
@@ -51,18 +152,87 @@ type Scheduler struct {
 	exporter        Exporter
 	logger          *log.Logger
 	ctx             context.Context
+
+	// deliveryGuarantee defines what we do with the events when the export fails.
+	deliveryGuarantee DeliveryGuarantee
+	// maxEventInRetry is the maximum number of events kept for retry when using DeliveryAtLeastOnce.
+	maxEventInRetry int64
+	// droppedEvents is the number of events that have been dropped without being exported.
+	droppedEvents int64
+	// shutdownTimeout is the maximum time Close waits for the last export.
+	shutdownTimeout time.Duration
+	closeOnce       sync.Once
+	// sortEvents is true if the events are sorted by CreationDate and Key before the export.
+	sortEvents bool
+	// valueExtract is the JSON Pointer applied to the Value of each event before the export.
+	valueExtract string
+	// includeFlags are the keys of the flags to export, if nil all the flags are exported.
+	includeFlags map[string]struct{}
+	// excludeFlags are the keys of the flags never exported.
+	excludeFlags map[string]struct{}
+
+	// eventBuffer is the queue of the events added asynchronously, nil if the events are added synchronously.
+	eventBuffer chan FeatureEvent
+	// eventBufferMutex prevents AddEvent from sending an event in the buffer while it is closed.
+	eventBufferMutex  sync.RWMutex
+	eventBufferClosed bool
+	// eventBufferDone is closed when all the events of the buffer have been added to the scheduler.
+	eventBufferDone chan struct{}
+	// eventBufferDropped is the number of events dropped because the buffer was full.
+	eventBufferDropped atomic.Int64
 }
 
 // AddEvent allow to add an event to the local cache and to call the exporter if we reach
 // the maximum number of events that can be present in the cache.
+// With an event buffer (see WithEventBuffer), the event is queued and AddEvent never waits for the exporter.
 func (dc *Scheduler) AddEvent(event FeatureEvent) {
+	if !dc.isFlagExported(event.Key) {
+		return
+	}
+
+	if dc.eventBuffer != nil {
+		dc.eventBufferMutex.RLock()
+		defer dc.eventBufferMutex.RUnlock()
+		if dc.eventBufferClosed {
+			return
+		}
+		select {
+		case dc.eventBuffer <- event:
+		default:
+			// the exporter is too slow, we drop the event instead of blocking the caller.
+			dc.eventBufferDropped.Add(1)
+		}
+		return
+	}
+	dc.addEvent(event)
+}
+
+// consumeEventBuffer adds the events of the buffer to the scheduler until the buffer is closed.
+func (dc *Scheduler) consumeEventBuffer() {
+	defer close(dc.eventBufferDone)
+	for event := range dc.eventBuffer {
+		dc.addEvent(event)
+	}
+}
+
+// addEvent adds an event to the local cache and calls the exporter if we reach
+// the maximum number of events that can be present in the cache.
+func (dc *Scheduler) addEvent(event FeatureEvent) {
+	if dc.valueExtract != "" {
+		value, err := extractValue(event.Value, dc.valueExtract)
+		if err != nil {
+			fflog.Printf(dc.logger, "warning: impossible to extract the value of the flag %s: %v\n", event.Key, err)
+		}
+		event.Value = value
+	}
+
 	if !dc.exporter.IsBulk() {
 		dc.mutex.Lock()
 		// if we are not in bulk we are directly flushing the data
 		dc.localCache = append(dc.localCache, event)
 		go func() {
 			defer dc.mutex.Unlock()
-			dc.flush()
+			dc.flush(dc.ctx)
 		}()
 		return
 	}
@@ -70,11 +240,31 @@ func (dc *Scheduler) AddEvent(event FeatureEvent) {
 	dc.mutex.Lock()
 	defer dc.mutex.Unlock()
 	if int64(len(dc.localCache)) >= dc.maxEventInCache {
-		dc.flush()
+		dc.flush(dc.ctx)
 	}
 	dc.localCache = append(dc.localCache, event)
 }
 
+// isFlagExported returns true if the events of the flag pass the filters of the scheduler.
+func (dc *Scheduler) isFlagExported(flagKey string) bool {
+	if _, excluded := dc.excludeFlags[flagKey]; excluded {
+		return false
+	}
+	if dc.includeFlags == nil {
+		return true
+	}
+	_, included := dc.includeFlags[flagKey]
+	return included
+}
+
+// GetDroppedEvents returns the number of events that have been dropped without being exported,
+// because the export failed and the events could not be kept for a retry or because the event buffer was full.
+func (dc *Scheduler) GetDroppedEvents() int64 {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+	return dc.droppedEvents + dc.eventBufferDropped.Load()
+}
+
 // StartDaemon will start a goroutine to check every X seconds if we should send the data.
 // The daemon is started only if we have a bulk exporter.
 func (dc *Scheduler) StartDaemon() {
@@ -83,7 +273,7 @@ func (dc *Scheduler) StartDaemon() {
 		case <-dc.ticker.C:
 			// send data and clear local cache
 			dc.mutex.Lock()
-			dc.flush()
+			dc.flush(dc.ctx)
 			dc.mutex.Unlock()
 		case <-dc.daemonChan:
 			// stop the daemon
@@ -92,28 +282,142 @@ func (dc *Scheduler) StartDaemon() {
 	}
 }
 
-// Close will stop the daemon and send the data still in the cache
+// Close will stop the daemon and send the data still in the cache.
+// It waits for the exports in progress and for the last export, bounded by the shutdown timeout.
+// The last export is done even if the context of the scheduler has been cancelled.
 func (dc *Scheduler) Close() {
-	// Close the daemon
-	dc.ticker.Stop()
-	close(dc.daemonChan)
+	dc.closeOnce.Do(func() {
+		// Close the daemon
+		dc.ticker.Stop()
+		close(dc.daemonChan)
 
-	// Send the data still in the cache
-	dc.mutex.Lock()
-	dc.flush()
-	dc.mutex.Unlock()
+		if dc.eventBuffer != nil {
+			dc.eventBufferMutex.Lock()
+			dc.eventBufferClosed = true
+			close(dc.eventBuffer)
+			dc.eventBufferMutex.Unlock()
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(dc.ctx), dc.shutdownTimeout)
+		defer cancel()
+
+		// Send the data still in the cache
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if dc.eventBuffer != nil {
+				// the events still in the buffer are added before the last export.
+				<-dc.eventBufferDone
+			}
+			dc.mutex.Lock()
+			defer dc.mutex.Unlock()
+			dc.flush(ctx)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			fflog.Printf(dc.logger, "error: timeout of %s reached while exporting the remaining data\n",
+				dc.shutdownTimeout)
+		}
+	})
 }
 
 // flush will call the data exporter and clear the cache
 // this method should be always called with a mutex
-func (dc *Scheduler) flush() {
+func (dc *Scheduler) flush(ctx context.Context) {
 	if len(dc.localCache) > 0 {
-		err := dc.exporter.Export(dc.ctx, dc.logger, dc.localCache)
-		if err != nil {
-			fflog.Printf(dc.logger, "error while exporting data: %v\n", err)
-			return
+		if dc.sortEvents {
+			sortFeatureEvents(dc.localCache)
+		}
+		maxBatchBytes := 0
+		if limiter, ok := dc.exporter.(BatchSizeLimiter); ok {
+			maxBatchBytes = limiter.GetMaxBatchBytes()
+		}
+		exported := 0
+		for _, batch := range splitBatch(dc.localCache, maxBatchBytes) {
+			panicked, err := dc.export(ctx, batch)
+			if panicked {
+				// a batch making the exporter panic is never retried, it would panic again.
+				dc.droppedEvents += int64(len(batch))
+				exported += len(batch)
+				continue
+			}
+			if err != nil {
+				fflog.Printf(dc.logger, "error while exporting data: %v\n", err)
+				if dc.deliveryGuarantee != DeliveryBestEffort {
+					// the events of this batch and of the next ones are retried during the next flush
+					dc.localCache = dc.localCache[exported:]
+					dc.trimRetryBuffer()
+					return
+				}
+				dc.droppedEvents += int64(len(batch))
+			}
+			exported += len(batch)
 		}
 	}
 	// Clear the cache
 	dc.localCache = make([]FeatureEvent, 0)
 }
+
+// export calls the exporter and recovers if the exporter panics, so a buggy exporter can't crash the application.
+// It returns true if the exporter panicked.
+func (dc *Scheduler) export(ctx context.Context, batch []FeatureEvent) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fflog.Printf(dc.logger, "error: the exporter %T panicked, %d events have been dropped: %v\n",
+				dc.exporter, len(batch), r)
+			panicked = true
+		}
+	}()
+	return false, dc.exporter.Export(ctx, dc.logger, batch)
+}
+
+// trimRetryBuffer drops the oldest events if we have more events to retry than the limit.
+// this method should be always called with a mutex
+func (dc *Scheduler) trimRetryBuffer() {
+	nbEvents := int64(len(dc.localCache))
+	if nbEvents <= dc.maxEventInRetry {
+		return
+	}
+	nbDropped := nbEvents - dc.maxEventInRetry
+	dc.localCache = dc.localCache[nbDropped:]
+	dc.droppedEvents += nbDropped
+	fflog.Printf(dc.logger, "warning: retry buffer is full, %d events have been dropped\n", nbDropped)
+}
+
+// sortFeatureEvents sorts the events by CreationDate and then by Key, the order of the
+// events with the same CreationDate and Key is kept.
+func sortFeatureEvents(events []FeatureEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].CreationDate != events[j].CreationDate {
+			return events[i].CreationDate < events[j].CreationDate
+		}
+		return events[i].Key < events[j].Key
+	})
+}
+
+// splitBatch splits the events in batches of maximum maxBatchBytes once marshaled in a JSON array.
+// If maxBatchBytes is 0 or less, all the events are in the same batch.
+func splitBatch(events []FeatureEvent, maxBatchBytes int) [][]FeatureEvent {
+	if maxBatchBytes <= 0 {
+		return [][]FeatureEvent{events}
+	}
+	// the 2 bytes are the brackets of the JSON array
+	const arraySize = 2
+	batches := make([][]FeatureEvent, 0)
+	start, size := 0, arraySize
+	for i, event := range events {
+		// the separator between 2 events is counted with the event
+		eventSize := 1
+		if content, err := json.Marshal(event); err == nil {
+			eventSize += len(content)
+		}
+		if i > start && size+eventSize > maxBatchBytes {
+			batches = append(batches, events[start:i])
+			start, size = i, arraySize
+		}
+		size += eventSize
+	}
+	return append(batches, events[start:])
+}
@@ -2,13 +2,18 @@ package exporter_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/exporter/fileexporter"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 	"github.com/thomaspoignant/go-feature-flag/testutils"
 
@@ -129,3 +134,396 @@ func TestDataExporterScheduler_nonBulkExporter(t *testing.T) {
 
 	assert.Equal(t, inputEvents[:100], mockExporter.GetExportedEvents())
 }
+
+func TestDataExporterScheduler_deliveryGuarantee(t *testing.T) {
+	tests := []struct {
+		name              string
+		deliveryGuarantee exporter.DeliveryGuarantee
+		maxEventInRetry   int64
+		expectedExported  int
+		expectedDropped   int64
+	}{
+		{
+			name:              "at-least-once should retain the events for retry",
+			deliveryGuarantee: exporter.DeliveryAtLeastOnce,
+			// 10 events in the failing export + 11 events retried in Close
+			expectedExported: 21,
+		},
+		{
+			name:              "at-least-once should drop the oldest events when the retry buffer is full",
+			deliveryGuarantee: exporter.DeliveryAtLeastOnce,
+			maxEventInRetry:   5,
+			// 10 events in the failing export + 5 retained events and the new one in Close
+			expectedExported: 16,
+			expectedDropped:  5,
+		},
+		{
+			name:              "best-effort should drop the events",
+			deliveryGuarantee: exporter.DeliveryBestEffort,
+			// 10 events in the failing export + 1 event in Close
+			expectedExported: 11,
+			expectedDropped:  10,
+		},
+		{
+			name:              "unknown delivery guarantee should use at-least-once",
+			deliveryGuarantee: "EXACTLY_ONCE",
+			// 10 events in the failing export + 11 events retried in Close
+			expectedExported: 21,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExporter := mock.Exporter{Err: errors.New("random err"), ExpectedNumberErr: 1, Bulk: true}
+			dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 10, &mockExporter, nil,
+				exporter.WithDeliveryGuarantee(tt.deliveryGuarantee, tt.maxEventInRetry))
+			go dc.StartDaemon()
+
+			for i := 0; i <= 10; i++ {
+				dc.AddEvent(exporter.NewFeatureEvent(
+					ffcontext.NewEvaluationContextBuilder("ABCD").Build(),
+					"random-key", "YO", "defaultVar", false, "", "SERVER"))
+			}
+			dc.Close()
+			assert.Len(t, mockExporter.GetExportedEvents(), tt.expectedExported)
+			assert.Equal(t, tt.expectedDropped, dc.GetDroppedEvents())
+		})
+	}
+}
+
+func TestDataExporterScheduler_flushWhenMaxEventInMemoryIsReached(t *testing.T) {
+	mockExporter := mock.Exporter{Bulk: true}
+	dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 5, &mockExporter, nil)
+	go dc.StartDaemon()
+	defer dc.Close()
+
+	for i := 0; i < 12; i++ {
+		dc.AddEvent(exporter.NewFeatureEvent(
+			ffcontext.NewEvaluationContextBuilder("ABCD").Build(),
+			"random-key", "YO", "defaultVar", false, "", "SERVER"))
+	}
+
+	// the flush interval is not reached, 2 flushes of 5 events should have been done.
+	assert.Len(t, mockExporter.GetExportedEvents(), 10)
+	assert.Equal(t, int64(0), dc.GetDroppedEvents())
+}
+
+func TestDataExporterScheduler_closeFlushesRemainingEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	exp := &contextExporter{}
+	dc := exporter.NewScheduler(ctx, 10*time.Minute, 100, exp, nil)
+	go dc.StartDaemon()
+
+	for i := 0; i < 10; i++ {
+		dc.AddEvent(exporter.NewFeatureEvent(
+			ffcontext.NewEvaluationContextBuilder("ABCD").Build(),
+			"random-key", "YO", "defaultVar", false, "", "SERVER"))
+	}
+
+	// the context of the scheduler is cancelled before closing, the last batch should still be exported.
+	cancel()
+	dc.Close()
+	assert.Equal(t, 10, exp.nbEvents)
+	assert.NoError(t, exp.ctxErr)
+
+	// closing twice should not panic
+	assert.NotPanics(t, dc.Close)
+}
+
+func TestDataExporterScheduler_closeWithTimeout(t *testing.T) {
+	file, _ := os.CreateTemp("", "log")
+	defer func() { _ = os.Remove(file.Name()) }()
+	defer file.Close()
+
+	exp := &contextExporter{block: true}
+	dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 100, exp, log.New(file, "", 0),
+		exporter.WithShutdownTimeout(50*time.Millisecond))
+	dc.AddEvent(exporter.NewFeatureEvent(
+		ffcontext.NewEvaluationContextBuilder("ABCD").Build(),
+		"random-key", "YO", "defaultVar", false, "", "SERVER"))
+
+	start := time.Now()
+	dc.Close()
+	assert.Less(t, time.Since(start), 1*time.Second)
+
+	logs, _ := os.ReadFile(file.Name())
+	assert.Contains(t, string(logs), "timeout of 50ms reached while exporting the remaining data")
+}
+
+func TestDataExporterScheduler_sortEvents(t *testing.T) {
+	outputDir := t.TempDir()
+	dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 100,
+		&fileexporter.Exporter{Format: "json", OutputDir: outputDir, Filename: "events.json"}, nil,
+		exporter.WithSortEvents(true))
+
+	newEvent := func(key string, creationDate int64) exporter.FeatureEvent {
+		event := exporter.NewFeatureEvent(
+			ffcontext.NewEvaluationContextBuilder("ABCD").Build(), key, "YO", "defaultVar", false, "", "SERVER")
+		event.CreationDate = creationDate
+		return event
+	}
+	for _, event := range []exporter.FeatureEvent{
+		newEvent("flag-b", 1617970701),
+		newEvent("flag-c", 1617970700),
+		newEvent("flag-a", 1617970701),
+		newEvent("flag-a", 1617970700),
+	} {
+		dc.AddEvent(event)
+	}
+	dc.Close()
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "events.json"))
+	assert.NoError(t, err)
+	var exported []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var event exporter.FeatureEvent
+		assert.NoError(t, json.Unmarshal([]byte(line), &event))
+		exported = append(exported, event.Key+"@"+strconv.FormatInt(event.CreationDate, 10))
+	}
+	assert.Equal(t, []string{
+		"flag-a@1617970700",
+		"flag-c@1617970700",
+		"flag-a@1617970701",
+		"flag-b@1617970701",
+	}, exported)
+}
+
+func TestDataExporterScheduler_valueExtract(t *testing.T) {
+	value := map[string]interface{}{
+		"payment": map[string]interface{}{
+			"provider": "stripe",
+			"methods":  []interface{}{"card", "paypal"},
+		},
+		"theme": "dark",
+	}
+	tests := []struct {
+		name         string
+		valueExtract string
+		want         interface{}
+	}{
+		{name: "no pointer exports the whole value", valueExtract: "", want: value},
+		{name: "nested object", valueExtract: "/payment/provider", want: "stripe"},
+		{name: "array index", valueExtract: "/payment/methods/1", want: "paypal"},
+		{name: "missing path exports null", valueExtract: "/payment/currency", want: nil},
+		{name: "invalid pointer exports null", valueExtract: "payment", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := &mock.Exporter{Bulk: true}
+			dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 100, exp, nil,
+				exporter.WithValueExtract(tt.valueExtract))
+			dc.AddEvent(exporter.NewFeatureEvent(
+				ffcontext.NewEvaluationContextBuilder("ABCD").Build(), "json-flag", value, "defaultVar", false, "",
+				"SERVER"))
+			dc.Close()
+
+			events := exp.GetExportedEvents()
+			assert.Len(t, events, 1)
+			assert.Equal(t, tt.want, events[0].Value)
+		})
+	}
+}
+
+func TestDataExporterScheduler_maxBatchBytes(t *testing.T) {
+	event := exporter.NewFeatureEvent(ffcontext.NewEvaluationContextBuilder("ABCD").Build(),
+		"random-key", "YO", "defaultVar", false, "", "SERVER")
+	content, err := json.Marshal(event)
+	assert.NoError(t, err)
+	// the limit allows 2 events per batch: the brackets of the array and each event with its separator
+	maxBatchBytes := 2 + 2*(len(content)+1)
+
+	tests := []struct {
+		name          string
+		maxBatchBytes int
+		nbEvents      int
+		wantNbExport  int
+	}{
+		{name: "split in batches of 2 events", maxBatchBytes: maxBatchBytes, nbEvents: 10, wantNbExport: 5},
+		{name: "last batch is smaller", maxBatchBytes: maxBatchBytes, nbEvents: 5, wantNbExport: 3},
+		{name: "event bigger than the limit is exported alone", maxBatchBytes: 10, nbEvents: 3, wantNbExport: 3},
+		{name: "no limit", maxBatchBytes: 0, nbEvents: 10, wantNbExport: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExporter := mock.Exporter{Bulk: true, MaxBatchBytes: tt.maxBatchBytes}
+			dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 100, &mockExporter, nil)
+			for i := 0; i < tt.nbEvents; i++ {
+				dc.AddEvent(event)
+			}
+			dc.Close()
+
+			assert.Equal(t, tt.wantNbExport, mockExporter.GetNbExport())
+			assert.Len(t, mockExporter.GetExportedEvents(), tt.nbEvents)
+		})
+	}
+}
+
+// contextExporter is a bulk exporter recording the state of the context used for the export.
+// If block is true, the export waits until the context is done.
+type contextExporter struct {
+	block    bool
+	nbEvents int
+	ctxErr   error
+}
+
+func (c *contextExporter) Export(ctx context.Context, _ *log.Logger, events []exporter.FeatureEvent) error {
+	if c.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	c.nbEvents += len(events)
+	c.ctxErr = ctx.Err()
+	return nil
+}
+
+func (c *contextExporter) IsBulk() bool {
+	return true
+}
+
+func TestDataExporterScheduler_exporterPanics(t *testing.T) {
+	newEvents := func(nb int) []exporter.FeatureEvent {
+		events := make([]exporter.FeatureEvent, 0, nb)
+		for i := 0; i < nb; i++ {
+			events = append(events, exporter.NewFeatureEvent(ffcontext.NewEvaluationContext("ABCD"),
+				"random-key", "YO", "defaultVar", false, "", "SERVER"))
+		}
+		return events
+	}
+
+	t.Run("bulk exporter", func(t *testing.T) {
+		panicExporter := mock.Exporter{Bulk: true, ExpectedNumberPanic: 1}
+		siblingExporter := mock.Exporter{Bulk: true}
+		dc := exporter.NewScheduler(context.Background(), time.Minute, 10, &panicExporter, log.New(os.Stdout, "", 0))
+		sibling := exporter.NewScheduler(context.Background(), time.Minute, 10, &siblingExporter, nil)
+
+		events := newEvents(5)
+		for _, event := range events {
+			dc.AddEvent(event)
+			sibling.AddEvent(event)
+		}
+		dc.Close()
+		sibling.Close()
+
+		// the batch of the panicking exporter is dropped, the other exporter receives its batch
+		assert.Equal(t, int64(5), dc.GetDroppedEvents())
+		assert.Empty(t, panicExporter.GetExportedEvents())
+		assert.Equal(t, events, siblingExporter.GetExportedEvents())
+	})
+
+	t.Run("the next flushes are exported", func(t *testing.T) {
+		panicExporter := mock.Exporter{Bulk: true, ExpectedNumberPanic: 1}
+		dc := exporter.NewScheduler(context.Background(), 10*time.Millisecond, 1000, &panicExporter, nil)
+		go dc.StartDaemon()
+		defer dc.Close()
+
+		for _, event := range newEvents(5) {
+			dc.AddEvent(event)
+		}
+		assert.Eventually(t, func() bool { return dc.GetDroppedEvents() == 5 }, time.Second, 5*time.Millisecond)
+
+		events := newEvents(3)
+		for _, event := range events {
+			dc.AddEvent(event)
+		}
+		assert.Eventually(t, func() bool {
+			return len(panicExporter.GetExportedEvents()) == 3
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int64(5), dc.GetDroppedEvents())
+	})
+
+	t.Run("non bulk exporter", func(t *testing.T) {
+		panicExporter := mock.Exporter{Bulk: false, ExpectedNumberPanic: 1}
+		dc := exporter.NewScheduler(context.Background(), 0, 0, &panicExporter, nil)
+		defer dc.Close()
+
+		events := newEvents(2)
+		dc.AddEvent(events[0])
+		assert.Eventually(t, func() bool { return dc.GetDroppedEvents() == 1 }, time.Second, 5*time.Millisecond)
+		dc.AddEvent(events[1])
+		assert.Eventually(t, func() bool {
+			return len(panicExporter.GetExportedEvents()) == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestDataExporterScheduler_flagFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{name: "no filter exports all the flags", want: []string{"flag-a", "flag-b", "flag-c"}},
+		{name: "include list", include: []string{"flag-a", "flag-c"}, want: []string{"flag-a", "flag-c"}},
+		{name: "exclude list", exclude: []string{"flag-b"}, want: []string{"flag-a", "flag-c"}},
+		{
+			name:    "exclude wins over include",
+			include: []string{"flag-a", "flag-b"},
+			exclude: []string{"flag-b"},
+			want:    []string{"flag-a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := &mock.Exporter{Bulk: true}
+			dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 100, exp, nil,
+				exporter.WithFlagFilter(tt.include, tt.exclude))
+			for _, key := range []string{"flag-a", "flag-b", "flag-c"} {
+				dc.AddEvent(exporter.NewFeatureEvent(
+					ffcontext.NewEvaluationContextBuilder("ABCD").Build(), key, true, "enabled", false, "",
+					"SERVER"))
+			}
+			dc.Close()
+
+			got := make([]string, 0)
+			for _, event := range exp.GetExportedEvents() {
+				got = append(got, event.Key)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestDataExporterScheduler_eventBuffer(t *testing.T) {
+	t.Run("events are exported", func(t *testing.T) {
+		exp := &mock.Exporter{Bulk: true}
+		dc := exporter.NewScheduler(context.Background(), 10*time.Minute, 100, exp, nil,
+			exporter.WithEventBuffer(10))
+		for i := 0; i < 5; i++ {
+			dc.AddEvent(exporter.NewFeatureEvent(
+				ffcontext.NewEvaluationContextBuilder("ABCD").Build(), "random-key", "YO", "defaultVar", false, "",
+				"SERVER"))
+		}
+		// the events still in the buffer are exported when closing the scheduler.
+		dc.Close()
+		assert.Len(t, exp.GetExportedEvents(), 5)
+		assert.Equal(t, int64(0), dc.GetDroppedEvents())
+
+		// adding an event after closing should not panic
+		assert.NotPanics(t, func() {
+			dc.AddEvent(exporter.NewFeatureEvent(
+				ffcontext.NewEvaluationContextBuilder("ABCD").Build(), "random-key", "YO", "defaultVar", false, "",
+				"SERVER"))
+		})
+	})
+
+	t.Run("a blocked exporter does not block the caller", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		exp := &contextExporter{block: true}
+		dc := exporter.NewScheduler(ctx, 10*time.Minute, 2, exp, nil,
+			exporter.WithEventBuffer(5), exporter.WithShutdownTimeout(50*time.Millisecond))
+
+		start := time.Now()
+		for i := 0; i < 20; i++ {
+			dc.AddEvent(exporter.NewFeatureEvent(
+				ffcontext.NewEvaluationContextBuilder("ABCD").Build(), "random-key", "YO", "defaultVar", false, "",
+				"SERVER"))
+		}
+		assert.Less(t, time.Since(start), 1*time.Second)
+
+		// the export in progress holds the scheduler until the context is cancelled.
+		cancel()
+		assert.Greater(t, dc.GetDroppedEvents(), int64(0))
+		dc.Close()
+	})
+}
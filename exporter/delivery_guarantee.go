@@ -0,0 +1,14 @@
+package exporter
+
+// DeliveryGuarantee defines how the Scheduler behaves when the exporter fails to export a batch of events.
+type DeliveryGuarantee string
+
+const (
+	// DeliveryAtLeastOnce keeps the events in memory when the export fails and retries them during the next flush.
+	// The events are kept until the export succeed or until the retry buffer is full, in which case the oldest
+	// events are dropped.
+	DeliveryAtLeastOnce DeliveryGuarantee = "AT_LEAST_ONCE"
+
+	// DeliveryBestEffort drops the events if the export fails.
+	DeliveryBestEffort DeliveryGuarantee = "BEST_EFFORT"
+)
@@ -14,3 +14,20 @@ type Exporter interface {
 	// and true if we collect the data to send them in bulk.
 	IsBulk() bool
 }
+
+// BatchSizeLimiter is an optional interface an exporter can implement to limit the size of the batches it receives.
+// The Scheduler splits the events in several calls to Export, the events of each call are staying under
+// the limit once marshaled in JSON.
+// An event bigger than the limit is exported alone.
+type BatchSizeLimiter interface {
+	// GetMaxBatchBytes returns the maximum size in bytes of a batch, 0 means no limit.
+	GetMaxBatchBytes() int
+}
+
+// ContextAttributesSelector is an optional interface an exporter can implement to receive some custom
+// attributes of the evaluation context in FeatureEvent.EvaluationContext.
+// The attributes are not copied in the events if no exporter is asking for them.
+type ContextAttributesSelector interface {
+	// GetContextAttributes returns the keys of the custom attributes of the evaluation context to export.
+	GetContextAttributes() []string
+}
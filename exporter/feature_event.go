@@ -2,7 +2,6 @@ package exporter
 
 import (
 	"encoding/json"
-	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 )
@@ -16,6 +15,24 @@ func NewFeatureEvent(
 	version string,
 	source string,
 ) FeatureEvent {
+	return NewFeatureEventWithClock(RealClock{}, ctx, flagKey, value, variation, failed, version, source)
+}
+
+// NewFeatureEventWithClock creates a new FeatureEvent, the CreationDate of the event is given by the clock.
+func NewFeatureEventWithClock(
+	clock Clock,
+	ctx ffcontext.Context,
+	flagKey string,
+	value interface{},
+	variation string,
+	failed bool,
+	version string,
+	source string,
+) FeatureEvent {
+	if clock == nil {
+		clock = RealClock{}
+	}
+
 	contextKind := "user"
 	if ctx.IsAnonymous() {
 		contextKind = "anonymousUser"
@@ -25,7 +42,7 @@ func NewFeatureEvent(
 		Kind:         "feature",
 		ContextKind:  contextKind,
 		UserKey:      ctx.GetKey(),
-		CreationDate: time.Now().Unix(),
+		CreationDate: clock.Now().Unix(),
 		Key:          flagKey,
 		Variation:    variation,
 		Value:        value,
@@ -75,6 +92,36 @@ type FeatureEvent struct {
 	// Source indicates where the event was generated.
 	// This is set to SERVER when the event was evaluated in the relay-proxy and PROVIDER_CACHE when it is evaluated from the cache.
 	Source string `json:"source" example:"SERVER" parquet:"name=source, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	// Environment is the environment of the application that produced the event (see ffclient.Config.Environment).
+	// The field is omitted if no environment is configured.
+	Environment string `json:"environment,omitempty" example:"production" parquet:"name=environment, type=BYTE_ARRAY, convertedtype=UTF8"` // nolint: lll
+
+	// ConfigGeneration is the generation of the flag configuration used for the evaluation, it is incremented
+	// each time the content of the flags changes. The field is omitted if the generation is unknown.
+	ConfigGeneration int64 `json:"configGeneration,omitempty" example:"3" parquet:"name=configGeneration, type=INT64"`
+
+	// ConfigHash is the hash of the content of the flag configuration used for the evaluation.
+	// The field is omitted if the hash is unknown.
+	ConfigHash string `json:"configHash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" parquet:"name=configHash, type=BYTE_ARRAY, convertedtype=UTF8"` // nolint: lll
+
+	// SamplingWeight is the number of events represented by this event when the events are sampled
+	// (see ffclient.Config.DataCollectorSampleRate), multiply your counts by this weight to get the real volume.
+	// The field is omitted if the events are not sampled.
+	SamplingWeight float64 `json:"samplingWeight,omitempty" example:"2" parquet:"name=samplingWeight, type=DOUBLE"`
+
+	// Reason is the reason of the evaluation (TARGETING_MATCH, DEFAULT, ERROR, ...).
+	// It is not serialized, to keep the format of the exported data unchanged.
+	Reason string `json:"-"`
+
+	// Metadata contains the metadata of the flag (ex: owner, issue link, description).
+	// It is not serialized, to keep the format of the exported data unchanged.
+	Metadata map[string]interface{} `json:"-"`
+
+	// EvaluationContext contains the custom attributes of the evaluation context that produced the event.
+	// Only the attributes requested by the exporters (see ContextAttributesSelector) are set.
+	// It is never serialized, an exporter has to explicitly select the attributes it wants to export.
+	EvaluationContext map[string]interface{} `json:"-"`
 }
 
 // MarshalInterface marshals all interface type fields in FeatureEvent into JSON-encoded string.
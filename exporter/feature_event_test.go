@@ -41,6 +41,34 @@ func TestNewFeatureEvent(t *testing.T) {
 				Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
 			},
 		},
+		{
+			name: "anonymous user with builder",
+			args: args{
+				user:      ffcontext.NewEvaluationContextBuilder("ABCD").Anonymous(true).Build(),
+				flagKey:   "random-key",
+				value:     "YO",
+				variation: "Default",
+				source:    "SERVER",
+			},
+			want: exporter.FeatureEvent{
+				Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: time.Now().Unix(), Key: "random-key",
+				Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+			},
+		},
+		{
+			name: "identified user",
+			args: args{
+				user:      ffcontext.NewEvaluationContextBuilder("ABCD").Anonymous(false).Build(),
+				flagKey:   "random-key",
+				value:     "YO",
+				variation: "Default",
+				source:    "SERVER",
+			},
+			want: exporter.FeatureEvent{
+				Kind: "feature", ContextKind: "user", UserKey: "ABCD", CreationDate: time.Now().Unix(), Key: "random-key",
+				Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,6 +77,33 @@ func TestNewFeatureEvent(t *testing.T) {
 	}
 }
 
+func TestNewFeatureEventWithClock(t *testing.T) {
+	fixedDate := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		clock exporter.Clock
+		want  int64
+	}{
+		{
+			name:  "fixed clock",
+			clock: exporter.FixedClock{Time: fixedDate},
+			want:  fixedDate.Unix(),
+		},
+		{
+			name:  "nil clock should use the real clock",
+			clock: nil,
+			want:  time.Now().Unix(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exporter.NewFeatureEventWithClock(tt.clock, ffcontext.NewEvaluationContext("ABCD"), "random-key",
+				"YO", "Default", false, "", "SERVER")
+			assert.Equal(t, tt.want, got.CreationDate)
+		})
+	}
+}
+
 func TestFeatureEvent_MarshalInterface(t *testing.T) {
 	tests := []struct {
 		name         string
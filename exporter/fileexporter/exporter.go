@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/exporter"
 	"github.com/xitongsys/parquet-go-source/local"
@@ -29,10 +30,26 @@ type Exporter struct {
 
 	// Filename is the name of your output file
 	// You can use a templated config to define the name of your export files.
-	// Available replacement are {{ .Hostname}}, {{ .Timestamp}} and {{ .Format}}
+	// Available replacement are {{ .Hostname}}, {{ .Timestamp}}, {{ .Sequence}} and {{ .Format}}
+	// {{ .Sequence}} is incremented each time a new file is started when the rotation is enabled.
 	// Default: "flag-variation-{{ .Hostname}}-{{ .Timestamp}}.{{ .Format}}"
+	// or "flag-variation-{{ .Hostname}}-{{ .Timestamp}}-{{ .Sequence}}.{{ .Format}}" if the rotation is enabled.
 	Filename string
 
+	// MaxFileSize is the maximum size in bytes of an export file.
+	// When set, the events of the next exports are appended to the same file until it reaches
+	// this size, then a new file is started.
+	// This field is ignored if you are using the Parquet format.
+	// Default: 0, no rotation by size
+	MaxFileSize int64
+
+	// RotateInterval is the maximum duration during which events are appended to the same file.
+	// When set, the events of the next exports are appended to the same file until this interval
+	// is elapsed, then a new file is started.
+	// This field is ignored if you are using the Parquet format.
+	// Default: 0, no rotation by time
+	RotateInterval time.Duration
+
 	// CsvTemplate is used if your output format is CSV.
 	// This field will be ignored if you are using another format than CSV.
 	// You can decide which fields you want in your CSV line with a go-template syntax,
@@ -42,6 +59,19 @@ type Exporter struct {
 	// {{ .Default}};{{ .Source}}\n
 	CsvTemplate string
 
+	// CsvColumns is the list of columns to export if your output format is CSV
+	// (ex: []string{"kind", "userKey", "key", "variation", "value", "creationDate"}).
+	// If set, CsvTemplate is ignored, a header row is written at the beginning of the file and
+	// non-scalar values are JSON-encoded.
+	// Available columns are the JSON names of the fields in exporter/feature_event.go.
+	// Default: nil, CsvTemplate is used
+	CsvColumns []string
+
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
 	// ParquetCompressionCodec is the parquet compression codec for better space efficiency.
 	// Available options https://github.com/apache/parquet-format/blob/master/Compression.md
 	// Default: SNAPPY
@@ -50,6 +80,18 @@ type Exporter struct {
 	csvTemplate      *template.Template
 	filenameTemplate *template.Template
 	initTemplates    sync.Once
+
+	// rotation is the state of the file currently written when the rotation is enabled.
+	rotation rotation
+}
+
+// rotation keeps track of the file currently written when the rotation is enabled.
+type rotation struct {
+	mutex    sync.Mutex
+	filePath string
+	size     int64
+	openedAt time.Time
+	sequence int
 }
 
 // Export is saving a collection of events in a file.
@@ -57,7 +99,11 @@ func (f *Exporter) Export(_ context.Context, _ *log.Logger, featureEvents []expo
 	// Parse the template only once
 	f.initTemplates.Do(func() {
 		f.csvTemplate = exporter.ParseTemplate("csvFormat", f.CsvTemplate, exporter.DefaultCsvTemplate)
-		f.filenameTemplate = exporter.ParseTemplate("filenameFormat", f.Filename, exporter.DefaultFilenameTemplate)
+		defaultFilenameTemplate := exporter.DefaultFilenameTemplate
+		if f.isRotationEnabled() {
+			defaultFilenameTemplate = exporter.DefaultRotatingFilenameTemplate
+		}
+		f.filenameTemplate = exporter.ParseTemplate("filenameFormat", f.Filename, defaultFilenameTemplate)
 	})
 
 	// Default format for the output
@@ -66,6 +112,10 @@ func (f *Exporter) Export(_ context.Context, _ *log.Logger, featureEvents []expo
 	}
 	f.Format = strings.ToLower(f.Format)
 
+	if f.Format != "parquet" && f.isRotationEnabled() {
+		return f.writeRotatingFile(featureEvents)
+	}
+
 	// Get the filename
 	filename, err := exporter.ComputeFilename(f.filenameTemplate, f.Format)
 	if err != nil {
@@ -92,32 +142,156 @@ func (f *Exporter) writeFile(filePath string, featureEvents []exporter.FeatureEv
 		return err
 	}
 	defer file.Close()
+
+	if f.Format == "csv" && len(f.CsvColumns) > 0 {
+		return f.writeCSVColumns(file, featureEvents)
+	}
+
 	for _, event := range featureEvents {
-		var line []byte
-		var err error
+		line, err := f.formatEvent(event)
+		if err != nil {
+			return err
+		}
+		_, errWrite := file.Write(line)
+		if errWrite != nil {
+			return fmt.Errorf("error while writing the export file: %v", err)
+		}
+	}
+	return nil
+}
 
-		// Convert the line in the right format
-		switch f.Format {
-		case "csv":
+// formatEvent converts the event in a line of the export file in the right format.
+func (f *Exporter) formatEvent(event exporter.FeatureEvent) ([]byte, error) {
+	var line []byte
+	var err error
+	switch f.Format {
+	case "csv":
+		if len(f.CsvColumns) > 0 {
+			line, err = exporter.FormatEventsInCSVColumns(f.CsvColumns, []exporter.FeatureEvent{event}, false)
+		} else {
 			line, err = exporter.FormatEventInCSV(f.csvTemplate, event)
-		case "json":
-			line, err = exporter.FormatEventInJSON(event)
-		default:
-			line, err = exporter.FormatEventInJSON(event)
 		}
+	case "json":
+		line, err = exporter.FormatEventInJSONWithStyle(event, f.FieldNameStyle)
+	default:
+		line, err = exporter.FormatEventInJSONWithStyle(event, f.FieldNameStyle)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("impossible to format the event in %s: %v", f.Format, err)
+	}
+	return line, nil
+}
+
+// isRotationEnabled returns true if the events should be appended to the same file until
+// MaxFileSize or RotateInterval is reached.
+func (f *Exporter) isRotationEnabled() bool {
+	return f.MaxFileSize > 0 || f.RotateInterval > 0
+}
+
+// writeRotatingFile appends the events to the current file and starts a new file
+// each time MaxFileSize or RotateInterval is reached.
+// The rotation happens between 2 events, so an event is never split across 2 files.
+func (f *Exporter) writeRotatingFile(featureEvents []exporter.FeatureEvent) error {
+	f.rotation.mutex.Lock()
+	defer f.rotation.mutex.Unlock()
+
+	var file *os.File
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+		}
+	}()
 
-		// Handle error and write line into the file
+	for _, event := range featureEvents {
+		line, err := f.formatEvent(event)
 		if err != nil {
-			return fmt.Errorf("impossible to format the event in %s: %v", f.Format, err)
+			return err
 		}
-		_, errWrite := file.Write(line)
-		if errWrite != nil {
+
+		if f.shouldRotate(int64(len(line))) {
+			if file != nil {
+				if err := file.Close(); err != nil {
+					return fmt.Errorf("error while closing the export file: %v", err)
+				}
+				file = nil
+			}
+			if err := f.startNewFile(); err != nil {
+				return err
+			}
+		}
+
+		if file == nil {
+			file, err = os.OpenFile(f.rotation.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				return err
+			}
+		}
+
+		if f.Format == "csv" && len(f.CsvColumns) > 0 && f.rotation.size == 0 {
+			header, err := exporter.FormatEventsInCSVColumns(f.CsvColumns, nil, true)
+			if err != nil {
+				return fmt.Errorf("impossible to format the events in csv: %v", err)
+			}
+			line = append(header, line...)
+		}
+
+		written, err := file.Write(line)
+		f.rotation.size += int64(written)
+		if err != nil {
 			return fmt.Errorf("error while writing the export file: %v", err)
 		}
 	}
 	return nil
 }
 
+// shouldRotate returns true if a new file should be started before writing a line of lineSize bytes.
+func (f *Exporter) shouldRotate(lineSize int64) bool {
+	if f.rotation.filePath == "" {
+		return true
+	}
+	if f.RotateInterval > 0 && time.Since(f.rotation.openedAt) >= f.RotateInterval {
+		return true
+	}
+	// a file always contains at least 1 event, even if this event is bigger than MaxFileSize.
+	return f.MaxFileSize > 0 && f.rotation.size > 0 && f.rotation.size+lineSize > f.MaxFileSize
+}
+
+// startNewFile computes the name of the next file and resets the state of the rotation.
+func (f *Exporter) startNewFile() error {
+	f.rotation.sequence++
+	filename, err := exporter.ComputeFilenameWithSequence(f.filenameTemplate, f.Format, f.rotation.sequence)
+	if err != nil {
+		return err
+	}
+	f.rotation.filePath = f.OutputDir + "/" + filename
+	f.rotation.openedAt = time.Now()
+	f.rotation.size = 0
+	// if the file already exists, we continue to append to it.
+	if info, err := os.Stat(f.rotation.filePath); err == nil {
+		f.rotation.size = info.Size()
+	}
+	return nil
+}
+
+// writeCSVColumns writes the events in CSV using the columns configured,
+// the header row is written only if the file is empty.
+func (f *Exporter) writeCSVColumns(file *os.File, featureEvents []exporter.FeatureEvent) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	content, err := exporter.FormatEventsInCSVColumns(f.CsvColumns, featureEvents, info.Size() == 0)
+	if err != nil {
+		return fmt.Errorf("impossible to format the events in csv: %v", err)
+	}
+	if _, err := file.Write(content); err != nil {
+		return fmt.Errorf("error while writing the export file: %v", err)
+	}
+	return nil
+}
+
+// writeParquet writes the events in a Parquet file, the value of the events is JSON-encoded.
+// The row groups are written by WriteStop, so each export produces a complete file.
 func (f *Exporter) writeParquet(filePath string, featureEvents []exporter.FeatureEvent) error {
 	fw, err := local.NewLocalFileWriter(filePath)
 	if err != nil {
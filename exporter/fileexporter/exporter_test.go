@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thomaspoignant/go-feature-flag/exporter"
@@ -346,6 +348,90 @@ func TestFile_Export(t *testing.T) {
 	}
 }
 
+func TestFile_ExportCSVColumns(t *testing.T) {
+	outputDir, _ := os.MkdirTemp("", "fileExporter")
+	defer os.RemoveAll(outputDir)
+
+	f := &fileexporter.Exporter{
+		Format:     "csv",
+		OutputDir:  outputDir,
+		Filename:   "flag-variation.{{ .Format}}",
+		CsvColumns: []string{"kind", "userKey", "key", "variation", "value", "creationDate"},
+	}
+	events := []exporter.FeatureEvent{
+		{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+			Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+		},
+		{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: "EFGH", CreationDate: 1617970701, Key: "random-key",
+			Variation: "Default", Value: []interface{}{"a", "b"}, Default: false, Source: "SERVER",
+		},
+	}
+
+	// we export twice in the same file, the header should be written only once.
+	assert.NoError(t, f.Export(context.Background(), nil, events[:1]))
+	assert.NoError(t, f.Export(context.Background(), nil, events[1:]))
+
+	expectedContent, _ := os.ReadFile("./testdata/csv_columns.csv")
+	gotContent, err := os.ReadFile(outputDir + "/flag-variation.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expectedContent), string(gotContent))
+}
+
+func TestFile_ExportRotationBySize(t *testing.T) {
+	outputDir, _ := os.MkdirTemp("", "fileExporter")
+	defer os.RemoveAll(outputDir)
+
+	event := exporter.FeatureEvent{
+		Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+		Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+	}
+	line, err := exporter.FormatEventInJSON(event)
+	assert.NoError(t, err)
+
+	f := &fileexporter.Exporter{
+		OutputDir:   outputDir,
+		Filename:    "flag-variation-{{ .Sequence}}.{{ .Format}}",
+		MaxFileSize: int64(3 * len(line)),
+	}
+	// the first export fills the first file, the second export does not fit and starts a new file.
+	assert.NoError(t, f.Export(context.Background(), nil, []exporter.FeatureEvent{event, event}))
+	assert.NoError(t, f.Export(context.Background(), nil, []exporter.FeatureEvent{event, event, event}))
+
+	files, _ := os.ReadDir(outputDir)
+	assert.Equal(t, 2, len(files), "Directory %s should have 2 files", outputDir)
+
+	firstFile, err := os.ReadFile(outputDir + "/flag-variation-1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat(string(line), 3), string(firstFile))
+	secondFile, err := os.ReadFile(outputDir + "/flag-variation-2.json")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat(string(line), 2), string(secondFile))
+}
+
+func TestFile_ExportRotationByInterval(t *testing.T) {
+	outputDir, _ := os.MkdirTemp("", "fileExporter")
+	defer os.RemoveAll(outputDir)
+
+	event := exporter.FeatureEvent{
+		Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+		Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+	}
+	f := &fileexporter.Exporter{
+		OutputDir:      outputDir,
+		Filename:       "flag-variation-{{ .Sequence}}.{{ .Format}}",
+		RotateInterval: 50 * time.Millisecond,
+	}
+	assert.NoError(t, f.Export(context.Background(), nil, []exporter.FeatureEvent{event}))
+	assert.NoError(t, f.Export(context.Background(), nil, []exporter.FeatureEvent{event}))
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, f.Export(context.Background(), nil, []exporter.FeatureEvent{event}))
+
+	files, _ := os.ReadDir(outputDir)
+	assert.Equal(t, 2, len(files), "Directory %s should have 2 files", outputDir)
+}
+
 func TestFile_IsBulk(t *testing.T) {
 	exporter := fileexporter.Exporter{}
 	assert.True(t, exporter.IsBulk(), "Exporter exporter is a bulk exporter")
@@ -0,0 +1,63 @@
+package gcstorageexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// maxComposeSources is the maximum number of source objects of a single Google Cloud Storage composition.
+const maxComposeSources = 32
+
+// composePending appends all the objects of the pending directory at the end of the destination object
+// (in the alphabetical order of their names) and deletes them.
+// Since a composition is limited to 32 sources, the objects are composed by chunks, the destination object
+// being the first source of each chunk.
+func composePending(ctx context.Context, bucket *storage.BucketHandle, destination string, pendingDir string) error {
+	pending := make([]string, 0)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: pendingDir + "/"})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("impossible to list the pending objects: %v", err)
+		}
+		pending = append(pending, attrs.Name)
+	}
+
+	dst := bucket.Object(destination)
+	_, err := dst.Attrs(ctx)
+	destinationExists := err == nil
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return err
+	}
+
+	for len(pending) > 0 {
+		sources := make([]*storage.ObjectHandle, 0, maxComposeSources)
+		if destinationExists {
+			sources = append(sources, dst)
+		}
+		chunk := pending[:min(maxComposeSources-len(sources), len(pending))]
+		for _, name := range chunk {
+			sources = append(sources, bucket.Object(name))
+		}
+		if _, err := dst.ComposerFrom(sources...).Run(ctx); err != nil {
+			return err
+		}
+		destinationExists = true
+
+		// the composed objects are deleted right away to never append them twice
+		for _, name := range chunk {
+			if err := bucket.Object(name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+				return fmt.Errorf("impossible to delete the composed object %s: %v", name, err)
+			}
+		}
+		pending = pending[len(chunk):]
+	}
+	return nil
+}
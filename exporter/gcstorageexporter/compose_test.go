@@ -0,0 +1,98 @@
+package gcstorageexporter_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/exporter/gcstorageexporter"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
+func TestGoogleStorage_ExportComposeDaily(t *testing.T) {
+	hostname, _ := os.Hostname()
+	server := fakestorage.NewServer(nil)
+	defer server.Stop()
+	server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "test"})
+
+	e := gcstorageexporter.Exporter{
+		Bucket:       "test",
+		Path:         "random/path",
+		Format:       "csv",
+		CsvTemplate:  "{{ .Key}};{{ .Value}}\n",
+		ComposeDaily: true,
+		Options: []option.ClientOption{
+			option.WithCredentials(&google.Credentials{}),
+			option.WithHTTPClient(server.HTTPClient()),
+		},
+	}
+
+	// listObjects returns the content of all the objects of the bucket.
+	listObjects := func() map[string]string {
+		objects, _, err := server.ListObjectsWithOptions("test", fakestorage.ListOptions{})
+		require.NoError(t, err)
+		contents := map[string]string{}
+		for _, o := range objects {
+			object, err := server.GetObject("test", o.Name)
+			require.NoError(t, err)
+			contents[o.Name] = string(object.Content)
+		}
+		return contents
+	}
+
+	// each flush is appended at the end of the daily object
+	want := ""
+	for i := 0; i < 3; i++ {
+		err := e.Export(context.Background(), log.New(os.Stdout, "", 0), []exporter.FeatureEvent{
+			{Kind: "feature", Key: fmt.Sprintf("flag-%d", i), Value: "A"},
+			{Kind: "feature", Key: fmt.Sprintf("flag-%d", i), Value: "B"},
+		})
+		require.NoError(t, err)
+		want += fmt.Sprintf("flag-%d;A\nflag-%d;B\n", i, i)
+	}
+	objects := listObjects()
+	require.Len(t, objects, 1)
+	var dailyObject string
+	for name, content := range objects {
+		dailyObject = name
+		assert.Regexp(t,
+			regexp.MustCompile("^random/path/[0-9]{4}-[0-9]{2}-[0-9]{2}/flag-variation-"+hostname+"\\.csv$"), name)
+		assert.Equal(t, want, content)
+	}
+
+	// more than 32 pending objects are composed by chunks
+	pendingDir := strings.TrimSuffix(dailyObject, ".csv")
+	pendingDir = strings.Replace(pendingDir, "flag-variation-", "pending-", 1)
+	for i := 0; i < 40; i++ {
+		content := fmt.Sprintf("pending-%d;A\n", i)
+		server.CreateObject(fakestorage.Object{
+			ObjectAttrs: fakestorage.ObjectAttrs{BucketName: "test", Name: fmt.Sprintf("%s/%020d-batch.csv", pendingDir, i)},
+			Content:     []byte(content),
+		})
+		want += content
+	}
+	err := e.Export(context.Background(), nil, []exporter.FeatureEvent{{Kind: "feature", Key: "last-flag", Value: "A"}})
+	require.NoError(t, err)
+	want += "last-flag;A\n"
+	assert.Equal(t, map[string]string{dailyObject: want}, listObjects())
+}
+
+func TestGoogleStorage_ExportComposeDailyParquet(t *testing.T) {
+	e := gcstorageexporter.Exporter{
+		Bucket:       "test",
+		Format:       "parquet",
+		ComposeDaily: true,
+		Options:      []option.ClientOption{option.WithCredentials(&google.Credentials{})},
+	}
+	err := e.Export(context.Background(), nil, []exporter.FeatureEvent{{Kind: "feature", Key: "flag", Value: "A"}})
+	assert.ErrorContains(t, err, "ComposeDaily is not available with the parquet format")
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/exporter"
 	"github.com/thomaspoignant/go-feature-flag/exporter/fileexporter"
@@ -49,6 +51,24 @@ type Exporter struct {
 	// Available options https://github.com/apache/parquet-format/blob/master/Compression.md
 	// Default: SNAPPY
 	ParquetCompressionCodec string
+
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
+	// DatePartitionedPath (optional) adds the day of the export (UTC) in the path of the files,
+	// so the files are stored in {{ Path}}/YYYY-MM-DD/ and can be listed by day.
+	// Default: false
+	DatePartitionedPath bool
+
+	// ComposeDaily (optional) appends the data of each flush into a single object per day
+	// instead of creating a new object every time, using Google Cloud Storage object composition.
+	// The daily object is {{ Path}}/YYYY-MM-DD/flag-variation-{{ Hostname}}.{{ Format}}, the files of
+	// the flush are uploaded in a pending folder next to it and deleted once composed.
+	// This mode is not available with the Parquet format since parquet files can't be concatenated.
+	// Default: false
+	ComposeDaily bool
 }
 
 func (f *Exporter) IsBulk() bool {
@@ -67,6 +87,17 @@ func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents
 		return fmt.Errorf("you should specify a bucket. %v is invalid", f.Bucket)
 	}
 
+	format := strings.ToLower(f.Format)
+	if format == "" {
+		format = "json"
+	}
+	if f.ComposeDaily && format == "parquet" {
+		return fmt.Errorf("ComposeDaily is not available with the parquet format")
+	}
+
+	now := time.Now().UTC()
+	directory, dailyObject, pendingDir := f.exportPaths(now, format)
+
 	// Create a temp directory to store the file we will produce
 	outputDir, err := os.MkdirTemp("", "go_feature_flag_GoogleCloudStorage_export")
 	if err != nil {
@@ -82,6 +113,7 @@ func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents
 		Filename:                f.Filename,
 		CsvTemplate:             f.CsvTemplate,
 		ParquetCompressionCodec: f.ParquetCompressionCodec,
+		FieldNameStyle:          f.FieldNameStyle,
 	}
 	err = fileExporter.Export(ctx, logger, featureEvents)
 	if err != nil {
@@ -95,28 +127,70 @@ func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents
 	}
 
 	for _, file := range files {
-		// read file
-		of, err := os.Open(outputDir + "/" + file.Name())
-		if err != nil {
-			fflog.Printf(logger, "error: [Exporter] impossible to open the file %s/%s", outputDir, file.Name())
-			continue
-		}
-
 		// prepend the path
-		source := file.Name()
-		if f.Path != "" {
-			source = f.Path + "/" + file.Name()
+		source := joinPath(directory, file.Name())
+		if f.ComposeDaily {
+			// the pending files are prefixed by the upload time to be composed in order
+			source = joinPath(pendingDir, fmt.Sprintf("%020d-%s", now.UnixNano(), file.Name()))
+		}
+		if err := f.uploadFile(ctx, logger, client.Bucket(f.Bucket), outputDir, file.Name(), source); err != nil {
+			return err
 		}
+	}
 
-		wc := client.Bucket(f.Bucket).Object(source).NewWriter(ctx)
-		_, err = io.Copy(wc, of)
-		_ = wc.Close()
-		if err != nil {
-			return fmt.Errorf("error: [Exporter] impossible to copy the file from %s to bucket %s: %v",
-				source, f.Bucket, err)
+	if f.ComposeDaily {
+		// The data is already safely stored in the pending folder, if the composition fails
+		// the pending files will be composed during the next export.
+		if err := composePending(ctx, client.Bucket(f.Bucket), dailyObject, pendingDir); err != nil {
+			fflog.Printf(logger, "error: [Exporter] impossible to compose the files of %s into %s: %v",
+				pendingDir, dailyObject, err)
 		}
-		fflog.Printf(logger, "info: [Exporter] file %s uploaded.", file.Name())
 	}
+	return nil
+}
 
+// exportPaths returns the folder of the bucket where the files are uploaded, and when ComposeDaily is
+// enabled the name of the daily object and the folder where the files are pending before being composed.
+func (f *Exporter) exportPaths(now time.Time, format string) (directory, dailyObject, pendingDir string) {
+	directory = f.Path
+	if f.DatePartitionedPath || f.ComposeDaily {
+		directory = joinPath(directory, now.Format("2006-01-02"))
+	}
+	if !f.ComposeDaily {
+		return directory, "", directory
+	}
+	hostname, _ := os.Hostname()
+	dailyObject = joinPath(directory, fmt.Sprintf("flag-variation-%s.%s", hostname, format))
+	pendingDir = joinPath(directory, fmt.Sprintf("pending-%s", hostname))
+	return directory, dailyObject, pendingDir
+}
+
+// uploadFile uploads the file of the outputDir to the object source of the bucket.
+// A file that can't be opened is skipped.
+func (f *Exporter) uploadFile(ctx context.Context, logger *log.Logger, bucket *storage.BucketHandle,
+	outputDir string, fileName string, source string) error {
+	of, err := os.Open(outputDir + "/" + fileName)
+	if err != nil {
+		fflog.Printf(logger, "error: [Exporter] impossible to open the file %s/%s", outputDir, fileName)
+		return nil
+	}
+	defer func() { _ = of.Close() }()
+
+	wc := bucket.Object(source).NewWriter(ctx)
+	_, err = io.Copy(wc, of)
+	_ = wc.Close()
+	if err != nil {
+		return fmt.Errorf("error: [Exporter] impossible to copy the file from %s to bucket %s: %v",
+			source, f.Bucket, err)
+	}
+	fflog.Printf(logger, "info: [Exporter] file %s uploaded.", fileName)
 	return nil
 }
+
+// joinPath joins the directory and the name of an object of the bucket.
+func joinPath(directory string, name string) string {
+	if directory == "" {
+		return name
+	}
+	return directory + "/" + name
+}
@@ -2,7 +2,6 @@ package kafkaexporter
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/IBM/sarama"
 	"github.com/thomaspoignant/go-feature-flag/exporter"
@@ -37,6 +36,11 @@ type Exporter struct {
 	// no sarama.Config is provided a sensible default will be used.
 	Settings Settings
 
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
 	sender MessageSender
 	// dialer will create the producer. This field is added for dependency injection during testing as sarama
 	// has the annoying tendency to dial as soon as a producer is created.
@@ -112,6 +116,6 @@ func (e *Exporter) formatMessage(event exporter.FeatureEvent) ([]byte, error) {
 	case formatJSON:
 		fallthrough
 	default:
-		return json.Marshal(event)
+		return exporter.MarshalEventInJSON(event, e.FieldNameStyle)
 	}
 }
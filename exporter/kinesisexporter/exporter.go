@@ -0,0 +1,154 @@
+package kinesisexporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+)
+
+const (
+	// maxRecordsPerRequest is the maximum number of records accepted by a PutRecords call.
+	maxRecordsPerRequest = 500
+
+	// defaultMaxRetries is the number of times we retry the records rejected by Kinesis.
+	defaultMaxRetries = 3
+)
+
+type Exporter struct {
+	// StreamName is the name of your Kinesis Data Stream
+	// (mandatory)
+	StreamName string
+
+	// Region is the AWS region of your Kinesis Data Stream.
+	// It is used only if AwsConfig is not set.
+	// (optional) Default: the region of the default AWS configuration.
+	Region string
+
+	// AwsConfig is the AWS SDK configuration object we will use to
+	// send the events to Kinesis.
+	// (optional) Default: the default AWS configuration.
+	AwsConfig *aws.Config
+
+	// MaxRetries is the number of times we retry to send the records rejected by Kinesis
+	// (ex: throttled records) before returning an error.
+	// (optional) Default: 3
+	MaxRetries int
+
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// (optional) Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
+	init           sync.Once
+	kinesisService KinesisPutRecordsAPI
+}
+
+// Export is sending the featureEvents to Kinesis in batches of 500 records.
+// Each record is partitioned by the user key of the event.
+func (f *Exporter) Export(ctx context.Context, _ *log.Logger, featureEvents []exporter.FeatureEvent) error {
+	if f.StreamName == "" {
+		return fmt.Errorf("impossible to init Kinesis exporter: StreamName is a mandatory parameter")
+	}
+
+	if f.AwsConfig == nil {
+		var opts []func(*config.LoadOptions) error
+		if f.Region != "" {
+			opts = append(opts, config.WithRegion(f.Region))
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return fmt.Errorf("impossible to init Kinesis exporter: %v", err)
+		}
+		f.AwsConfig = &cfg
+	}
+
+	if f.kinesisService == nil {
+		f.init.Do(func() {
+			f.kinesisService = kinesis.NewFromConfig(*f.AwsConfig)
+		})
+	}
+
+	records := make([]types.PutRecordsRequestEntry, 0, len(featureEvents))
+	for _, event := range featureEvents {
+		data, err := exporter.MarshalEventInJSON(event, f.FieldNameStyle)
+		if err != nil {
+			return err
+		}
+		records = append(records, types.PutRecordsRequestEntry{
+			Data:         data,
+			PartitionKey: aws.String(event.UserKey),
+		})
+	}
+
+	for start := 0; start < len(records); start += maxRecordsPerRequest {
+		end := start + maxRecordsPerRequest
+		if end > len(records) {
+			end = len(records)
+		}
+		if err := f.putRecords(ctx, records[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putRecords is sending one batch of records to Kinesis and retries the records that
+// have been rejected until MaxRetries is reached.
+func (f *Exporter) putRecords(ctx context.Context, records []types.PutRecordsRequestEntry) error {
+	maxRetries := f.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		output, err := f.kinesisService.PutRecords(ctx, &kinesis.PutRecordsInput{
+			Records:    records,
+			StreamName: aws.String(f.StreamName),
+		})
+		if err != nil {
+			return err
+		}
+		if output == nil || aws.ToInt32(output.FailedRecordCount) == 0 {
+			return nil
+		}
+
+		// The response contains one result per record in the same order as the request,
+		// the failed records are the ones with an error code.
+		failed := make([]types.PutRecordsRequestEntry, 0, aws.ToInt32(output.FailedRecordCount))
+		var lastErrorMessage string
+		for index, result := range output.Records {
+			if result.ErrorCode != nil && index < len(records) {
+				failed = append(failed, records[index])
+				lastErrorMessage = aws.ToString(result.ErrorMessage)
+			}
+		}
+		if len(failed) == 0 {
+			return nil
+		}
+		if attempt >= maxRetries {
+			return fmt.Errorf("impossible to send %d records to Kinesis after %d retries: %s",
+				len(failed), maxRetries, lastErrorMessage)
+		}
+		records = failed
+	}
+}
+
+// IsBulk return true, we are sending the events in batches to Kinesis.
+func (f *Exporter) IsBulk() bool {
+	return true
+}
+
+// KinesisPutRecordsAPI defines the interface for the PutRecords function.
+// We use this interface to test the functions using a mocked service.
+type KinesisPutRecordsAPI interface {
+	PutRecords(ctx context.Context,
+		params *kinesis.PutRecordsInput,
+		optFns ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error)
+}
@@ -0,0 +1,177 @@
+package kinesisexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+)
+
+type KinesisPutRecordsAPIMock struct {
+	// calls contains the records received for each call of PutRecords.
+	calls [][]types.PutRecordsRequestEntry
+	// failingPartitionKeys contains the number of times a partition key should be rejected.
+	failingPartitionKeys map[string]int
+	err                  error
+}
+
+func (k *KinesisPutRecordsAPIMock) PutRecords(_ context.Context,
+	params *kinesis.PutRecordsInput,
+	_ ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	k.calls = append(k.calls, params.Records)
+
+	output := &kinesis.PutRecordsOutput{FailedRecordCount: aws.Int32(0)}
+	for _, record := range params.Records {
+		key := aws.ToString(record.PartitionKey)
+		if k.failingPartitionKeys[key] > 0 {
+			k.failingPartitionKeys[key]--
+			output.FailedRecordCount = aws.Int32(aws.ToInt32(output.FailedRecordCount) + 1)
+			output.Records = append(output.Records, types.PutRecordsResultEntry{
+				ErrorCode:    aws.String("ProvisionedThroughputExceededException"),
+				ErrorMessage: aws.String("Rate exceeded"),
+			})
+			continue
+		}
+		output.Records = append(output.Records, types.PutRecordsResultEntry{
+			SequenceNumber: aws.String("1"),
+			ShardId:        aws.String("shardId-000000000000"),
+		})
+	}
+	return output, nil
+}
+
+func generateEvents(count int) []exporter.FeatureEvent {
+	events := make([]exporter.FeatureEvent, 0, count)
+	for i := 0; i < count; i++ {
+		events = append(events, exporter.FeatureEvent{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: fmt.Sprintf("user-%d", i),
+			CreationDate: 1617970547, Key: "random-key", Variation: "Default", Value: "YO", Default: false,
+		})
+	}
+	return events
+}
+
+func TestKinesis_IsBulk(t *testing.T) {
+	exporter := Exporter{}
+	assert.True(t, exporter.IsBulk(), "Kinesis exporter is a bulk exporter")
+}
+
+func TestExporter_Export(t *testing.T) {
+	tests := []struct {
+		name                 string
+		streamName           string
+		maxRetries           int
+		featureEvents        []exporter.FeatureEvent
+		kinesisService       *KinesisPutRecordsAPIMock
+		wantErr              assert.ErrorAssertionFunc
+		wantCallsRecordCount []int
+	}{
+		{
+			name:           "should return an error if no StreamName provided",
+			featureEvents:  generateEvents(1),
+			kinesisService: &KinesisPutRecordsAPIMock{},
+			wantErr:        assert.Error,
+		},
+		{
+			name:                 "should send all the events in one call",
+			streamName:           "test-stream",
+			featureEvents:        generateEvents(3),
+			kinesisService:       &KinesisPutRecordsAPIMock{},
+			wantErr:              assert.NoError,
+			wantCallsRecordCount: []int{3},
+		},
+		{
+			name:                 "should split the events in batches of 500 records",
+			streamName:           "test-stream",
+			featureEvents:        generateEvents(1201),
+			kinesisService:       &KinesisPutRecordsAPIMock{},
+			wantErr:              assert.NoError,
+			wantCallsRecordCount: []int{500, 500, 201},
+		},
+		{
+			name:          "should retry only the failed records",
+			streamName:    "test-stream",
+			featureEvents: generateEvents(5),
+			kinesisService: &KinesisPutRecordsAPIMock{
+				failingPartitionKeys: map[string]int{"user-1": 2, "user-3": 1},
+			},
+			wantErr:              assert.NoError,
+			wantCallsRecordCount: []int{5, 2, 1},
+		},
+		{
+			name:          "should return an error if the records are still failing after the retries",
+			streamName:    "test-stream",
+			maxRetries:    2,
+			featureEvents: generateEvents(2),
+			kinesisService: &KinesisPutRecordsAPIMock{
+				failingPartitionKeys: map[string]int{"user-0": 10},
+			},
+			wantErr:              assert.Error,
+			wantCallsRecordCount: []int{2, 1, 1},
+		},
+		{
+			name:                 "should return an error if Kinesis is returning an error",
+			streamName:           "test-stream",
+			featureEvents:        generateEvents(2),
+			kinesisService:       &KinesisPutRecordsAPIMock{err: fmt.Errorf("random error")},
+			wantErr:              assert.Error,
+			wantCallsRecordCount: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Exporter{
+				StreamName:     tt.streamName,
+				AwsConfig:      &aws.Config{},
+				MaxRetries:     tt.maxRetries,
+				kinesisService: tt.kinesisService,
+			}
+
+			logger := log.New(os.Stdout, "", 0)
+			err := f.Export(context.TODO(), logger, tt.featureEvents)
+			tt.wantErr(t, err)
+			if tt.wantCallsRecordCount == nil {
+				return
+			}
+
+			gotCallsRecordCount := make([]int, 0, len(tt.kinesisService.calls))
+			for _, call := range tt.kinesisService.calls {
+				gotCallsRecordCount = append(gotCallsRecordCount, len(call))
+			}
+			assert.Equal(t, tt.wantCallsRecordCount, gotCallsRecordCount)
+		})
+	}
+}
+
+func TestExporter_Export_records(t *testing.T) {
+	kinesisService := &KinesisPutRecordsAPIMock{}
+	f := &Exporter{
+		StreamName:     "test-stream",
+		AwsConfig:      &aws.Config{},
+		kinesisService: kinesisService,
+	}
+	events := generateEvents(2)
+	err := f.Export(context.TODO(), log.New(os.Stdout, "", 0), events)
+	assert.NoError(t, err)
+
+	want := make([]types.PutRecordsRequestEntry, 0, len(events))
+	for _, event := range events {
+		data, _ := json.Marshal(event)
+		want = append(want, types.PutRecordsRequestEntry{
+			Data:         data,
+			PartitionKey: aws.String(event.UserKey),
+		})
+	}
+	assert.Equal(t, [][]types.PutRecordsRequestEntry{want}, kinesisService.calls)
+}
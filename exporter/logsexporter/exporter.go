@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"log"
+	"log/slog"
 	"sync"
 	"text/template"
 	"time"
@@ -26,12 +27,35 @@ type Exporter struct {
 	// Default: [{{ .FormattedDate}}] user="{{ .UserKey}}", flag="{{ .Key}}", value="{{ .Value}}"
 	LogFormat string
 
+	// SlogLogger (optional) if set, the events are logged as structured logs with this logger
+	// instead of using the LogFormat template.
+	// Each event is logged with the attributes flag.key, user.key, variation, value, default, version, source
+	// and creationDate.
+	// Default: nil
+	SlogLogger *slog.Logger
+
 	logTemplate   *template.Template
 	initTemplates sync.Once
 }
 
 // Export is saving a collection of events in a file.
-func (f *Exporter) Export(_ context.Context, logger *log.Logger, featureEvents []exporter.FeatureEvent) error {
+func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents []exporter.FeatureEvent) error {
+	if f.SlogLogger != nil {
+		for _, event := range featureEvents {
+			f.SlogLogger.LogAttrs(ctx, slog.LevelInfo, "feature flag evaluation",
+				slog.String("flag.key", event.Key),
+				slog.String("user.key", event.UserKey),
+				slog.String("variation", event.Variation),
+				slog.Any("value", event.Value),
+				slog.Bool("default", event.Default),
+				slog.String("version", event.Version),
+				slog.String("source", event.Source),
+				slog.Time("creationDate", time.Unix(event.CreationDate, 0)),
+			)
+		}
+		return nil
+	}
+
 	f.initTemplates.Do(func() {
 		// Remove below after deprecation of Format
 		if f.LogFormat == "" && f.Format != "" {
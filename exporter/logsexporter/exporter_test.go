@@ -1,8 +1,11 @@
 package logsexporter_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log"
+	"log/slog"
 	"os"
 	"testing"
 
@@ -104,6 +107,33 @@ func TestLog_Export(t *testing.T) {
 	}
 }
 
+func TestLog_ExportWithSlog(t *testing.T) {
+	var output bytes.Buffer
+	exp := logsexporter.Exporter{
+		SlogLogger: slog.New(slog.NewJSONHandler(&output, nil)),
+	}
+	err := exp.Export(context.Background(), log.New(os.Stdout, "", 0), []exporter.FeatureEvent{
+		{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+			Variation: "Default", Value: "YO", Default: false, Source: "SERVER", Version: "1.0.0",
+		},
+	})
+	assert.NoError(t, err)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "feature flag evaluation", record["msg"])
+	assert.Equal(t, "random-key", record["flag.key"])
+	assert.Equal(t, "ABCD", record["user.key"])
+	assert.Equal(t, "Default", record["variation"])
+	assert.Equal(t, "YO", record["value"])
+	assert.Equal(t, false, record["default"])
+	assert.Equal(t, "1.0.0", record["version"])
+	assert.Equal(t, "SERVER", record["source"])
+	assert.Contains(t, record, "creationDate")
+}
+
 func TestLog_IsBulk(t *testing.T) {
 	exporter := logsexporter.Exporter{}
 	assert.False(t, exporter.IsBulk(), "File exporter is not a bulk exporter")
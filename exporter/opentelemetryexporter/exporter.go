@@ -0,0 +1,205 @@
+package opentelemetryexporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the name of the OpenTelemetry tracer used by the exporter.
+	tracerName = "github.com/thomaspoignant/go-feature-flag/exporter/opentelemetryexporter"
+
+	// spanName is the name of the span (or span event) created for each feature event.
+	spanName = "gofeatureflag.evaluation"
+
+	// batchSpanName is the name of the span created for each batch in the EventModeSpanEvents mode.
+	batchSpanName = "gofeatureflag.export"
+
+	// contextAttributePrefix is the prefix of the attributes copied from the evaluation context.
+	contextAttributePrefix = "gofeatureflag.context."
+
+	// metadataAttributePrefix is the prefix of the attributes copied from the metadata of the flag.
+	metadataAttributePrefix = "gofeatureflag.metadata."
+)
+
+// EventMode is the way the feature events are recorded in the traces.
+type EventMode string
+
+const (
+	// EventModeSpans creates a span for each feature event.
+	EventModeSpans EventMode = "spans"
+
+	// EventModeSpanEvents creates a single span for each batch of feature events,
+	// each feature event is recorded as an event of this span.
+	// It is less expensive than EventModeSpans if you have a lot of evaluations.
+	EventModeSpanEvents EventMode = "spanEvents"
+)
+
+// Option is a function to configure the Exporter.
+type Option func(*Exporter)
+
+// WithTracerProvider is setting the tracer provider used to create the spans.
+// Default: the global tracer provider (otel.GetTracerProvider())
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(e *Exporter) {
+		e.tracerProvider = provider
+	}
+}
+
+// WithContextAttributes is the list of custom attributes of the evaluation context copied on each span
+// as gofeatureflag.context.<key>.
+// Only the keys listed are exported, to avoid leaking sensitive attributes of your users.
+// Default: no attribute of the evaluation context is exported.
+func WithContextAttributes(keys ...string) Option {
+	return func(e *Exporter) {
+		e.contextAttributes = append(e.contextAttributes, keys...)
+	}
+}
+
+// WithMaxBatchBytes is the maximum size of the events exported in one call, measured as JSON.
+// Use it if your OpenTelemetry collector is limiting the size of the requests.
+// Default: 0, no limit.
+func WithMaxBatchBytes(maxBatchBytes int) Option {
+	return func(e *Exporter) {
+		e.maxBatchBytes = maxBatchBytes
+	}
+}
+
+// WithEventMode is setting how the feature events are recorded in the traces,
+// as a span per event (EventModeSpans) or as events of a single span per batch (EventModeSpanEvents).
+// Default: EventModeSpans
+func WithEventMode(mode EventMode) Option {
+	return func(e *Exporter) {
+		e.eventMode = mode
+	}
+}
+
+// Exporter is creating an OpenTelemetry span for each feature event.
+type Exporter struct {
+	tracerProvider    trace.TracerProvider
+	contextAttributes []string
+	maxBatchBytes     int
+	eventMode         EventMode
+}
+
+// NewExporter creates a new OpenTelemetry exporter.
+func NewExporter(options ...Option) *Exporter {
+	e := &Exporter{}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// GetMaxBatchBytes returns the maximum size of the events exported in one call.
+func (e *Exporter) GetMaxBatchBytes() int {
+	return e.maxBatchBytes
+}
+
+// GetContextAttributes returns the keys of the custom attributes of the evaluation context copied on each span.
+func (e *Exporter) GetContextAttributes() []string {
+	return e.contextAttributes
+}
+
+// Export is creating a span for each featureEvents received,
+// or a single span with an event per featureEvents in the EventModeSpanEvents mode.
+func (e *Exporter) Export(ctx context.Context, _ *log.Logger, featureEvents []exporter.FeatureEvent) error {
+	provider := e.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	tracer := provider.Tracer(tracerName)
+
+	if e.eventMode == EventModeSpanEvents {
+		if len(featureEvents) == 0 {
+			return nil
+		}
+		_, span := tracer.Start(ctx, batchSpanName,
+			trace.WithAttributes(attribute.Int("gofeatureflag.eventCount", len(featureEvents))))
+		for _, event := range featureEvents {
+			span.AddEvent(spanName,
+				trace.WithTimestamp(time.Unix(event.CreationDate, 0)),
+				trace.WithAttributes(e.attributes(event)...))
+		}
+		span.End()
+		return nil
+	}
+
+	for _, event := range featureEvents {
+		creationDate := time.Unix(event.CreationDate, 0)
+		_, span := tracer.Start(ctx, spanName, trace.WithTimestamp(creationDate))
+		span.SetAttributes(e.attributes(event)...)
+		span.End(trace.WithTimestamp(creationDate))
+	}
+	return nil
+}
+
+// IsBulk return false, we are creating the spans as soon as the events are produced.
+// In the EventModeSpanEvents mode it returns true, the events are collected to create a span per batch.
+func (e *Exporter) IsBulk() bool {
+	return e.eventMode == EventModeSpanEvents
+}
+
+// attributes returns the attributes of the span for this event.
+func (e *Exporter) attributes(event exporter.FeatureEvent) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attribute.String("gofeatureflag.kind", event.Kind),
+		attribute.String("gofeatureflag.contextKind", event.ContextKind),
+		attribute.String("gofeatureflag.userKey", event.UserKey),
+		attribute.String("gofeatureflag.key", event.Key),
+		attribute.String("gofeatureflag.variation", event.Variation),
+		// we convert to string because there is no attribute for interface{}
+		attribute.String("gofeatureflag.value", fmt.Sprintf("%v", event.Value)),
+		attribute.Bool("gofeatureflag.default", event.Default),
+		attribute.String("gofeatureflag.version", event.Version),
+		attribute.String("gofeatureflag.source", event.Source),
+	}
+	if event.Environment != "" {
+		attributes = append(attributes, attribute.String("gofeatureflag.environment", event.Environment))
+	}
+
+	for _, key := range e.contextAttributes {
+		value, ok := event.EvaluationContext[key]
+		if !ok {
+			continue
+		}
+		attributes = append(attributes, contextAttribute(contextAttributePrefix+key, value))
+	}
+
+	// the keys are sorted to always export the attributes in the same order.
+	metadataKeys := make([]string, 0, len(event.Metadata))
+	for key := range event.Metadata {
+		metadataKeys = append(metadataKeys, key)
+	}
+	sort.Strings(metadataKeys)
+	for _, key := range metadataKeys {
+		attributes = append(attributes, contextAttribute(metadataAttributePrefix+key, event.Metadata[key]))
+	}
+	return attributes
+}
+
+// contextAttribute converts a value of the evaluation context into an attribute.
+func contextAttribute(name string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(name, v)
+	case bool:
+		return attribute.Bool(name, v)
+	case int:
+		return attribute.Int(name, v)
+	case int64:
+		return attribute.Int64(name, v)
+	case float64:
+		return attribute.Float64(name, v)
+	default:
+		return attribute.String(name, fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,184 @@
+package opentelemetryexporter_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/exporter/opentelemetryexporter"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExporter_Export(t *testing.T) {
+	evaluationCtx := ffcontext.NewEvaluationContextBuilder("user-key").
+		AddCustom("country", "FR").
+		AddCustom("beta", true).
+		AddCustom("age", 42).
+		AddCustom("email", "john.doe@example.com").
+		Build()
+	event := exporter.NewFeatureEventWithClock(
+		exporter.FixedClock{Time: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		evaluationCtx, "my-flag", "value-A", "variation-A", false, "v1", "SERVER")
+	// the attributes are copied by GoFeatureFlag when an exporter asks for them.
+	event.EvaluationContext = evaluationCtx.GetCustom()
+
+	tests := []struct {
+		name    string
+		options []opentelemetryexporter.Option
+		want    []attribute.KeyValue
+	}{
+		{
+			name: "no context attributes by default",
+			want: []attribute.KeyValue{},
+		},
+		{
+			name: "only allowlisted context attributes",
+			options: []opentelemetryexporter.Option{
+				opentelemetryexporter.WithContextAttributes("country", "beta"),
+				opentelemetryexporter.WithContextAttributes("age", "unknown"),
+			},
+			want: []attribute.KeyValue{
+				attribute.String("gofeatureflag.context.country", "FR"),
+				attribute.Bool("gofeatureflag.context.beta", true),
+				attribute.Int("gofeatureflag.context.age", 42),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			exp := opentelemetryexporter.NewExporter(
+				append(tt.options, opentelemetryexporter.WithTracerProvider(provider))...)
+
+			err := exp.Export(context.Background(), log.Default(), []exporter.FeatureEvent{event})
+			assert.NoError(t, err)
+
+			spans := recorder.Ended()
+			assert.Len(t, spans, 1)
+			assert.Equal(t, "gofeatureflag.evaluation", spans[0].Name())
+			assert.Equal(t, int64(1704067200), spans[0].StartTime().Unix())
+
+			want := append([]attribute.KeyValue{
+				attribute.String("gofeatureflag.kind", "feature"),
+				attribute.String("gofeatureflag.contextKind", "user"),
+				attribute.String("gofeatureflag.userKey", "user-key"),
+				attribute.String("gofeatureflag.key", "my-flag"),
+				attribute.String("gofeatureflag.variation", "variation-A"),
+				attribute.String("gofeatureflag.value", "value-A"),
+				attribute.Bool("gofeatureflag.default", false),
+				attribute.String("gofeatureflag.version", "v1"),
+				attribute.String("gofeatureflag.source", "SERVER"),
+			}, tt.want...)
+			assert.Equal(t, want, spans[0].Attributes())
+		})
+	}
+}
+
+func TestExporter_ExportEnvironment(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	exp := opentelemetryexporter.NewExporter(opentelemetryexporter.WithTracerProvider(provider))
+
+	event := exporter.NewFeatureEvent(ffcontext.NewEvaluationContext("user-key"),
+		"my-flag", "value-A", "variation-A", false, "v1", "SERVER")
+	event.Environment = "production"
+	err := exp.Export(context.Background(), log.Default(), []exporter.FeatureEvent{event})
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("gofeatureflag.environment", "production"))
+}
+
+func TestExporter_IsBulk(t *testing.T) {
+	exp := opentelemetryexporter.NewExporter()
+	assert.False(t, exp.IsBulk())
+	exp = opentelemetryexporter.NewExporter(
+		opentelemetryexporter.WithEventMode(opentelemetryexporter.EventModeSpanEvents))
+	assert.True(t, exp.IsBulk())
+}
+
+func TestExporter_ExportEventMode(t *testing.T) {
+	events := make([]exporter.FeatureEvent, 0, 3)
+	for _, flagKey := range []string{"flag-1", "flag-2", "flag-3"} {
+		events = append(events, exporter.NewFeatureEvent(ffcontext.NewEvaluationContext("user-key"),
+			flagKey, "value-A", "variation-A", false, "v1", "SERVER"))
+	}
+
+	t.Run("child spans by default", func(t *testing.T) {
+		spanExporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter))
+		exp := opentelemetryexporter.NewExporter(opentelemetryexporter.WithTracerProvider(provider))
+
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+		err := exp.Export(ctx, log.Default(), events)
+		parent.End()
+		assert.NoError(t, err)
+
+		spans := spanExporter.GetSpans()
+		assert.Len(t, spans, len(events)+1)
+		for _, span := range spans[:len(events)] {
+			assert.Equal(t, "gofeatureflag.evaluation", span.Name)
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+			assert.Empty(t, span.Events)
+		}
+	})
+
+	t.Run("span events", func(t *testing.T) {
+		spanExporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter))
+		exp := opentelemetryexporter.NewExporter(
+			opentelemetryexporter.WithTracerProvider(provider),
+			opentelemetryexporter.WithEventMode("spanEvents"))
+
+		err := exp.Export(context.Background(), log.Default(), events)
+		assert.NoError(t, err)
+
+		spans := spanExporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "gofeatureflag.export", spans[0].Name)
+		assert.Contains(t, spans[0].Attributes, attribute.Int("gofeatureflag.eventCount", len(events)))
+		assert.Len(t, spans[0].Events, len(events))
+		for index, event := range spans[0].Events {
+			assert.Equal(t, "gofeatureflag.evaluation", event.Name)
+			assert.Contains(t, event.Attributes, attribute.String("gofeatureflag.key", events[index].Key))
+		}
+	})
+}
+
+func TestExporter_GetContextAttributes(t *testing.T) {
+	assert.Empty(t, opentelemetryexporter.NewExporter().GetContextAttributes())
+	exp := opentelemetryexporter.NewExporter(
+		opentelemetryexporter.WithContextAttributes("country", "beta"),
+		opentelemetryexporter.WithContextAttributes("age"))
+	assert.Equal(t, []string{"country", "beta", "age"}, exp.GetContextAttributes())
+}
+
+func TestExporter_ExportMetadata(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	exp := opentelemetryexporter.NewExporter(opentelemetryexporter.WithTracerProvider(provider))
+
+	event := exporter.NewFeatureEvent(ffcontext.NewEvaluationContext("user-key"),
+		"my-flag", "value-A", "variation-A", false, "v1", "SERVER")
+	event.Metadata = map[string]interface{}{
+		"owner":   "team-a",
+		"jira":    "FF-123",
+		"private": false,
+	}
+	err := exp.Export(context.Background(), log.Default(), []exporter.FeatureEvent{event})
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("gofeatureflag.metadata.owner", "team-a"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("gofeatureflag.metadata.jira", "FF-123"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Bool("gofeatureflag.metadata.private", false))
+}
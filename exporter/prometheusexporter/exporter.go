@@ -0,0 +1,87 @@
+package prometheusexporter
+
+import (
+	"context"
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+)
+
+const (
+	// namespace is the prefix of all the metrics exposed by the exporter.
+	namespace = "gofeatureflag"
+
+	// evaluationsMetricName is the name of the counter of evaluations.
+	evaluationsMetricName = "evaluation_events_total"
+)
+
+// Option is a function to configure the Exporter.
+type Option func(*Exporter)
+
+// WithConstLabels is adding static labels to all the metrics exposed by the exporter.
+// It is useful to identify the instance of your application when several of them share the same registry.
+// Default: no constant label.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(e *Exporter) {
+		e.constLabels = labels
+	}
+}
+
+// Exporter is aggregating the feature events as Prometheus metrics.
+// Unlike the other exporters it does not store each event, it counts the evaluations
+// by flag key, variation and reason.
+//
+// The Exporter is a prometheus.Collector, you can mount the registry on your own /metrics endpoint
+// to expose the metrics.
+type Exporter struct {
+	constLabels prometheus.Labels
+	evaluations *prometheus.CounterVec
+}
+
+// NewExporter creates a new Prometheus exporter and registers its metrics on the registerer.
+// If registerer is nil the metrics are registered on prometheus.DefaultRegisterer.
+func NewExporter(registerer prometheus.Registerer, options ...Option) (*Exporter, error) {
+	e := &Exporter{}
+	for _, option := range options {
+		option(e)
+	}
+
+	e.evaluations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        evaluationsMetricName,
+		Help:        "Counter of the flag evaluations collected by the exporter.",
+		ConstLabels: e.constLabels,
+	}, []string{"flag_key", "variation", "reason"})
+
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	if err := registerer.Register(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Describe sends the descriptors of the metrics of the exporter, it implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	e.evaluations.Describe(ch)
+}
+
+// Collect sends the current value of the metrics of the exporter, it implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.evaluations.Collect(ch)
+}
+
+// Export is incrementing the counters for each featureEvents received.
+func (e *Exporter) Export(_ context.Context, _ *log.Logger, featureEvents []exporter.FeatureEvent) error {
+	for _, event := range featureEvents {
+		e.evaluations.WithLabelValues(event.Key, event.Variation, event.Reason).Inc()
+	}
+	return nil
+}
+
+// IsBulk return false, the counters are updated as soon as the events are produced.
+func (e *Exporter) IsBulk() bool {
+	return false
+}
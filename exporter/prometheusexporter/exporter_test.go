@@ -0,0 +1,77 @@
+package prometheusexporter_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/exporter/prometheusexporter"
+)
+
+func TestExporter_Export(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	exp, err := prometheusexporter.NewExporter(registry)
+	require.NoError(t, err)
+	assert.False(t, exp.IsBulk())
+
+	events := []exporter.FeatureEvent{
+		{Kind: "feature", Key: "flag-a", Variation: "enabled", Reason: "TARGETING_MATCH"},
+		{Kind: "feature", Key: "flag-a", Variation: "enabled", Reason: "TARGETING_MATCH"},
+		{Kind: "feature", Key: "flag-a", Variation: "disabled", Reason: "DEFAULT"},
+		{Kind: "feature", Key: "flag-b", Variation: "SdkDefault", Reason: "ERROR", Default: true},
+	}
+	require.NoError(t, exp.Export(context.Background(), log.Default(), events))
+	require.NoError(t, exp.Export(context.Background(), log.Default(), events[:1]))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, "gofeatureflag_evaluation_events_total", families[0].GetName())
+
+	got := map[[3]string]float64{}
+	for _, metric := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		got[[3]string{labels["flag_key"], labels["variation"], labels["reason"]}] = metric.GetCounter().GetValue()
+	}
+	assert.Equal(t, map[[3]string]float64{
+		{"flag-a", "enabled", "TARGETING_MATCH"}: 3,
+		{"flag-a", "disabled", "DEFAULT"}:        1,
+		{"flag-b", "SdkDefault", "ERROR"}:        1,
+	}, got)
+}
+
+func TestNewExporter_constLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	exp, err := prometheusexporter.NewExporter(registry,
+		prometheusexporter.WithConstLabels(prometheus.Labels{"service": "my-service"}))
+	require.NoError(t, err)
+	require.NoError(t, exp.Export(context.Background(), nil, []exporter.FeatureEvent{
+		{Kind: "feature", Key: "flag-a", Variation: "enabled", Reason: "STATIC"},
+	}))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Len(t, families[0].GetMetric(), 1)
+	labels := map[string]string{}
+	for _, label := range families[0].GetMetric()[0].GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	assert.Equal(t, "my-service", labels["service"])
+}
+
+func TestNewExporter_alreadyRegistered(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := prometheusexporter.NewExporter(registry)
+	require.NoError(t, err)
+
+	_, err = prometheusexporter.NewExporter(registry)
+	assert.Error(t, err)
+}
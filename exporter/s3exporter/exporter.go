@@ -25,6 +25,15 @@ type Exporter struct {
 	// upload your exported data files.
 	AwsConfig *aws.Config
 
+	// Endpoint is the URL of an S3-compatible service (ex: MinIO, Ceph, LocalStack).
+	// If empty, the endpoint of AwsConfig is used.
+	Endpoint string
+
+	// ForcePathStyle forces the use of path-style URLs (https://endpoint/bucket/key)
+	// instead of virtual-hosted-style URLs (https://bucket.endpoint/key).
+	// Most S3-compatible services require it.
+	ForcePathStyle bool
+
 	// Format is the output format you want in your exported file.
 	// Available format are JSON, CSV and Parquet.
 	// Default: JSON
@@ -52,6 +61,11 @@ type Exporter struct {
 	// Default: SNAPPY
 	ParquetCompressionCodec string
 
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
 	s3Uploader s3manageriface.UploaderAPI
 	init       sync.Once
 }
@@ -62,8 +76,18 @@ func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents
 	if f.s3Uploader == nil {
 		var initErr error
 		f.init.Do(func() {
+			awsConfig := &aws.Config{}
+			if f.AwsConfig != nil {
+				awsConfig = f.AwsConfig.Copy()
+			}
+			if f.Endpoint != "" {
+				awsConfig.Endpoint = aws.String(f.Endpoint)
+			}
+			if f.ForcePathStyle {
+				awsConfig.S3ForcePathStyle = aws.Bool(true)
+			}
 			var sess *session.Session
-			sess, initErr = session.NewSession(f.AwsConfig)
+			sess, initErr = session.NewSession(awsConfig)
 			f.s3Uploader = s3manager.NewUploader(sess)
 		})
 		// Check that we don't have error in the init.Do()
@@ -87,6 +111,7 @@ func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents
 		Filename:                f.Filename,
 		CsvTemplate:             f.CsvTemplate,
 		ParquetCompressionCodec: f.ParquetCompressionCodec,
+		FieldNameStyle:          f.FieldNameStyle,
 	}
 	err = fileExporter.Export(ctx, logger, featureEvents)
 	if err != nil {
@@ -23,6 +23,15 @@ type Exporter struct {
 	// upload your exported data files.
 	AwsConfig *aws.Config
 
+	// Endpoint is the URL of an S3-compatible service (ex: MinIO, Ceph, LocalStack).
+	// If empty, the AWS endpoint resolution is used.
+	Endpoint string
+
+	// ForcePathStyle forces the use of path-style URLs (https://endpoint/bucket/key)
+	// instead of virtual-hosted-style URLs (https://bucket.endpoint/key).
+	// Most S3-compatible services require it.
+	ForcePathStyle bool
+
 	// Format is the output format you want in your exported file.
 	// Available format are JSON, CSV and Parquet.
 	// Default: JSON
@@ -50,6 +59,11 @@ type Exporter struct {
 	// Default: SNAPPY
 	ParquetCompressionCodec string
 
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
 	s3Uploader UploaderAPI
 	init       sync.Once
 }
@@ -66,7 +80,12 @@ func (f *Exporter) initializeUploader(ctx context.Context) error {
 			f.AwsConfig = &cfg
 		}
 
-		client := s3.NewFromConfig(*f.AwsConfig)
+		client := s3.NewFromConfig(*f.AwsConfig, func(o *s3.Options) {
+			if f.Endpoint != "" {
+				o.BaseEndpoint = aws.String(f.Endpoint)
+			}
+			o.UsePathStyle = f.ForcePathStyle
+		})
 		f.s3Uploader = manager.NewUploader(client)
 	})
 	return initErr
@@ -96,6 +115,7 @@ func (f *Exporter) Export(ctx context.Context, logger *log.Logger, featureEvents
 		Filename:                f.Filename,
 		CsvTemplate:             f.CsvTemplate,
 		ParquetCompressionCodec: f.ParquetCompressionCodec,
+		FieldNameStyle:          f.FieldNameStyle,
 	}
 	err = fileExporter.Export(ctx, logger, featureEvents)
 	if err != nil {
@@ -2,7 +2,6 @@ package sqsexporter
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -22,6 +21,11 @@ type Exporter struct {
 	// upload your exported data files.
 	AwsConfig *aws.Config
 
+	// FieldNameStyle is the style of the field names when the events are serialized in JSON.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// (optional) Default: camel
+	FieldNameStyle exporter.FieldNameStyle
+
 	init       sync.Once
 	sqsService SQSSendMessageAPI
 }
@@ -47,7 +51,7 @@ func (f *Exporter) Export(ctx context.Context, _ *log.Logger, featureEvents []ex
 	}
 
 	for _, event := range featureEvents {
-		messageBody, err := json.Marshal(event)
+		messageBody, err := exporter.MarshalEventInJSON(event, f.FieldNameStyle)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,50 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// extractValue returns the sub value of value located by the JSON Pointer (RFC 6901).
+// An empty pointer references the whole value.
+func extractValue(value interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: it should start with /", pointer)
+	}
+
+	// the value is converted to its JSON representation to be able to walk in any struct.
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var current interface{}
+	if err := json.Unmarshal(content, &current); err != nil {
+		return nil, err
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := current.(type) {
+		case map[string]interface{}:
+			child, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found in the value", pointer)
+			}
+			current = child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("path %q not found in the value", pointer)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %q not found in the value", pointer)
+		}
+	}
+	return current, nil
+}
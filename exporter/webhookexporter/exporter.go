@@ -46,6 +46,14 @@ type Exporter struct {
 	Meta map[string]string
 	// Headers (optional) the list of Headers to send to the endpoint
 	Headers map[string][]string
+	// MaxBatchBytes (optional) is the maximum size of the events sent in one call, the events are
+	// sent in several calls if needed. Use it if your endpoint is limiting the size of the requests.
+	// Default: 0, no limit.
+	MaxBatchBytes int
+	// FieldNameStyle (optional) is the style of the field names of the events in the payload.
+	// Available styles are "camel" (ex: userKey) and "snake" (ex: user_key).
+	// Default: camel
+	FieldNameStyle exporter.FieldNameStyle
 
 	httpClient internal.HTTPClient
 	init       sync.Once
@@ -57,7 +65,12 @@ type webhookPayload struct {
 	Meta map[string]string `json:"meta"`
 
 	// events is the list of the event we send in the payload
-	Events []exporter.FeatureEvent `json:"events"`
+	Events []json.RawMessage `json:"events"`
+}
+
+// GetMaxBatchBytes returns the maximum size of the events sent in one call.
+func (f *Exporter) GetMaxBatchBytes() int {
+	return f.MaxBatchBytes
 }
 
 // Export is sending a collection of events in a webhook call.
@@ -77,9 +90,17 @@ func (f *Exporter) Export(ctx context.Context, _ *log.Logger, featureEvents []ex
 		}
 	})
 
+	events := make([]json.RawMessage, 0, len(featureEvents))
+	for _, event := range featureEvents {
+		content, err := exporter.MarshalEventInJSON(event, f.FieldNameStyle)
+		if err != nil {
+			return err
+		}
+		events = append(events, content)
+	}
 	body := webhookPayload{
 		Meta:   f.Meta,
-		Events: featureEvents,
+		Events: events,
 	}
 	payload, err := json.Marshal(body)
 	if err != nil {
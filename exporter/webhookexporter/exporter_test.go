@@ -221,3 +221,37 @@ func TestWebhook_Export_impossibleToParse(t *testing.T) {
 	err := f.Export(context.Background(), log.New(os.Stdout, "", 0), []exporter.FeatureEvent{})
 	assert.EqualError(t, err, "parse \" http://invalid.com/\": first path segment in URL cannot contain colon")
 }
+
+func TestWebhook_Export_snakeCase(t *testing.T) {
+	httpClient := &testutils.HTTPClientMock{StatusCode: 200}
+	f := &Exporter{
+		EndpointURL:    "http://valid.com/webhook",
+		Meta:           map[string]string{"hostname": "hostname"},
+		FieldNameStyle: exporter.FieldNameStyleSnake,
+		httpClient:     httpClient,
+	}
+	err := f.Export(context.Background(), log.New(os.Stdout, "", 0), []exporter.FeatureEvent{
+		{
+			Kind: "feature", ContextKind: "anonymousUser", UserKey: "ABCD", CreationDate: 1617970547, Key: "random-key",
+			Variation: "Default", Value: "YO", Default: false, Source: "SERVER",
+		},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "meta": {"hostname": "hostname"},
+  "events": [
+    {
+      "kind": "feature",
+      "context_kind": "anonymousUser",
+      "user_key": "ABCD",
+      "creation_date": 1617970547,
+      "key": "random-key",
+      "variation": "Default",
+      "value": "YO",
+      "default": false,
+      "version": "",
+      "source": "SERVER"
+    }
+  ]
+}`, httpClient.Body)
+}
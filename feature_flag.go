@@ -2,12 +2,18 @@ package ffclient
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/exporter"
+	"github.com/thomaspoignant/go-feature-flag/flagvalidation"
 	"github.com/thomaspoignant/go-feature-flag/internal/dto"
 	"github.com/thomaspoignant/go-feature-flag/retriever"
 	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
@@ -53,6 +59,42 @@ type GoFeatureFlag struct {
 	bgUpdater        backgroundUpdater
 	dataExporter     *exporter.Scheduler
 	retrieverManager *retriever.Manager
+	metrics          *evaluationMetrics
+	sampler          *eventSampler
+
+	// health is the result of the retrievals of the flags.
+	health healthTracker
+
+	// overrides are the variations pinned for specific targeting keys.
+	overrides overrideStore
+
+	// evaluationCache keeps the evaluation results when Config.EvaluationCacheTTL is set.
+	evaluationCache *evaluationCache
+
+	// additionalDataExporters are the schedulers of the Config.AdditionalDataExporters.
+	additionalDataExporters []*exporter.Scheduler
+
+	// eventContextAttributes are the custom attributes of the evaluation context requested by the
+	// exporters (see exporter.ContextAttributesSelector), only those are copied in the events.
+	eventContextAttributes []string
+
+	// usingPersistedFlags is true while the flags served are the ones loaded from
+	// Config.PersistentFlagConfigurationFile.
+	usingPersistedFlags atomic.Bool
+
+	// retrieverDeltas are the deltas accumulated for each DeltaRetriever.
+	retrieverDeltas retrieverDeltas
+
+	// logger is the logger built from Config.Logger and Config.LeveledLogger.
+	logger *fflog.FFLogger
+
+	// retrieverCtx is the context given to the retrievers, it is cancelled by Close
+	// to abandon the refresh in progress.
+	retrieverCtx     context.Context
+	cancelRetrievers context.CancelFunc
+
+	// daemonDone is closed when the flag updater daemon is stopped.
+	daemonDone chan struct{}
 }
 
 // ff is the default object for go-feature-flag
@@ -76,98 +118,276 @@ func New(config Config) (*GoFeatureFlag, error) {
 		// do nothing
 	}
 
+	if config.DataCollectorSampleRate < 0 || config.DataCollectorSampleRate > 1 {
+		return nil, fmt.Errorf("%v is not a valid DataCollectorSampleRate value, it needs to be between 0 and 1",
+			config.DataCollectorSampleRate)
+	}
+
 	goFF := &GoFeatureFlag{
-		config: config,
+		config:          config,
+		sampler:         newEventSampler(config.DataCollectorSampleRate, config.DataCollectorSampleSeed),
+		evaluationCache: newEvaluationCache(config.EvaluationCacheTTL),
+		logger:          config.internalLogger(),
 	}
+	// logLogger is the logger given to the components still using a *log.Logger.
+	logLogger := goFF.logger.GetLogLogger()
 
 	if !config.Offline {
 		notifiers := config.Notifiers
-		if config.Logger != nil {
-			notifiers = append(notifiers, &logsnotifier.Notifier{Logger: config.Logger})
+		if logLogger != nil {
+			notifiers = append(notifiers, &logsnotifier.Notifier{Logger: logLogger})
+		}
+		if config.OnConfigurationChange != nil {
+			notifiers = append(notifiers, &configurationChangeNotifier{
+				callback: config.OnConfigurationChange,
+				logger:   goFF.logger,
+			})
 		}
 
 		notificationService := cache.NewNotificationService(notifiers)
 		goFF.bgUpdater = newBackgroundUpdater(config.PollingInterval, config.EnablePollingJitter)
-		goFF.cache = cache.New(notificationService, config.Logger)
+		goFF.cache = cache.New(notificationService, logLogger)
 
-		retrievers, err := config.GetRetrievers()
-		if err != nil {
+		// the metrics are initialized before starting the retrievers, nothing has to be stopped if it fails.
+		if config.OpenTelemetryMeterProvider != nil {
+			metrics, err := newEvaluationMetrics(config.OpenTelemetryMeterProvider)
+			if err != nil {
+				return nil, fmt.Errorf("impossible to initialize the OpenTelemetry metrics: %v", err)
+			}
+			goFF.metrics = metrics
+		}
+
+		if err := goFF.initRetrievers(logLogger); err != nil {
 			return nil, err
 		}
-		goFF.retrieverManager = retriever.NewManager(config.Context, retrievers, config.Logger)
-		err = goFF.retrieverManager.Init(config.Context)
-		if err != nil && !config.StartWithRetrieverError {
-			return nil, fmt.Errorf("impossible to initialize the retrievers, please check your configuration: %v", err)
+		goFF.daemonDone = make(chan struct{})
+		go goFF.startFlagUpdaterDaemon()
+
+		goFF.initDataExporters(logLogger)
+	}
+	return goFF, nil
+}
+
+// initRetrievers initializes the retrievers and retrieves the flags for the first time.
+// If the retrievers fail, the persisted flags are used when available.
+func (g *GoFeatureFlag) initRetrievers(logLogger *log.Logger) error {
+	retrievers, err := g.config.GetRetrievers()
+	if err != nil {
+		return err
+	}
+	for _, r := range retrievers {
+		// the stream retrievers ask for a refresh as soon as they receive a new configuration
+		if streamRetriever, ok := r.(retriever.StreamRetriever); ok {
+			streamRetriever.OnUpdate(g.bgUpdater.requestRefresh)
 		}
+	}
+	retrieverCtx := g.config.Context
+	if retrieverCtx == nil {
+		retrieverCtx = context.Background()
+	}
+	g.retrieverCtx, g.cancelRetrievers = context.WithCancel(retrieverCtx)
+	g.retrieverManager = retriever.NewManager(g.retrieverCtx, retrievers, logLogger)
+	err = g.retrieverManager.Init(g.retrieverCtx)
+	usePersistedFlags := err != nil && g.loadPersistedFlags(err)
+	if err != nil && !usePersistedFlags && !g.config.StartWithRetrieverError {
+		return fmt.Errorf("impossible to initialize the retrievers, please check your configuration: %v", err)
+	}
 
-		err = retrieveFlagsAndUpdateCache(goFF.config, goFF.cache, goFF.retrieverManager)
-		if err != nil && !config.StartWithRetrieverError {
-			return nil, fmt.Errorf("impossible to retrieve the flags, please check your configuration: %v", err)
+	if !usePersistedFlags {
+		err = g.retrieveFlags()
+		if err != nil && !g.loadPersistedFlags(err) && !g.config.StartWithRetrieverError {
+			return fmt.Errorf("impossible to retrieve the flags, please check your configuration: %v", err)
 		}
-		go goFF.startFlagUpdaterDaemon()
+	}
+	return nil
+}
 
-		if goFF.config.DataExporter.Exporter != nil {
-			// init the data exporter
-			goFF.dataExporter = exporter.NewScheduler(goFF.config.Context, goFF.config.DataExporter.FlushInterval,
-				goFF.config.DataExporter.MaxEventInMemory, goFF.config.DataExporter.Exporter, goFF.config.Logger)
+// initDataExporters creates the schedulers of the data exporters.
+func (g *GoFeatureFlag) initDataExporters(logLogger *log.Logger) {
+	if g.config.DataExporter.Exporter != nil {
+		// init the data exporter
+		g.dataExporter = newDataExporterScheduler(g.config.Context, g.config.DataExporter, logLogger)
+	}
+	for _, dataExporter := range g.config.AdditionalDataExporters {
+		if dataExporter.Exporter != nil {
+			g.additionalDataExporters = append(g.additionalDataExporters,
+				newDataExporterScheduler(g.config.Context, dataExporter, logLogger))
+		}
+	}
+	g.eventContextAttributes = eventContextAttributes(
+		append([]DataExporter{g.config.DataExporter}, g.config.AdditionalDataExporters...))
+}
 
-			// we start the daemon only if we have a bulk exporter
-			if goFF.config.DataExporter.Exporter.IsBulk() {
-				go goFF.dataExporter.StartDaemon()
+// newDataExporterScheduler creates the scheduler of a data exporter,
+// the daemon flushing the events is started only if the exporter is a bulk exporter.
+func newDataExporterScheduler(ctx context.Context, dataExporter DataExporter, logger *log.Logger,
+) *exporter.Scheduler {
+	scheduler := exporter.NewScheduler(ctx, dataExporter.FlushInterval,
+		dataExporter.MaxEventInMemory, dataExporter.Exporter, logger,
+		exporter.WithDeliveryGuarantee(dataExporter.DeliveryGuarantee, dataExporter.MaxEventInRetry),
+		exporter.WithShutdownTimeout(dataExporter.ShutdownTimeout),
+		exporter.WithSortEvents(dataExporter.SortEvents),
+		exporter.WithValueExtract(dataExporter.ValueExtract),
+		exporter.WithFlagFilter(dataExporter.IncludeFlags, dataExporter.ExcludeFlags),
+		exporter.WithEventBuffer(dataExporter.EventBufferSize))
+
+	// we start the daemon only if we have a bulk exporter
+	if dataExporter.Exporter.IsBulk() {
+		go scheduler.StartDaemon()
+	}
+	return scheduler
+}
+
+// eventContextAttributes returns the custom attributes of the evaluation context requested by the exporters.
+func eventContextAttributes(dataExporters []DataExporter) []string {
+	var keys []string
+	for _, dataExporter := range dataExporters {
+		if selector, ok := dataExporter.Exporter.(exporter.ContextAttributesSelector); ok {
+			for _, key := range selector.GetContextAttributes() {
+				if !slices.Contains(keys, key) {
+					keys = append(keys, key)
+				}
 			}
 		}
 	}
-	return goFF, nil
+	return keys
 }
 
-// Close wait until thread are done
+// Close stops the background goroutines and exports the events still in memory before returning.
+// The refresh of the flags in progress is abandoned, the context given to the retrievers is cancelled.
+// The export of the remaining events is bounded by DataExporter.ShutdownTimeout.
 func (g *GoFeatureFlag) Close() {
 	if g != nil {
-		if g.cache != nil {
-			// clear the cache
-			g.cache.Close()
+		if g.cancelRetrievers != nil {
+			g.cancelRetrievers()
 		}
 		if g.bgUpdater.updaterChan != nil && g.bgUpdater.ticker != nil {
 			g.bgUpdater.close()
 		}
+		if g.daemonDone != nil {
+			// the refresh in progress returns as soon as the context is cancelled.
+			<-g.daemonDone
+		}
+		if g.cache != nil {
+			// clear the cache
+			g.cache.Close()
+		}
 
 		if g.dataExporter != nil {
 			g.dataExporter.Close()
 		}
+		for _, dataExporter := range g.additionalDataExporters {
+			dataExporter.Close()
+		}
 		if g.retrieverManager != nil {
 			_ = g.retrieverManager.Shutdown(g.config.Context)
 		}
 	}
 }
 
-// startFlagUpdaterDaemon is the daemon that refresh the cache every X seconds.
+// startFlagUpdaterDaemon is the daemon that refresh the cache every X seconds,
+// or as soon as a stream retriever receives a new configuration.
 func (g *GoFeatureFlag) startFlagUpdaterDaemon() {
+	defer close(g.daemonDone)
 	for {
 		select {
 		case <-g.bgUpdater.ticker.C:
-			err := retrieveFlagsAndUpdateCache(g.config, g.cache, g.retrieverManager)
-			if err != nil {
-				fflog.Printf(g.config.Logger, "error while updating the cache: %v\n", err)
-			}
+			g.refreshFlags()
+		case <-g.bgUpdater.refreshChan:
+			g.refreshFlags()
 		case <-g.bgUpdater.updaterChan:
 			return
 		}
 	}
 }
 
+// refreshFlags retrieves the flags and updates the cache.
+func (g *GoFeatureFlag) refreshFlags() {
+	err := g.retrieveFlags()
+	if g.retrieverCtx.Err() != nil {
+		// the refresh has been abandoned by Close.
+		return
+	}
+	g.evaluationCache.invalidate()
+	if err != nil {
+		g.logger.Error("error while updating the cache", slog.Any("error", err))
+		return
+	}
+	generation, hash := g.cache.GetGeneration()
+	g.logger.Debug("flags refreshed", slog.Int64("generation", generation), slog.String("hash", hash))
+	g.usingPersistedFlags.Store(false)
+}
+
+// retrieveFlags retrieves the flags, updates the cache and records the result and the duration of the refresh.
+func (g *GoFeatureFlag) retrieveFlags() error {
+	start := time.Now()
+	err := retrieveFlagsAndUpdateCache(g.retrieverCtx, g.config, g.cache, g.retrieverManager, &g.retrieverDeltas)
+	if g.retrieverCtx.Err() == nil {
+		g.health.recordRefresh(err, time.Since(start))
+	}
+	return err
+}
+
+// retrieverResult is the result of a call to a retriever during a refresh of the flags.
+type retrieverResult struct {
+	err   error
+	value map[string]dto.DTO
+	delta *dto.Delta
+	index int
+}
+
+// retrieverDeltas keeps, for each DeltaRetriever, the accumulation of all the deltas it has returned.
+// The accumulated delta is applied on top of the full configuration returned by the other retrievers,
+// so the changes received during the previous refreshes are not lost.
+type retrieverDeltas struct {
+	mutex  sync.Mutex
+	deltas map[int]dto.Delta
+}
+
+// accumulate merges the delta returned by the retriever at index with the previous ones
+// and returns the accumulated delta.
+func (r *retrieverDeltas) accumulate(index int, delta dto.Delta) dto.Delta {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.deltas == nil {
+		r.deltas = map[int]dto.Delta{}
+	}
+	r.deltas[index] = r.deltas[index].Merge(delta)
+	return r.deltas[index]
+}
+
 // retrieveFlagsAndUpdateCache is called every X seconds to refresh the cache flag.
-func retrieveFlagsAndUpdateCache(config Config, cache cache.Manager, retrieverManager *retriever.Manager) error {
-	retrievers := retrieverManager.GetRetrievers()
-	// Results is the type that will receive the results when calling
-	// all the retrievers.
-	type Results struct {
-		Error error
-		Value map[string]dto.DTO
-		Index int
-	}
-
-	// resultsChan is the channel that will receive all the results.
-	resultsChan := make(chan Results)
+// The refresh is abandoned, without updating the cache, as soon as ctx is cancelled.
+func retrieveFlagsAndUpdateCache(ctx context.Context, config Config, cache cache.Manager,
+	retrieverManager *retriever.Manager, deltas *retrieverDeltas) error {
+	logger := config.internalLogger()
+	results, err := retrieveAll(ctx, config, cache, retrieverManager.GetRetrievers())
+	if err != nil {
+		return err
+	}
+
+	newFlags, err := updateCacheWithResults(cache, results, deltas, logger.GetLogLogger())
+	if err != nil {
+		logger.Error("impossible to update the cache of the flags", slog.Any("error", err))
+		return err
+	}
+
+	if config.PersistentFlagConfigurationFile != "" {
+		if err := persistFlags(config.PersistentFlagConfigurationFile, newFlags); err != nil {
+			logger.Error("impossible to persist the flags",
+				slog.String("path", config.PersistentFlagConfigurationFile), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// retrieveAll calls all the retrievers in parallel and returns their results in the order of the retrievers.
+// It returns the first error received, or the error of ctx if it is cancelled before the end.
+func retrieveAll(ctx context.Context, config Config, cache cache.Manager, retrievers []retriever.Retriever,
+) ([]retrieverResult, error) {
+	// resultsChan is the channel that will receive all the results, it is buffered to not block
+	// the retrievers still running when the refresh is abandoned.
+	resultsChan := make(chan retrieverResult, len(retrievers))
 	var wg sync.WaitGroup
 	wg.Add(len(retrievers))
 
@@ -180,47 +400,197 @@ func retrieveFlagsAndUpdateCache(config Config, cache cache.Manager, retrieverMa
 
 	for index, r := range retrievers {
 		// Launching GO routines to retrieve all files in parallel.
-		go func(r retriever.Retriever, format string, index int, ctx context.Context) {
+		go func(r retriever.Retriever, index int) {
 			defer wg.Done()
+			resultsChan <- retrieveOne(ctx, config, cache, r, index)
+		}(r, index)
+	}
 
-			// If the retriever is not ready, we ignore it
-			if rr, ok := r.(retriever.InitializableRetriever); ok && rr.Status() != retriever.RetrieverReady {
-				resultsChan <- Results{Error: nil, Value: map[string]dto.DTO{}, Index: index}
-				return
+	results := make([]retrieverResult, len(retrievers))
+	for {
+		select {
+		case v, ok := <-resultsChan:
+			if !ok {
+				return results, nil
 			}
-
-			rawValue, err := r.Retrieve(ctx)
-			if err != nil {
-				resultsChan <- Results{Error: err, Value: nil, Index: index}
-				return
+			if v.err != nil {
+				return nil, v.err
 			}
-			convertedFlag, err := cache.ConvertToFlagStruct(rawValue, format)
-			resultsChan <- Results{Error: err, Value: convertedFlag, Index: index}
-		}(r, config.FileFormat, index, config.Context)
+			results[v.index] = v
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
+}
 
-	retrieversResults := make([]map[string]dto.DTO, len(retrievers))
-	for v := range resultsChan {
-		if v.Error != nil {
-			return v.Error
+// retrieveOne calls the retriever and converts its document into flags, or into a delta for a DeltaRetriever.
+func retrieveOne(ctx context.Context, config Config, cache cache.Manager, r retriever.Retriever, index int,
+) retrieverResult {
+	isDelta := false
+	if dr, ok := r.(retriever.DeltaRetriever); ok {
+		isDelta = dr.IsDelta()
+	}
+
+	// If the retriever is not ready, we ignore it
+	if rr, ok := r.(retriever.InitializableRetriever); ok && rr.Status() != retriever.RetrieverReady {
+		// when the flags are persisted, we don't want to replace them by a partial configuration.
+		if config.PersistentFlagConfigurationFile != "" {
+			return retrieverResult{err: fmt.Errorf("retriever %d is not ready", index), index: index}
+		}
+		if isDelta {
+			// an empty delta keeps the current flags.
+			return retrieverResult{delta: &dto.Delta{}, index: index}
 		}
-		retrieversResults[v.Index] = v.Value
+		return retrieverResult{value: map[string]dto.DTO{}, index: index}
 	}
 
-	// merge all the flags
+	rawValue, err := r.Retrieve(ctx)
+	if err != nil {
+		return retrieverResult{err: err, index: index}
+	}
+	// the retriever can provide its own format, otherwise we use the one from the configuration
+	format := config.FileFormat
+	if fr, ok := r.(retriever.FormattedRetriever); ok && fr.Format() != "" {
+		format = fr.Format()
+	}
+	if isDelta {
+		delta, err := convertDelta(cache, rawValue, format, config.ValidateConfiguration, config.StrictValidation)
+		return retrieverResult{err: err, delta: delta, index: index}
+	}
+	if config.ValidateConfiguration {
+		if err := flagvalidation.ValidateConfiguration(rawValue, format); err != nil {
+			return retrieverResult{err: err, index: index}
+		}
+	}
+	if config.StrictValidation {
+		if err := validateVariationReferences(rawValue, format); err != nil {
+			return retrieverResult{err: err, index: index}
+		}
+	}
+	convertedFlag, err := cache.ConvertToFlagStruct(rawValue, format)
+	return retrieverResult{err: err, value: convertedFlag, index: index}
+}
+
+// updateCacheWithResults merges the results of the retrievers and updates the cache,
+// it returns the flags stored in the cache after the update.
+func updateCacheWithResults(cache cache.Manager, results []retrieverResult, deltas *retrieverDeltas,
+	logger *log.Logger) (map[string]dto.DTO, error) {
 	newFlags := map[string]dto.DTO{}
-	for _, flags := range retrieversResults {
-		for flagName, value := range flags {
+	newDeltas := make([]dto.Delta, 0)
+	accumulatedDeltas := make([]dto.Delta, 0)
+	for _, result := range results {
+		if result.delta != nil {
+			newDeltas = append(newDeltas, *result.delta)
+			accumulatedDeltas = append(accumulatedDeltas, deltas.accumulate(result.index, *result.delta))
+			continue
+		}
+		for flagName, value := range result.value {
 			newFlags[flagName] = value
 		}
 	}
 
-	err := cache.UpdateCache(newFlags, config.Logger)
+	if len(newDeltas) > 0 && len(newDeltas) == len(results) {
+		// only deltas, they are applied on top of the flags currently in the cache.
+		return cache.ApplyDeltas(newDeltas, logger)
+	}
+
+	// the full configuration is retrieved again, so all the deltas received since the start
+	// are applied on top of it, in the order of the retrievers.
+	for _, delta := range accumulatedDeltas {
+		newFlags = delta.Apply(newFlags)
+	}
+	return newFlags, cache.UpdateCache(newFlags, logger)
+}
+
+// convertDelta converts the document returned by a DeltaRetriever, when the validation is enabled
+// the flags to upsert are validated like a full configuration.
+func convertDelta(cache cache.Manager, rawValue []byte, format string, validate bool, strict bool,
+) (*dto.Delta, error) {
+	delta, err := cache.ConvertToDelta(rawValue, format)
+	if err != nil {
+		return nil, err
+	}
+	if (validate || strict) && len(delta.Upsert) > 0 {
+		upsert, err := json.Marshal(delta.Upsert)
+		if err != nil {
+			return nil, err
+		}
+		if validate {
+			if err := flagvalidation.ValidateConfiguration(upsert, "json"); err != nil {
+				return nil, err
+			}
+		}
+		if strict {
+			if err := validateVariationReferences(upsert, "json"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &delta, nil
+}
+
+// validateVariationReferences returns an error listing the variations used in the rules of the flags
+// that are not declared in the flags.
+func validateVariationReferences(rawValue []byte, format string) error {
+	validationErrors, err := flagvalidation.ValidateVariationReferences(rawValue, format)
 	if err != nil {
-		log.Printf("error: impossible to update the cache of the flags: %v", err)
 		return err
 	}
-	return nil
+	if len(validationErrors) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		errs = append(errs, validationError)
+	}
+	return fmt.Errorf("unknown variations referenced in the flags: %w", errors.Join(errs...))
+}
+
+// loadPersistedFlags is called when the flags cannot be retrieved at startup, it loads the flags
+// from Config.PersistentFlagConfigurationFile and returns true if the flags have been loaded.
+func (g *GoFeatureFlag) loadPersistedFlags(retrieveErr error) bool {
+	path := g.config.PersistentFlagConfigurationFile
+	if path == "" {
+		return false
+	}
+	if err := loadPersistedFlags(path, g.cache, g.config); err != nil {
+		g.logger.Error("impossible to load the persisted flags", slog.String("path", path), slog.Any("error", err))
+		return false
+	}
+	g.logger.Warn("impossible to retrieve the flags, serving the persisted flags",
+		slog.String("path", path), slog.Any("error", retrieveErr))
+	g.usingPersistedFlags.Store(true)
+	g.health.recordPersistedFlags(retrieveErr)
+	return true
+}
+
+// IsUsingPersistedFlags returns true if the flags served have been loaded from
+// Config.PersistentFlagConfigurationFile because the retrievers were not reachable at startup.
+// It returns false as soon as the flags are successfully retrieved.
+func (g *GoFeatureFlag) IsUsingPersistedFlags() bool {
+	return g != nil && g.usingPersistedFlags.Load()
+}
+
+// IsUsingPersistedFlags returns true if the flags served have been loaded from
+// Config.PersistentFlagConfigurationFile because the retrievers were not reachable at startup.
+func IsUsingPersistedFlags() bool {
+	return ff.IsUsingPersistedFlags()
+}
+
+// GetDroppedEvents returns the number of evaluation events that have been dropped by the data exporter
+// without being exported.
+func (g *GoFeatureFlag) GetDroppedEvents() int64 {
+	if g == nil {
+		return 0
+	}
+	var droppedEvents int64
+	if g.dataExporter != nil {
+		droppedEvents = g.dataExporter.GetDroppedEvents()
+	}
+	for _, dataExporter := range g.additionalDataExporters {
+		droppedEvents += dataExporter.GetDroppedEvents()
+	}
+	return droppedEvents
 }
 
 // GetCacheRefreshDate gives the date of the latest refresh of the cache
@@ -83,6 +83,30 @@ func BenchmarkBoolVar_RuleComplex(b *testing.B) {
 	}
 }
 
+func BenchmarkBoolVarBatch_Rule(b *testing.B) {
+	users := make([]ffcontext.Context, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		users = append(users, ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = client.BoolVariationBatch("bool-rule", users, false)
+	}
+}
+
+func BenchmarkBoolVarBatch_RuleIndividualCalls(b *testing.B) {
+	users := make([]ffcontext.Context, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		users = append(users, ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, user := range users {
+			_, _ = client.BoolVariation("bool-rule", user, false)
+		}
+	}
+}
+
 func BenchmarkBoolVar_RolloutProgressive(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		user := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
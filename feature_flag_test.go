@@ -1,24 +1,47 @@
 package ffclient_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/thomaspoignant/go-feature-flag/exporter"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 	"github.com/thomaspoignant/go-feature-flag/testutils/initializableretriever"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/notifier"
 	"github.com/thomaspoignant/go-feature-flag/retriever"
 
+	"github.com/thomaspoignant/go-feature-flag/exporter/opentelemetryexporter"
 	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inlineretriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inmemoryretriever"
 	"github.com/thomaspoignant/go-feature-flag/retriever/s3retriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/sseretriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/webhookretriever"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	ffclient "github.com/thomaspoignant/go-feature-flag"
 	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestStartWithoutRetriever(t *testing.T) {
@@ -74,6 +97,240 @@ func TestMultipleRetrieversWithOverrideFlag(t *testing.T) {
 	assert.NotEqual(t, flag.ErrorCodeFlagNotFound, flagRes2.ErrorCode)
 }
 
+func TestInMemoryRetriever(t *testing.T) {
+	client, err := ffclient.New(ffclient.Config{
+		PollingInterval: 60 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"bool-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+				"string-flag": map[string]interface{}{
+					"variations": map[string]interface{}{"A": "value_A", "B": "value_B"},
+					"targeting": []interface{}{
+						map[string]interface{}{"query": `key eq "random-key"`, "variation": "B"},
+					},
+					"defaultRule": map[string]interface{}{"variation": "A"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	user := ffcontext.NewEvaluationContext("random-key")
+	boolRes, err := client.BoolVariationDetails("bool-flag", user, false)
+	assert.NoError(t, err)
+	assert.True(t, boolRes.Value)
+	assert.Equal(t, flag.ReasonStatic, boolRes.Reason)
+
+	stringRes, err := client.StringVariationDetails("string-flag", user, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value_B", stringRes.Value)
+	assert.Equal(t, flag.ReasonTargetingMatch, stringRes.Reason)
+}
+
+func TestInlineRetriever(t *testing.T) {
+	client, err := ffclient.New(ffclient.Config{
+		PollingInterval: 60 * time.Second,
+		Retriever: &inlineretriever.Retriever{
+			FileFormat: "yaml",
+			Content: `string-flag:
+  variations:
+    A: value_A
+    B: value_B
+  targeting:
+    - query: key eq "random-key"
+      variation: B
+  defaultRule:
+    variation: A
+`,
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	res, err := client.StringVariationDetails("string-flag", ffcontext.NewEvaluationContext("random-key"), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value_B", res.Value)
+	assert.Equal(t, flag.ReasonTargetingMatch, res.Reason)
+
+	res, err = client.StringVariationDetails("string-flag", ffcontext.NewEvaluationContext("other-key"), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value_A", res.Value)
+}
+
+func TestOnConfigurationChange(t *testing.T) {
+	inMemoryRetriever := &inmemoryretriever.Retriever{
+		Flags: map[string]interface{}{
+			"bool-flag": map[string]interface{}{
+				"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+				"defaultRule": map[string]interface{}{"variation": "enabled"},
+			},
+		},
+	}
+
+	var client *ffclient.GoFeatureFlag
+	clientReady := make(chan struct{})
+	diffs := make(chan notifier.DiffCache, 10)
+	values := make(chan bool, 10)
+	client, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       inMemoryRetriever,
+		OnConfigurationChange: func(diff notifier.DiffCache) {
+			<-clientReady
+			diffs <- diff
+			value, _ := client.BoolVariation("bool-flag", ffcontext.NewEvaluationContext("random-key"), false)
+			values <- value
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+	close(clientReady)
+
+	// initial load of the flags
+	select {
+	case diff := <-diffs:
+		assert.Contains(t, diff.Added, "bool-flag")
+		assert.True(t, <-values)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "OnConfigurationChange not called for the initial load")
+	}
+
+	inMemoryRetriever.SetFlags(map[string]interface{}{
+		"bool-flag": map[string]interface{}{
+			"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+			"defaultRule": map[string]interface{}{"variation": "disabled"},
+		},
+		"new-flag": map[string]interface{}{
+			"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+			"defaultRule": map[string]interface{}{"variation": "enabled"},
+		},
+	})
+
+	select {
+	case diff := <-diffs:
+		assert.Contains(t, diff.Updated, "bool-flag")
+		assert.Contains(t, diff.Added, "new-flag")
+		assert.Empty(t, diff.Deleted)
+		// the callback is called after the cache is updated, it should see the new value
+		assert.False(t, <-values)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "OnConfigurationChange not called after the configuration change")
+	}
+}
+
+func TestOnConfigurationChangePanic(t *testing.T) {
+	client, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		OnConfigurationChange: func(diff notifier.DiffCache) {
+			panic("callback error")
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	res, err := client.BoolVariation("test-flag", ffcontext.NewEvaluationContext("random-key"), false)
+	assert.NoError(t, err)
+	assert.True(t, res)
+}
+
+func TestOfflineMode(t *testing.T) {
+	exp := &mock.Exporter{}
+	goroutinesBefore := runtime.NumGoroutine()
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		Offline:         true,
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    100 * time.Millisecond,
+			MaxEventInMemory: 1,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+	// in offline mode we should not start any goroutine (polling, data exporter, ...)
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutinesBefore)
+
+	res, err := gffClient.BoolVariationDetails("test-flag", ffcontext.NewEvaluationContext("random-key"), false)
+	assert.NoError(t, err)
+	assert.False(t, res.Value)
+	assert.Equal(t, flag.ReasonOffline, res.Reason)
+	assert.Equal(t, flag.VariationSDKDefault, res.VariationType)
+
+	gffClient.Close()
+	assert.Empty(t, exp.GetExportedEvents())
+	assert.Equal(t, time.Time{}, gffClient.GetCacheRefreshDate())
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	tests := []struct {
+		name                  string
+		path                  string
+		validateConfiguration bool
+		wantErr               assert.ErrorAssertionFunc
+	}{
+		{
+			name:                  "valid configuration",
+			path:                  "testdata/flag-config.yaml",
+			validateConfiguration: true,
+			wantErr:               assert.NoError,
+		},
+		{
+			name:                  "invalid configuration",
+			path:                  "testdata/flag-config-invalid-schema.yaml",
+			validateConfiguration: true,
+			wantErr:               assert.Error,
+		},
+		{
+			name:                  "invalid configuration without validation",
+			path:                  "testdata/flag-config-invalid-schema.yaml",
+			validateConfiguration: false,
+			wantErr:               assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gffClient, err := ffclient.New(ffclient.Config{
+				PollingInterval:       5 * time.Second,
+				Retriever:             &fileretriever.Retriever{Path: tt.path},
+				ValidateConfiguration: tt.validateConfiguration,
+			})
+			tt.wantErr(t, err)
+			if err == nil {
+				gffClient.Close()
+			}
+		})
+	}
+}
+
+func TestClock(t *testing.T) {
+	fixedDate := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	exp := &mock.Exporter{}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		Clock:           exporter.FixedClock{Time: fixedDate},
+		DataExporter: ffclient.DataExporter{
+			Exporter: exp,
+		},
+	})
+	assert.NoError(t, err)
+
+	user := ffcontext.NewEvaluationContext("random-key")
+	_, _ = gffClient.BoolVariation("test-flag", user, false)
+	_, _ = gffClient.BoolVariation("test-flag2", user, false)
+	gffClient.Close()
+
+	events := exp.GetExportedEvents()
+	assert.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, fixedDate.Unix(), event.CreationDate)
+	}
+}
+
 func TestStartWithNegativeInterval(t *testing.T) {
 	_, err := ffclient.New(ffclient.Config{
 		PollingInterval: -60 * time.Second,
@@ -134,6 +391,42 @@ func TestAllFlagsFromCache(t *testing.T) {
 	assert.Len(t, flags, 2)
 }
 
+func TestGetFlagsFromCacheReturnsACopy(t *testing.T) {
+	exp := &mock.Exporter{}
+	gffClient, err := ffclient.New(ffclient.Config{
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		PollingInterval: 5 * time.Second,
+		DataExporter: ffclient.DataExporter{
+			Exporter: exp,
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	flags, err := gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Contains(t, flags, "test-flag")
+	assert.Contains(t, flags, "test-flag2")
+	assert.Empty(t, exp.GetExportedEvents(), "getting the flags from the cache should not export any event")
+
+	// modify the flags returned
+	testFlag, ok := flags["test-flag"].(*flag.InternalFlag)
+	assert.True(t, ok)
+	var newValue interface{} = false
+	(*testFlag.Variations)["True"] = &newValue
+	delete(flags, "test-flag2")
+
+	user := ffcontext.NewEvaluationContext("random-key")
+	hasTestFlag, err := gffClient.BoolVariation("test-flag", user, false)
+	assert.NoError(t, err)
+	assert.True(t, hasTestFlag, "modifying the result of GetFlagsFromCache should not change the cache")
+
+	flagsAfterUpdate, err := gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flagsAfterUpdate, 2)
+}
+
 func TestValidUseCaseToml(t *testing.T) {
 	// Valid use case
 	gffClient, err := ffclient.New(ffclient.Config{
@@ -360,6 +653,67 @@ test-flag:
 	assert.Equal(t, "true", flagValue, "should use the true value")
 }
 
+func TestPersistentFlagConfigurationFile(t *testing.T) {
+	flagContent := `test-flag:
+  variations:
+    A: "persisted"
+    B: "updated"
+  defaultRule:
+    variation: A
+`
+	tempDir, _ := os.MkdirTemp("", "")
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	persistedFile := tempDir + "/persisted-flags.json"
+	flagFilePath := tempDir + "/flag-config.yaml"
+	user := ffcontext.NewEvaluationContext("random-key")
+
+	// a first client is retrieving the flags and persists them.
+	_ = os.WriteFile(flagFilePath, []byte(flagContent), os.ModePerm)
+	gff, err := ffclient.New(ffclient.Config{
+		PollingInterval:                 5 * time.Second,
+		Retriever:                       &fileretriever.Retriever{Path: flagFilePath},
+		PersistentFlagConfigurationFile: persistedFile,
+	})
+	assert.NoError(t, err)
+	assert.False(t, gff.IsUsingPersistedFlags())
+	gff.Close()
+	assert.FileExists(t, persistedFile)
+
+	// the retriever is not reachable anymore, the flags are loaded from the persisted file.
+	_ = os.Remove(flagFilePath)
+	gff, err = ffclient.New(ffclient.Config{
+		PollingInterval:                 1 * time.Second,
+		Retriever:                       &fileretriever.Retriever{Path: flagFilePath},
+		PersistentFlagConfigurationFile: persistedFile,
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+	assert.True(t, gff.IsUsingPersistedFlags())
+	flagValue, _ := gff.StringVariation("test-flag", user, "SDKdefault")
+	assert.Equal(t, "persisted", flagValue)
+
+	// the retriever is reachable again, the flags are refreshed and the snapshot is updated.
+	_ = os.WriteFile(flagFilePath, []byte(strings.ReplaceAll(flagContent, "variation: A", "variation: B")), os.ModePerm)
+	time.Sleep(2 * time.Second)
+	assert.False(t, gff.IsUsingPersistedFlags())
+	flagValue, _ = gff.StringVariation("test-flag", user, "SDKdefault")
+	assert.Equal(t, "updated", flagValue)
+	persisted, err := os.ReadFile(persistedFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(persisted), `"variation":"B"`)
+}
+
+func TestPersistentFlagConfigurationFileMissing(t *testing.T) {
+	tempDir, _ := os.MkdirTemp("", "")
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	_, err := ffclient.New(ffclient.Config{
+		PollingInterval:                 5 * time.Second,
+		Retriever:                       &fileretriever.Retriever{Path: tempDir + "/flag-config.yaml"},
+		PersistentFlagConfigurationFile: tempDir + "/persisted-flags.json",
+	})
+	assert.Error(t, err)
+}
+
 func TestValidUseCaseBigFlagFile(t *testing.T) {
 	// Valid use case
 	gff, err := ffclient.New(ffclient.Config{
@@ -465,3 +819,1134 @@ func TestGoFeatureFlag_GetCacheRefreshDate(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenTelemetryMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval:            5 * time.Second,
+		Retriever:                  &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		OpenTelemetryMeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	_, _ = gffClient.BoolVariation("test-flag", ffcontext.NewEvaluationContext("random-key"), false)
+	_, _ = gffClient.BoolVariation("test-flag", ffcontext.NewEvaluationContext("random-key"), false)
+	_, _ = gffClient.BoolVariation("test-flag", ffcontext.NewEvaluationContext("other-key"), false)
+	_, _ = gffClient.BoolVariation("unknown-flag", ffcontext.NewEvaluationContext("random-key"), false)
+
+	var metrics metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &metrics))
+	if assert.Len(t, metrics.ScopeMetrics, 1) && assert.Len(t, metrics.ScopeMetrics[0].Metrics, 1) {
+		counter := metrics.ScopeMetrics[0].Metrics[0]
+		assert.Equal(t, "gofeatureflag.evaluations", counter.Name)
+		metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{
+					Attributes: attribute.NewSet(
+						attribute.String("flag_key", "test-flag"),
+						attribute.String("variation", "True"),
+						attribute.String("reason", flag.ReasonTargetingMatch),
+					),
+					Value: 2,
+				},
+				{
+					Attributes: attribute.NewSet(
+						attribute.String("flag_key", "test-flag"),
+						attribute.String("variation", "Default"),
+						attribute.String("reason", flag.ReasonDefault),
+					),
+					Value: 1,
+				},
+				{
+					Attributes: attribute.NewSet(
+						attribute.String("flag_key", "unknown-flag"),
+						attribute.String("variation", flag.VariationSDKDefault),
+						attribute.String("reason", flag.ReasonError),
+					),
+					Value: 1,
+				},
+			},
+		}, counter.Data, metricdatatest.IgnoreTimestamp())
+	}
+}
+
+// failingMeterProvider is a metric.MeterProvider returning an error when creating a counter.
+type failingMeterProvider struct{ noop.MeterProvider }
+
+func (failingMeterProvider) Meter(_ string, _ ...metric.MeterOption) metric.Meter {
+	return failingMeter{}
+}
+
+type failingMeter struct{ noop.Meter }
+
+func (failingMeter) Int64Counter(_ string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return nil, errors.New("impossible to create the counter")
+}
+
+func TestOpenTelemetryMetricsInitError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	r := initializableretriever.NewMockInitializableRetriever(path, retriever.RetrieverReady)
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval:            5 * time.Second,
+		Retriever:                  &r,
+		OpenTelemetryMeterProvider: failingMeterProvider{},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, gffClient)
+	// the retriever has not been initialized, nothing is left running
+	assert.NoFileExists(t, path)
+}
+
+func TestOpenTelemetryMetricsNotConfigured(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	// without meter provider the evaluation works as usual
+	res, err := gffClient.BoolVariation("test-flag", ffcontext.NewEvaluationContext("random-key"), false)
+	assert.NoError(t, err)
+	assert.True(t, res)
+}
+
+func TestContextKindInEvents(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Second,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+
+	anonymous := ffcontext.NewEvaluationContextBuilder("anonymous-key").Anonymous(true).Build()
+	identified := ffcontext.NewEvaluationContext("identified-key")
+	_, _ = gffClient.BoolVariation("test-flag", anonymous, false)
+	_, _ = gffClient.BoolVariation("test-flag", identified, false)
+	gffClient.Close()
+
+	events := exp.GetExportedEvents()
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "anonymous-key", events[0].UserKey)
+		assert.Equal(t, "anonymousUser", events[0].ContextKind)
+		assert.Equal(t, "identified-key", events[1].UserKey)
+		assert.Equal(t, "user", events[1].ContextKind)
+	}
+}
+
+func TestCloseExportsRemainingEvents(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 1000,
+			ShutdownTimeout:  time.Second,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 50; i++ {
+		_, _ = gffClient.BoolVariation("test-flag", ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i)), false)
+	}
+	// neither the flush interval nor the max events in memory are reached, nothing is exported yet.
+	assert.Len(t, exp.GetExportedEvents(), 0)
+
+	gffClient.Close()
+	assert.Len(t, exp.GetExportedEvents(), 50)
+}
+
+func TestPrerequisites(t *testing.T) {
+	tests := []struct {
+		name                    string
+		trackPrerequisiteEvents bool
+		wantEvents              int
+	}{
+		{name: "prerequisite events not tracked", trackPrerequisiteEvents: false, wantEvents: 2},
+		{name: "prerequisite events tracked", trackPrerequisiteEvents: true, wantEvents: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := &mock.Exporter{Bulk: true}
+			gffClient, err := ffclient.New(ffclient.Config{
+				PollingInterval: 5 * time.Second,
+				Retriever: &inmemoryretriever.Retriever{
+					Flags: map[string]interface{}{
+						"parent-flag": map[string]interface{}{
+							"variations": map[string]interface{}{"enabled": true, "disabled": false},
+							"targeting": []interface{}{
+								map[string]interface{}{"query": `beta eq true`, "variation": "enabled"},
+							},
+							"defaultRule": map[string]interface{}{"variation": "disabled"},
+						},
+						"child-flag": map[string]interface{}{
+							"variations":    map[string]interface{}{"A": "value_A", "B": "value_B"},
+							"defaultRule":   map[string]interface{}{"variation": "B"},
+							"prerequisites": []interface{}{map[string]interface{}{"flag": "parent-flag", "variation": "enabled"}},
+						},
+					},
+				},
+				TrackPrerequisiteEvents: tt.trackPrerequisiteEvents,
+				DataExporter: ffclient.DataExporter{
+					FlushInterval:    10 * time.Minute,
+					MaxEventInMemory: 100,
+					Exporter:         exp,
+				},
+			})
+			assert.NoError(t, err)
+
+			// prerequisite satisfied
+			betaUser := ffcontext.NewEvaluationContextBuilder("beta-user").AddCustom("beta", true).Build()
+			satisfied, err := gffClient.StringVariationDetails("child-flag", betaUser, "sdk-default")
+			assert.NoError(t, err)
+			assert.Equal(t, "value_B", satisfied.Value)
+			assert.Equal(t, flag.ReasonStatic, satisfied.Reason)
+
+			// prerequisite not satisfied
+			otherUser := ffcontext.NewEvaluationContext("other-user")
+			unsatisfied, err := gffClient.StringVariationDetails("child-flag", otherUser, "sdk-default")
+			assert.NoError(t, err)
+			assert.Equal(t, "sdk-default", unsatisfied.Value)
+			assert.Equal(t, flag.VariationSDKDefault, unsatisfied.VariationType)
+			assert.Equal(t, flag.ReasonPrerequisiteFailed, unsatisfied.Reason)
+			gffClient.Close()
+
+			events := exp.GetExportedEvents()
+			assert.Len(t, events, tt.wantEvents)
+		})
+	}
+}
+
+func TestEventsEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+	}{
+		{name: "no environment configured", environment: ""},
+		{name: "environment configured", environment: "production"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := &mock.Exporter{Bulk: true}
+			gffClient, err := ffclient.New(ffclient.Config{
+				PollingInterval: 5 * time.Second,
+				Environment:     tt.environment,
+				Retriever: &inmemoryretriever.Retriever{
+					Flags: map[string]interface{}{
+						"my-flag": map[string]interface{}{
+							"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+							"defaultRule": map[string]interface{}{"variation": "enabled"},
+						},
+					},
+				},
+				DataExporter: ffclient.DataExporter{
+					FlushInterval:    10 * time.Minute,
+					MaxEventInMemory: 100,
+					Exporter:         exp,
+				},
+			})
+			assert.NoError(t, err)
+
+			_, err = gffClient.BoolVariation("my-flag", ffcontext.NewEvaluationContext("user-key"), false)
+			assert.NoError(t, err)
+			_ = gffClient.AllFlagsState(ffcontext.NewEvaluationContext("user-key"))
+			gffClient.Close()
+
+			events := exp.GetExportedEvents()
+			assert.Len(t, events, 2)
+			for _, event := range events {
+				assert.Equal(t, tt.environment, event.Environment)
+			}
+		})
+	}
+}
+
+func TestDataCollectorSampleRate(t *testing.T) {
+	seed := int64(42)
+	exportSampledEvents := func() []exporter.FeatureEvent {
+		exp := &mock.Exporter{Bulk: true}
+		gffClient, err := ffclient.New(ffclient.Config{
+			PollingInterval:         5 * time.Second,
+			DataCollectorSampleRate: 0.5,
+			DataCollectorSampleSeed: &seed,
+			Clock:                   exporter.FixedClock{Time: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			Retriever: &inmemoryretriever.Retriever{
+				Flags: map[string]interface{}{
+					"my-flag": map[string]interface{}{
+						"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+						"defaultRule": map[string]interface{}{"variation": "enabled"},
+					},
+				},
+			},
+			DataExporter: ffclient.DataExporter{
+				FlushInterval:    10 * time.Minute,
+				MaxEventInMemory: 10000,
+				Exporter:         exp,
+			},
+		})
+		assert.NoError(t, err)
+		for i := 0; i < 1000; i++ {
+			_, err = gffClient.BoolVariation("my-flag", ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i)), false)
+			assert.NoError(t, err)
+		}
+		gffClient.Close()
+		return exp.GetExportedEvents()
+	}
+
+	events := exportSampledEvents()
+	assert.InDelta(t, 500, len(events), 50, "roughly half of the events should be exported")
+	for _, event := range events {
+		assert.Equal(t, float64(2), event.SamplingWeight)
+	}
+	// with a seed the sampling is deterministic.
+	assert.ElementsMatch(t, events, exportSampledEvents())
+}
+
+func TestDataCollectorSampleRateInvalid(t *testing.T) {
+	_, err := ffclient.New(ffclient.Config{
+		PollingInterval:         5 * time.Second,
+		DataCollectorSampleRate: 1.5,
+		Retriever:               &inmemoryretriever.Retriever{},
+	})
+	assert.EqualError(t, err, "1.5 is not a valid DataCollectorSampleRate value, it needs to be between 0 and 1")
+}
+
+// yamlRetriever is a retriever returning a YAML flag file and providing its format.
+type yamlRetriever struct {
+	content string
+}
+
+func (r *yamlRetriever) Retrieve(_ context.Context) ([]byte, error) {
+	return []byte(r.content), nil
+}
+
+func (r *yamlRetriever) Format() string {
+	return "yaml"
+}
+
+func TestRetrieversWithDifferentFormats(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		// the default format is not used since both retrievers provide their format.
+		FileFormat: "toml",
+		Retrievers: []retriever.Retriever{
+			&yamlRetriever{content: `yaml-flag:
+  variations:
+    enabled: true
+    disabled: false
+  defaultRule:
+    variation: enabled
+`},
+			&inmemoryretriever.Retriever{Flags: map[string]interface{}{
+				"json-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"A": "value-A", "B": "value-B"},
+					"defaultRule": map[string]interface{}{"variation": "B"},
+				},
+			}},
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	user := ffcontext.NewEvaluationContext("random-key")
+	yamlValue, err := gffClient.BoolVariation("yaml-flag", user, false)
+	assert.NoError(t, err)
+	assert.True(t, yamlValue)
+
+	jsonValue, err := gffClient.StringVariation("json-flag", user, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value-B", jsonValue)
+}
+
+// deltaRetriever is a retriever returning a new delta document on each call.
+type deltaRetriever struct {
+	mutex  sync.Mutex
+	deltas []string
+}
+
+func (r *deltaRetriever) Retrieve(_ context.Context) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(r.deltas) == 0 {
+		return []byte(`{}`), nil
+	}
+	delta := r.deltas[0]
+	r.deltas = r.deltas[1:]
+	return []byte(delta), nil
+}
+
+func (r *deltaRetriever) IsDelta() bool {
+	return true
+}
+
+func TestDeltaRetriever(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		FileFormat:      "json",
+		Retrievers: []retriever.Retriever{
+			&deltaRetriever{deltas: []string{
+				`{"upsert": {
+					"flag-a": {"variations": {"A": "a"}, "defaultRule": {"variation": "A"}},
+					"flag-b": {"variations": {"A": "b"}, "defaultRule": {"variation": "A"}}
+				}}`,
+				`{"upsert": {
+					"flag-b": {"variations": {"A": "b-updated"}, "defaultRule": {"variation": "A"}},
+					"flag-c": {"variations": {"A": "c"}, "defaultRule": {"variation": "A"}}
+				}, "delete": ["flag-a"]}`,
+			}},
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	flags, err := gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Contains(t, flags, "flag-a")
+	assert.Contains(t, flags, "flag-b")
+
+	// the second delta is applied on top of the first one.
+	time.Sleep(1500 * time.Millisecond)
+	flags, err = gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.NotContains(t, flags, "flag-a")
+	assert.Equal(t, "b-updated", flags["flag-b"].GetVariationValue("A"))
+	assert.Equal(t, "c", flags["flag-c"].GetVariationValue("A"))
+}
+
+func TestDeltaRetrieverWithFullRetriever(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		FileFormat:      "json",
+		Retrievers: []retriever.Retriever{
+			&inmemoryretriever.Retriever{Flags: map[string]interface{}{
+				"flag-full": map[string]interface{}{
+					"variations":  map[string]interface{}{"A": "full"},
+					"defaultRule": map[string]interface{}{"variation": "A"},
+				},
+			}},
+			&deltaRetriever{deltas: []string{
+				`{"upsert": {"flag-a": {"variations": {"A": "a"}, "defaultRule": {"variation": "A"}}}}`,
+				`{"upsert": {"flag-b": {"variations": {"A": "b"}, "defaultRule": {"variation": "A"}}}}`,
+			}},
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	flags, err := gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Contains(t, flags, "flag-full")
+	assert.Contains(t, flags, "flag-a")
+
+	// the deltas of the previous refreshes are applied on top of the full configuration retrieved again.
+	time.Sleep(2500 * time.Millisecond)
+	flags, err = gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flags, 3)
+	assert.Contains(t, flags, "flag-full")
+	assert.Contains(t, flags, "flag-a")
+	assert.Contains(t, flags, "flag-b")
+}
+
+func TestMetadataInDetailsAndExportedSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{Flags: map[string]interface{}{
+			"flag-with-metadata": map[string]interface{}{
+				"variations":  map[string]interface{}{"A": "value-A", "B": "value-B"},
+				"defaultRule": map[string]interface{}{"variation": "A"},
+				"metadata": map[string]interface{}{
+					"owner":       "team-a",
+					"jira":        "FF-123",
+					"description": "flag used to test the metadata",
+				},
+			},
+		}},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Second,
+			MaxEventInMemory: 1000,
+			Exporter:         opentelemetryexporter.NewExporter(opentelemetryexporter.WithTracerProvider(provider)),
+		},
+	})
+	assert.NoError(t, err)
+
+	details, err := gffClient.StringVariationDetails("flag-with-metadata",
+		ffcontext.NewEvaluationContext("random-key"), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"owner":       "team-a",
+		"jira":        "FF-123",
+		"description": "flag used to test the metadata",
+	}, details.Metadata)
+
+	// closing the client flushes the events to the exporter.
+	gffClient.Close()
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("gofeatureflag.metadata.owner", "team-a"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("gofeatureflag.metadata.jira", "FF-123"))
+	assert.Contains(t, spans[0].Attributes(),
+		attribute.String("gofeatureflag.metadata.description", "flag used to test the metadata"))
+}
+
+func TestSSERetrieverPushUpdates(t *testing.T) {
+	events := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case value := <-events:
+				_, _ = fmt.Fprintf(w, "event: flags\ndata: {\"sse-flag\": "+
+					"{\"variations\": {\"A\": \"%s\"}, \"defaultRule\": {\"variation\": \"A\"}}}\n\n", value)
+				w.(http.Flusher).Flush()
+			case <-req.Context().Done():
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	events <- "initial"
+	gffClient, err := ffclient.New(ffclient.Config{
+		// the polling interval is long, the updates should be applied as soon as they are pushed
+		PollingInterval: 1 * time.Minute,
+		FileFormat:      "json",
+		Retriever:       &sseretriever.Retriever{URL: server.URL},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	user := ffcontext.NewEvaluationContext("random-key")
+	value, _ := gffClient.StringVariation("sse-flag", user, "default")
+	assert.Equal(t, "initial", value)
+
+	for _, update := range []string{"first-update", "second-update"} {
+		events <- update
+		assert.Eventually(t, func() bool {
+			value, _ := gffClient.StringVariation("sse-flag", user, "default")
+			return value == update
+		}, 2*time.Second, 10*time.Millisecond)
+	}
+}
+
+func TestWebhookRetrieverPushedConfiguration(t *testing.T) {
+	r := &webhookretriever.Retriever{FileFormat: "json", Token: "secret"}
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	gffClient, err := ffclient.New(ffclient.Config{
+		// the polling interval is long, the configuration should be applied as soon as it is pushed
+		PollingInterval: 1 * time.Minute,
+		Retriever:       r,
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	user := ffcontext.NewEvaluationContext("random-key")
+	value, _ := gffClient.StringVariation("pushed-flag", user, "default")
+	assert.Equal(t, "default", value, "no configuration pushed yet")
+
+	push := func(config string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(config))
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusAccepted,
+		push(`{"pushed-flag": {"variations": {"A": "pushed"}, "defaultRule": {"variation": "A"}}}`))
+	assert.Eventually(t, func() bool {
+		value, _ := gffClient.StringVariation("pushed-flag", user, "default")
+		return value == "pushed"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// an invalid configuration is rejected and the current flags are kept
+	assert.Equal(t, http.StatusBadRequest,
+		push(`{"pushed-flag": {"variations": {"A": "invalid"}, "defaultRule": {"variation": "B"}}}`))
+	time.Sleep(50 * time.Millisecond)
+	value, _ = gffClient.StringVariation("pushed-flag", user, "default")
+	assert.Equal(t, "pushed", value)
+}
+
+func TestYAMLAnchorsAndFragments(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config-yaml-anchors.yaml"},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	// the fragments are not flags
+	flags, err := gffClient.GetFlagsFromCache()
+	assert.NoError(t, err)
+	assert.Len(t, flags, 2)
+
+	betaUser := ffcontext.NewEvaluationContextBuilder("beta-user").AddCustom("beta", true).Build()
+	otherUser := ffcontext.NewEvaluationContext("other-user")
+	for _, flagKey := range []string{"new-checkout", "new-search"} {
+		details, err := gffClient.BoolVariationDetails(flagKey, betaUser, false)
+		assert.NoError(t, err)
+		assert.True(t, details.Value)
+		assert.Equal(t, "enabled", details.VariationType)
+		assert.Equal(t, flag.ReasonTargetingMatch, details.Reason)
+
+		details, err = gffClient.BoolVariationDetails(flagKey, otherUser, true)
+		assert.NoError(t, err)
+		assert.False(t, details.Value)
+		assert.Equal(t, flag.ReasonDefault, details.Reason)
+	}
+}
+
+func TestEnvironmentsOverride(t *testing.T) {
+	flags := map[string]interface{}{
+		"checkout-flow": map[string]interface{}{
+			"variations":  map[string]interface{}{"treatment": "treatment", "control": "control"},
+			"defaultRule": map[string]interface{}{"variation": "treatment"},
+			"environments": map[string]interface{}{
+				"prod": map[string]interface{}{
+					"defaultRule": map[string]interface{}{"variation": "control"},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		environment string
+		want        string
+	}{
+		{name: "environment without override uses the base definition", environment: "dev", want: "treatment"},
+		{name: "no environment uses the base definition", environment: "", want: "treatment"},
+		{name: "environment with override", environment: "prod", want: "control"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gffClient, err := ffclient.New(ffclient.Config{
+				PollingInterval: 5 * time.Second,
+				Environment:     tt.environment,
+				Retriever:       &inmemoryretriever.Retriever{Flags: flags},
+			})
+			assert.NoError(t, err)
+			defer gffClient.Close()
+
+			got, err := gffClient.StringVariation("checkout-flow", ffcontext.NewEvaluationContext("user-key"), "default")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			allFlags := gffClient.AllFlagsState(ffcontext.NewEvaluationContext("user-key"))
+			assert.Equal(t, tt.want, allFlags.GetFlags()["checkout-flow"].Value)
+		})
+	}
+}
+
+// stubHasher returns the hash configured for each bucketing key.
+type stubHasher struct {
+	hashes map[string]uint32
+}
+
+func (s stubHasher) Hash(key string) uint32 {
+	return s.hashes[key]
+}
+
+func TestBucketingHasher(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"split-flag": map[string]interface{}{
+					"variations": map[string]interface{}{"A": "value-a", "B": "value-b"},
+					"defaultRule": map[string]interface{}{
+						"percentage": map[string]interface{}{"A": 50, "B": 50},
+					},
+				},
+			},
+		},
+		BucketingHasher: stubHasher{hashes: map[string]uint32{
+			// the bucket B is [0, 50000[ and the bucket A is [50000, 100000[
+			"split-flaguser-1": 10,
+			"split-flaguser-2": 60000,
+			"split-flaguser-3": 149999,
+		}},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	for key, want := range map[string]string{"user-1": "value-b", "user-2": "value-a", "user-3": "value-b"} {
+		got, err := gffClient.StringVariation("split-flag", ffcontext.NewEvaluationContext(key), "default")
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, "the variation of %s should follow the injected hash", key)
+	}
+}
+
+func TestContextEnricher(t *testing.T) {
+	tiers := map[string]string{"user-premium": "premium"}
+	nbCalls := 0
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"premium-flag": map[string]interface{}{
+					"variations": map[string]interface{}{"enabled": true, "disabled": false},
+					"targeting": []interface{}{
+						map[string]interface{}{"query": `tier eq "premium"`, "variation": "enabled"},
+					},
+					"defaultRule": map[string]interface{}{"variation": "disabled"},
+				},
+				"other-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+			},
+		},
+		ContextEnricher: func(evaluationCtx ffcontext.Context) (ffcontext.Context, error) {
+			nbCalls++
+			tier, ok := tiers[evaluationCtx.GetKey()]
+			if !ok {
+				return nil, fmt.Errorf("unknown user %s", evaluationCtx.GetKey())
+			}
+			builder := ffcontext.NewEvaluationContextBuilder(evaluationCtx.GetKey())
+			for name, value := range evaluationCtx.GetCustom() {
+				builder.AddCustom(name, value)
+			}
+			return builder.AddCustom("tier", tier).Build(), nil
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	premiumUser := ffcontext.NewEvaluationContext("user-premium")
+	res, err := gffClient.BoolVariationDetails("premium-flag", premiumUser, false)
+	assert.NoError(t, err)
+	assert.True(t, res.Value)
+	assert.Equal(t, flag.ReasonTargetingMatch, res.Reason)
+	assert.Equal(t, 1, nbCalls)
+	// the evaluation context of the caller is not modified
+	assert.NotContains(t, premiumUser.GetCustom(), "tier")
+
+	// an error of the enricher does not fail the evaluation, the original context is used
+	res, err = gffClient.BoolVariationDetails("premium-flag", ffcontext.NewEvaluationContext("user-unknown"), false)
+	assert.NoError(t, err)
+	assert.False(t, res.Value)
+	assert.Equal(t, flag.ReasonDefault, res.Reason)
+
+	// the enricher is called once for all the flags
+	nbCalls = 0
+	allFlags := gffClient.AllFlagsState(premiumUser)
+	assert.True(t, allFlags.IsValid())
+	assert.Equal(t, true, allFlags.GetFlags()["premium-flag"].Value)
+	assert.Equal(t, 1, nbCalls)
+}
+
+func TestArchivedFlag(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"archived-flag": map[string]interface{}{
+					"archived":    true,
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+				"active-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	user := ffcontext.NewEvaluationContext("user-key")
+
+	// an archived flag is still evaluated and serves the SDK default value without error
+	res, err := gffClient.BoolVariationDetails("archived-flag", user, false)
+	assert.NoError(t, err)
+	assert.False(t, res.Value)
+	assert.Equal(t, flag.ReasonDisabled, res.Reason)
+	assert.Empty(t, res.ErrorCode)
+
+	allFlags := gffClient.AllFlagsState(user)
+	assert.True(t, allFlags.IsValid())
+	assert.NotContains(t, allFlags.GetFlags(), "archived-flag")
+	assert.Contains(t, allFlags.GetFlags(), "active-flag")
+}
+
+func TestAdditionalDataExportersFilters(t *testing.T) {
+	fileExporter := &mock.Exporter{Bulk: true}
+	otelExporter := &mock.Exporter{Bulk: true}
+	boolFlag := map[string]interface{}{
+		"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+		"defaultRule": map[string]interface{}{"variation": "enabled"},
+	}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"important-flag": boolFlag,
+				"other-flag":     boolFlag,
+				"noisy-flag":     boolFlag,
+			},
+		},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 100,
+			ExcludeFlags:     []string{"noisy-flag"},
+			Exporter:         fileExporter,
+		},
+		AdditionalDataExporters: []ffclient.DataExporter{
+			{
+				FlushInterval:    10 * time.Minute,
+				MaxEventInMemory: 100,
+				IncludeFlags:     []string{"important-flag"},
+				Exporter:         otelExporter,
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	user := ffcontext.NewEvaluationContext("user-key")
+	for _, flagKey := range []string{"important-flag", "other-flag", "noisy-flag"} {
+		_, err := gffClient.BoolVariation(flagKey, user, false)
+		assert.NoError(t, err)
+	}
+	// the events in memory are exported when the client is closed
+	gffClient.Close()
+
+	exportedKeys := func(exp *mock.Exporter) []string {
+		keys := make([]string, 0)
+		for _, event := range exp.GetExportedEvents() {
+			keys = append(keys, event.Key)
+		}
+		return keys
+	}
+	assert.ElementsMatch(t, []string{"important-flag", "other-flag"}, exportedKeys(fileExporter))
+	assert.ElementsMatch(t, []string{"important-flag"}, exportedKeys(otelExporter))
+}
+
+func TestJSON5FlagFile(t *testing.T) {
+	flagFile, err := os.CreateTemp("", "flags-*.json5")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(flagFile.Name()) }()
+	_ = os.WriteFile(flagFile.Name(), []byte(`{
+  // owned by the ops team
+  "commented-flag": {
+    "variations": {
+      "enabled": true,
+      "disabled": false, // trailing comma
+    },
+    /* the beta testers
+       get the new feature */
+    "targeting": [
+      {"query": "beta eq true", "variation": "enabled"},
+    ],
+    "defaultRule": {"variation": "disabled"},
+  },
+}
+`), os.ModePerm)
+
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile.Name()},
+		FileFormat:      "json5",
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	beta := ffcontext.NewEvaluationContextBuilder("user-key").AddCustom("beta", true).Build()
+	got, err := gffClient.BoolVariation("commented-flag", beta, false)
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = gffClient.BoolVariation("commented-flag", ffcontext.NewEvaluationContext("user-key"), true)
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestAdditionalDataExportersFlushIndependently(t *testing.T) {
+	fastExporter := &mock.Exporter{Bulk: true}
+	slowExporter := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"test-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+			},
+		},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    1 * time.Hour,
+			MaxEventInMemory: 1000,
+			EventBufferSize:  100,
+			Exporter:         slowExporter,
+		},
+		AdditionalDataExporters: []ffclient.DataExporter{
+			{
+				FlushInterval:    20 * time.Millisecond,
+				MaxEventInMemory: 1000,
+				EventBufferSize:  100,
+				Exporter:         fastExporter,
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := gffClient.BoolVariation("test-flag", ffcontext.NewEvaluationContext("user-key"), false)
+		assert.NoError(t, err)
+	}
+
+	// only the exporter with the short flush interval has exported the events.
+	time.Sleep(200 * time.Millisecond)
+	assert.Len(t, fastExporter.GetExportedEvents(), 3)
+	assert.Len(t, slowExporter.GetExportedEvents(), 0)
+
+	gffClient.Close()
+	assert.Len(t, slowExporter.GetExportedEvents(), 3)
+}
+
+func TestFlagVersion(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"versioned-flag": map[string]interface{}{
+					"version":     "1.2.0",
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+				"unversioned-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+			},
+		},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx := ffcontext.NewEvaluationContext("user-key")
+	versioned, err := gffClient.BoolVariationDetails("versioned-flag", ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", versioned.Version)
+
+	unversioned, err := gffClient.BoolVariationDetails("unversioned-flag", ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "", unversioned.Version)
+
+	allFlags := gffClient.AllFlagsState(ctx)
+	assert.Equal(t, "1.2.0", allFlags.GetFlags()["versioned-flag"].Version)
+	assert.Equal(t, "", allFlags.GetFlags()["unversioned-flag"].Version)
+	gffClient.Close()
+
+	events := exp.GetExportedEvents()
+	assert.Len(t, events, 4)
+	for _, event := range events {
+		if event.Key == "versioned-flag" {
+			assert.Equal(t, "1.2.0", event.Version)
+		} else {
+			assert.Equal(t, "", event.Version)
+		}
+	}
+}
+
+func TestMultiKindContext(t *testing.T) {
+	flagFile, err := os.CreateTemp("", "multi-kind-*.yaml")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(flagFile.Name()) }()
+	_ = os.WriteFile(flagFile.Name(), []byte(`enterprise-flag:
+  variations:
+    enterprise: "enterprise"
+    mobile: "mobile"
+    default: "default"
+  targeting:
+    - query: org.plan eq "enterprise"
+      variation: enterprise
+    - query: device pr
+      variation: mobile
+  defaultRule:
+    variation: default
+`), os.ModePerm)
+
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile.Name()},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	user := ffcontext.NewEvaluationContextBuilder("user-key").AddCustom("plan", "free").Build()
+	tests := []struct {
+		name string
+		ctx  ffcontext.Context
+		want string
+	}{
+		{
+			name: "rule keyed on the org kind matches",
+			ctx: ffcontext.NewMultiContextBuilder().
+				AddContext("user", user).
+				AddContext("org", ffcontext.NewEvaluationContextBuilder("org-key").AddCustom("plan", "enterprise").Build()).
+				Build(),
+			want: "enterprise",
+		},
+		{
+			name: "attribute of another kind is not used",
+			ctx:  ffcontext.NewMultiContextBuilder().AddContext("user", user).Build(),
+			want: "default",
+		},
+		{
+			name: "rule keyed on the presence of a kind",
+			ctx: ffcontext.NewMultiContextBuilder().
+				AddContext("user", user).
+				AddContext("device", ffcontext.NewEvaluationContext("device-key")).
+				Build(),
+			want: "mobile",
+		},
+		{
+			name: "single context does not match the kind rules",
+			ctx:  user,
+			want: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gffClient.StringVariation("enterprise-flag", tt.ctx, "sdk-default")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// blockingRetriever returns the flags on the first call and blocks the next calls
+// until their context is cancelled.
+type blockingRetriever struct {
+	mutex   sync.Mutex
+	calls   int
+	blocked chan struct{}
+	ctxErr  error
+}
+
+func (r *blockingRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	r.mutex.Lock()
+	r.calls++
+	firstCall := r.calls == 1
+	r.mutex.Unlock()
+	if firstCall {
+		return []byte(`test-flag:
+  variations:
+    A: true
+  defaultRule:
+    variation: A
+`), nil
+	}
+
+	select {
+	case r.blocked <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ctxErr = ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestCloseCancelsInFlightRetrieval(t *testing.T) {
+	r := &blockingRetriever{blocked: make(chan struct{}, 1)}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       r,
+	})
+	assert.NoError(t, err)
+
+	// wait for the refresh to be blocked in the retriever
+	select {
+	case <-r.blocked:
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "the retriever has not been called by the refresh")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		gffClient.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(500 * time.Millisecond):
+		assert.Fail(t, "Close is blocked by the retriever")
+	}
+
+	// the retriever has received the cancellation of its context
+	assert.Eventually(t, func() bool {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		return errors.Is(r.ctxErr, context.Canceled)
+	}, 1*time.Second, 10*time.Millisecond)
+	// the abandoned refresh is not recorded as a failure
+	assert.NoError(t, gffClient.Health().LastRefreshError)
+}
+
+func TestStrictValidation(t *testing.T) {
+	flagFile, err := os.CreateTemp("", "strict-*.yaml")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(flagFile.Name()) }()
+	_ = os.WriteFile(flagFile.Name(), []byte(`bad-reference-flag:
+  variations:
+    enabled: true
+    disabled: false
+  targeting:
+    - query: beta eq true
+      variation: enable
+  defaultRule:
+    variation: disabled
+`), os.ModePerm)
+
+	// strict mode: the configuration is rejected with the unknown reference
+	_, err = ffclient.New(ffclient.Config{
+		PollingInterval:  5 * time.Second,
+		Retriever:        &fileretriever.Retriever{Path: flagFile.Name()},
+		StrictValidation: true,
+	})
+	assert.ErrorContains(t, err,
+		"invalid flag bad-reference-flag: targeting[0].variation: variation enable does not exist")
+
+	// lenient mode: the configuration is loaded and the rule serves the SDK default value
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile.Name()},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	beta := ffcontext.NewEvaluationContextBuilder("user-key").AddCustom("beta", true).Build()
+	got, _ := gffClient.BoolVariation("bad-reference-flag", beta, true)
+	assert.True(t, got)
+	got, err = gffClient.BoolVariation("bad-reference-flag", ffcontext.NewEvaluationContext("user-key"), true)
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
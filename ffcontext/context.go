@@ -11,6 +11,15 @@ type Context interface {
 	AddCustomAttribute(name string, value interface{})
 }
 
+// GetSecondaryKey returns the secondary key of the context used to compute its bucket in the percentage rollouts.
+// It returns an empty string if the context has no secondary key.
+func GetSecondaryKey(ctx Context) string {
+	if secondaryCtx, ok := ctx.(interface{ GetSecondaryKey() string }); ok {
+		return secondaryCtx.GetSecondaryKey()
+	}
+	return ""
+}
+
 // value is a type to define custom attribute.
 type value map[string]interface{}
 
@@ -42,6 +51,8 @@ func NewAnonymousEvaluationContext(key string) EvaluationContext {
 type EvaluationContext struct {
 	key    string // only mandatory attribute
 	custom value
+	// secondary is mixed with the key to compute the bucket of the context in the percentage rollouts.
+	secondary string
 }
 
 // GetKey return the unique key for the user.
@@ -60,6 +71,13 @@ func (u EvaluationContext) IsAnonymous() bool {
 	}
 }
 
+// GetSecondaryKey return the secondary key of the user.
+// The secondary key is mixed with the key to compute the bucket of the user in the percentage rollouts.
+// It returns an empty string if no secondary key is set.
+func (u EvaluationContext) GetSecondaryKey() string {
+	return u.secondary
+}
+
 // GetCustom return all the custom properties of a user.
 func (u EvaluationContext) GetCustom() map[string]interface{} {
 	return u.custom
@@ -16,26 +16,44 @@ type EvaluationContextBuilder interface {
 	// Deprecated: Anonymous is to flag the context for an anonymous context or not.
 	// This function is here for compatibility reason, please consider to use AddCustom("anonymous", true)
 	// instead of using this function.
+	//
+	// The attribute "anonymous" is used to set the ContextKind of the events
+	// and to bucket the anonymous users separately if the flag has an anonymousBucketingSalt.
 	Anonymous(bool) EvaluationContextBuilder
 
+	// Secondary is an optional secondary key mixed with the key to compute the bucket of the context
+	// in the percentage rollouts.
+	// It is not part of the custom attributes, without it the bucketing only depends on the key.
+	Secondary(string) EvaluationContextBuilder
+
 	AddCustom(string, interface{}) EvaluationContextBuilder
 	Build() EvaluationContext
 }
 
 type evaluationContextBuilderImpl struct {
 	// Key is the only mandatory attribute
-	key    string
-	custom value
+	key       string
+	custom    value
+	secondary string
 }
 
 // Deprecated: Anonymous is to flag the context for an anonymous context or not.
 // This function is here for compatibility reason, please consider using AddCustom("anonymous", true)
 // instead of using this function.
+//
+// The attribute "anonymous" is used to set the ContextKind of the events
+// and to bucket the anonymous users separately if the flag has an anonymousBucketingSalt.
 func (u *evaluationContextBuilderImpl) Anonymous(anonymous bool) EvaluationContextBuilder {
 	u.custom["anonymous"] = anonymous
 	return u
 }
 
+// Secondary sets the secondary key used to compute the bucket of the context.
+func (u *evaluationContextBuilderImpl) Secondary(secondary string) EvaluationContextBuilder {
+	u.secondary = secondary
+	return u
+}
+
 // AddCustom allows you to add a custom attribute to the EvaluationContext.
 func (u *evaluationContextBuilderImpl) AddCustom(key string, value interface{}) EvaluationContextBuilder {
 	u.custom[key] = value
@@ -45,7 +63,8 @@ func (u *evaluationContextBuilderImpl) AddCustom(key string, value interface{})
 // Build is creating the EvaluationContext.
 func (u *evaluationContextBuilderImpl) Build() EvaluationContext {
 	return EvaluationContext{
-		key:    u.key,
-		custom: u.custom,
+		key:       u.key,
+		custom:    u.custom,
+		secondary: u.secondary,
 	}
 }
@@ -46,6 +46,17 @@ func TestNewUser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Builder with secondary key",
+			got: NewEvaluationContextBuilder("random-key").
+				Secondary("secondary-key").
+				Build(),
+			want: EvaluationContext{
+				key:       "random-key",
+				custom:    map[string]interface{}{},
+				secondary: "secondary-key",
+			},
+		},
 		{
 			name: "NewUser with key",
 			got:  NewEvaluationContext("random-key"),
@@ -0,0 +1,129 @@
+package ffcontext
+
+// MultiKind is the value of the attribute "kind" of a MultiContext.
+const MultiKind = "multi"
+
+// MultiContext is an evaluation context made of several contexts of different kinds (ex: user, org, device).
+//
+// In the targeting queries, the attributes of a kind are prefixed by the name of the kind
+// (ex: org.plan eq "enterprise"), and a rule using a kind absent from the context does not match.
+// The presence of a kind can be checked with the pr operator (ex: device pr).
+//
+// The key of the targeting kind (the first kind added to the builder) is the key of the MultiContext,
+// it is used as targeting key to compute the bucket of the context in the percentage rollouts.
+//
+// To construct a MultiContext, use NewMultiContextBuilder.
+type MultiContext struct {
+	kinds         map[string]Context
+	targetingKind string
+	// custom contains the attributes of the context, it is computed once when the MultiContext is built.
+	custom value
+}
+
+// GetKey return the key of the context of the targeting kind.
+func (m MultiContext) GetKey() string {
+	if ctx, ok := m.kinds[m.targetingKind]; ok {
+		return ctx.GetKey()
+	}
+	return ""
+}
+
+// IsAnonymous return if the context of the targeting kind is anonymous or not.
+func (m MultiContext) IsAnonymous() bool {
+	if ctx, ok := m.kinds[m.targetingKind]; ok {
+		return ctx.IsAnonymous()
+	}
+	return false
+}
+
+// GetSecondaryKey return the secondary key of the context of the targeting kind,
+// it is used to compute the bucket of the MultiContext.
+func (m MultiContext) GetSecondaryKey() string {
+	if ctx, ok := m.kinds[m.targetingKind]; ok {
+		return GetSecondaryKey(ctx)
+	}
+	return ""
+}
+
+// GetCustom return the attributes of the context, the attributes of each kind are in a map
+// named as the kind (ex: {"kind": "multi", "org": {"key": "org-1", "plan": "enterprise"}}).
+func (m MultiContext) GetCustom() map[string]interface{} {
+	return m.custom
+}
+
+// AddCustomAttribute allows to add a custom attribute at the top level of the context,
+// it is not added to the contexts of the kinds.
+// The name of a kind and the attribute "kind" are reserved, the attribute is ignored if it uses one of them.
+func (m MultiContext) AddCustomAttribute(name string, value interface{}) {
+	if _, isKind := m.kinds[name]; name == "" || name == "kind" || isKind {
+		return
+	}
+	m.custom[name] = value
+}
+
+// GetContext returns the context of a kind and false if the kind is not part of the MultiContext.
+func (m MultiContext) GetContext(kind string) (Context, bool) {
+	ctx, ok := m.kinds[kind]
+	return ctx, ok
+}
+
+// GetTargetingKind returns the kind whose key is used as key of the MultiContext.
+func (m MultiContext) GetTargetingKind() string {
+	return m.targetingKind
+}
+
+// NewMultiContextBuilder constructs a new MultiContextBuilder.
+func NewMultiContextBuilder() MultiContextBuilder {
+	return &multiContextBuilderImpl{
+		kinds: map[string]Context{},
+	}
+}
+
+// MultiContextBuilder is a builder to create a MultiContext.
+type MultiContextBuilder interface {
+	// AddContext adds the context of a kind (ex: "user", "org", "device").
+	// The first kind added is the targeting kind of the MultiContext, adding an existing kind replaces its context.
+	AddContext(kind string, ctx Context) MultiContextBuilder
+	Build() MultiContext
+}
+
+type multiContextBuilderImpl struct {
+	kinds         map[string]Context
+	targetingKind string
+}
+
+// AddContext adds the context of a kind to the MultiContext.
+func (m *multiContextBuilderImpl) AddContext(kind string, ctx Context) MultiContextBuilder {
+	if kind == "" || ctx == nil {
+		return m
+	}
+	if m.targetingKind == "" {
+		m.targetingKind = kind
+	}
+	m.kinds[kind] = ctx
+	return m
+}
+
+// Build is creating the MultiContext.
+// The kinds are copied, adding a context to the builder after the build has no impact on the MultiContext.
+func (m *multiContextBuilderImpl) Build() MultiContext {
+	kinds := make(map[string]Context, len(m.kinds))
+	custom := make(map[string]interface{}, len(m.kinds)+1)
+	for kind, ctx := range m.kinds {
+		kinds[kind] = ctx
+		kindAttributes := make(map[string]interface{}, len(ctx.GetCustom())+3)
+		for key, val := range ctx.GetCustom() {
+			kindAttributes[key] = val
+		}
+		kindAttributes["key"] = ctx.GetKey()
+		kindAttributes["anonymous"] = ctx.IsAnonymous()
+		kindAttributes["kind"] = kind
+		custom[kind] = kindAttributes
+	}
+	custom["kind"] = MultiKind
+	return MultiContext{
+		kinds:         kinds,
+		targetingKind: m.targetingKind,
+		custom:        custom,
+	}
+}
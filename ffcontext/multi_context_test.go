@@ -0,0 +1,80 @@
+package ffcontext_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+)
+
+func TestMultiContext(t *testing.T) {
+	user := ffcontext.NewEvaluationContextBuilder("user-key").Anonymous(true).Secondary("team-a").Build()
+	org := ffcontext.NewEvaluationContextBuilder("org-key").AddCustom("plan", "enterprise").Build()
+	ctx := ffcontext.NewMultiContextBuilder().
+		AddContext("user", user).
+		AddContext("org", org).
+		AddContext("", ffcontext.NewEvaluationContext("ignored")).
+		Build()
+	ctx.AddCustomAttribute("env", "prod")
+
+	assert.Equal(t, "user", ctx.GetTargetingKind())
+	assert.Equal(t, "user-key", ctx.GetKey())
+	assert.True(t, ctx.IsAnonymous())
+	assert.Equal(t, "team-a", ctx.GetSecondaryKey())
+
+	got, ok := ctx.GetContext("org")
+	assert.True(t, ok)
+	assert.Equal(t, org, got)
+	_, ok = ctx.GetContext("device")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]interface{}{
+		"kind": ffcontext.MultiKind,
+		"env":  "prod",
+		"user": map[string]interface{}{
+			"key":       "user-key",
+			"anonymous": true,
+			"kind":      "user",
+		},
+		"org": map[string]interface{}{
+			"key":       "org-key",
+			"anonymous": false,
+			"plan":      "enterprise",
+			"kind":      "org",
+		},
+	}, ctx.GetCustom())
+}
+
+func TestMultiContext_immutableKinds(t *testing.T) {
+	builder := ffcontext.NewMultiContextBuilder().
+		AddContext("user", ffcontext.NewEvaluationContext("user-key"))
+	ctx := builder.Build()
+
+	// adding a kind to the builder after the build does not change the context
+	builder.AddContext("org", ffcontext.NewEvaluationContext("org-key"))
+	_, ok := ctx.GetContext("org")
+	assert.False(t, ok)
+	_, ok = ctx.GetCustom()["org"]
+	assert.False(t, ok)
+
+	// the top level attributes can't override the kinds
+	ctx.AddCustomAttribute("user", "overridden")
+	ctx.AddCustomAttribute("kind", "overridden")
+	ctx.AddCustomAttribute("env", "prod")
+	assert.Equal(t, map[string]interface{}{
+		"kind": ffcontext.MultiKind,
+		"env":  "prod",
+		"user": map[string]interface{}{
+			"key":       "user-key",
+			"anonymous": false,
+			"kind":      "user",
+		},
+	}, ctx.GetCustom())
+}
+
+func TestMultiContext_noTargetingKind(t *testing.T) {
+	ctx := ffcontext.NewMultiContextBuilder().Build()
+	assert.Equal(t, "", ctx.GetKey())
+	assert.False(t, ctx.IsAnonymous())
+	assert.Equal(t, map[string]interface{}{"kind": ffcontext.MultiKind}, ctx.GetCustom())
+}
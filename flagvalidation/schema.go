@@ -0,0 +1,314 @@
+package flagvalidation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/invopop/jsonschema"
+	"github.com/thomaspoignant/go-feature-flag/internal/dto"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigurationError is returned by ValidateConfiguration when the configuration is not valid.
+// It contains all the errors found in the configuration.
+type ConfigurationError struct {
+	Errors []ValidationError
+}
+
+// Error returns the list of all the errors found in the configuration.
+func (c *ConfigurationError) Error() string {
+	messages := make([]string, 0, len(c.Errors))
+	for _, validationError := range c.Errors {
+		messages = append(messages, validationError.Error())
+	}
+	return fmt.Sprintf("invalid flag configuration:\n%s", strings.Join(messages, "\n"))
+}
+
+var (
+	flagSchema     map[string]interface{}
+	flagSchemaErr  error
+	flagSchemaOnce sync.Once
+)
+
+// getFlagSchema returns the JSON schema of a flag configuration file.
+// The schema is generated from the flag DTO, it is the same as the one published in .schema/flag-schema.json.
+func getFlagSchema() (map[string]interface{}, error) {
+	flagSchemaOnce.Do(func() {
+		content, err := jsonschema.Reflect(map[string]dto.DTO{}).MarshalJSON()
+		if err != nil {
+			flagSchemaErr = err
+			return
+		}
+		flagSchemaErr = json.Unmarshal(content, &flagSchema)
+	})
+	return flagSchema, flagSchemaErr
+}
+
+// ValidateConfiguration is validating a flag configuration file against the JSON schema of the flags,
+// and is checking the consistency of each flag (percentages, variations, ...).
+// The format of the file can be yaml, json, json5 or toml (default: yaml).
+//
+// If the configuration is not valid, a *ConfigurationError listing every offending flag and field is returned.
+func ValidateConfiguration(config []byte, format string) error {
+	var document interface{}
+	if strings.ToLower(format) == "yaml" || format == "" {
+		// YAML is decoded node by node to keep the keys as written in the file (ex: True: 100).
+		var node yaml.Node
+		if err := yaml.Unmarshal(config, &node); err != nil {
+			return fmt.Errorf("could not parse file: %w", err)
+		}
+		utils.RemoveYAMLFragments(&node)
+		var err error
+		if document, err = yamlNodeToInterface(&node); err != nil {
+			return fmt.Errorf("could not parse file: %w", err)
+		}
+	} else if err := unmarshal(config, format, &document); err != nil {
+		return err
+	}
+
+	// we convert the document to JSON to validate the same types whatever the input format is.
+	normalized, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("could not parse file: %w", err)
+	}
+	var jsonDocument map[string]interface{}
+	if err := json.Unmarshal(normalized, &jsonDocument); err != nil {
+		return fmt.Errorf("could not parse file: %w", err)
+	}
+
+	schema, err := getFlagSchema()
+	if err != nil {
+		return fmt.Errorf("impossible to generate the flag schema: %w", err)
+	}
+
+	flagNames := make([]string, 0, len(jsonDocument))
+	for flagName := range jsonDocument {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+
+	flagDefinition, _ := schema["additionalProperties"].(map[string]interface{})
+	validationErrors := make([]ValidationError, 0)
+	for _, flagName := range flagNames {
+		v := &schemaValidator{root: schema, flagName: flagName}
+		v.validate("", flagDefinition, jsonDocument[flagName])
+		validationErrors = append(validationErrors, v.errors...)
+	}
+
+	// The consistency checks need a document matching the schema.
+	if len(validationErrors) == 0 {
+		validationErrors, err = Validate(normalized, "json")
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return &ConfigurationError{Errors: validationErrors}
+	}
+	return nil
+}
+
+// schemaValidator is validating a flag against the subset of JSON schema used by the flag schema.
+type schemaValidator struct {
+	root     map[string]interface{}
+	flagName string
+	errors   []ValidationError
+}
+
+func (s *schemaValidator) add(field string, message string) {
+	if field == "" {
+		field = "."
+	}
+	s.errors = append(s.errors, ValidationError{Flag: s.flagName, Field: field, Message: message})
+}
+
+// validate checks the value against the schema and collects the errors with their location.
+func (s *schemaValidator) validate(field string, schema map[string]interface{}, value interface{}) {
+	if schema == nil {
+		return
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		s.validate(field, s.resolveRef(ref), value)
+		return
+	}
+
+	if expectedType, ok := schema["type"].(string); ok && !matchType(expectedType, value) {
+		s.add(field, fmt.Sprintf("invalid type, expected %s got %s", expectedType, jsonType(value)))
+		return
+	}
+	if format, ok := schema["format"].(string); ok && format == "date-time" {
+		if str, ok := value.(string); ok {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				s.add(field, fmt.Sprintf("invalid date-time: %s", str))
+			}
+		}
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(field, schema, typedValue)
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for index, item := range typedValue {
+			s.validate(fmt.Sprintf("%s[%d]", field, index), items, item)
+		}
+	}
+}
+
+// validateObject checks the properties of an object.
+func (s *schemaValidator) validateObject(field string, schema map[string]interface{}, value map[string]interface{}) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, present := value[fmt.Sprint(name)]; !present {
+				s.add(joinField(field, fmt.Sprint(name)), "missing required field")
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, key := range keys {
+		if propertySchema, ok := properties[key].(map[string]interface{}); ok {
+			s.validate(joinField(field, key), propertySchema, value[key])
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				s.add(joinField(field, key), "unknown field")
+			}
+		case map[string]interface{}:
+			s.validate(joinField(field, key), additional, value[key])
+		}
+	}
+}
+
+// resolveRef returns the definition referenced by a $ref (ex: #/$defs/Rule).
+func (s *schemaValidator) resolveRef(ref string) map[string]interface{} {
+	defs, _ := s.root["$defs"].(map[string]interface{})
+	definition, _ := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	return definition
+}
+
+// yamlNodeToInterface converts a YAML node to the types used by encoding/json, the keys of the mappings
+// are kept as they are written in the file.
+func yamlNodeToInterface(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return map[string]interface{}{}, nil
+		}
+		return yamlNodeToInterface(node.Content[0])
+	case yaml.MappingNode:
+		result := make(map[string]interface{}, len(node.Content)/2)
+		merged := map[string]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlNodeToInterface(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if node.Content[i].Tag == "!!merge" {
+				// merge key (<<: *anchor), the keys written in the mapping have the priority.
+				if err := mergeYAMLValue(merged, value); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			result[node.Content[i].Value] = value
+		}
+		for key, value := range merged {
+			if _, ok := result[key]; !ok {
+				result[key] = value
+			}
+		}
+		return result, nil
+	case yaml.SequenceNode:
+		result := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlNodeToInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	case yaml.AliasNode:
+		return yamlNodeToInterface(node.Alias)
+	default:
+		var value interface{}
+		err := node.Decode(&value)
+		return value, err
+	}
+}
+
+// mergeYAMLValue adds the keys of the value of a merge key into merged, the value can be a mapping
+// or a list of mappings, the first mappings of the list have the priority.
+func mergeYAMLValue(merged map[string]interface{}, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if _, ok := merged[key]; !ok {
+				merged[key] = item
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := mergeYAMLValue(merged, item); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("the value of a merge key should be a mapping or a list of mappings")
+	}
+	return nil
+}
+
+func joinField(field string, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
+
+// matchType checks if a value decoded from JSON has the expected JSON schema type.
+func matchType(expectedType string, value interface{}) bool {
+	switch expectedType {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	default:
+		return jsonType(value) == expectedType
+	}
+}
+
+// jsonType returns the JSON schema type of a value decoded from JSON.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
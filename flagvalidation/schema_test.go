@@ -0,0 +1,127 @@
+package flagvalidation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/flagvalidation"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		format   string
+		wantErr  assert.ErrorAssertionFunc
+		errorMsg string
+	}{
+		{
+			name:    "valid yaml file",
+			file:    "../testdata/flag-config.yaml",
+			format:  "yaml",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "yaml file with anchors and fragments",
+			file:    "../testdata/flag-config-yaml-anchors.yaml",
+			format:  "yaml",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "valid json file",
+			file:    "../testdata/flag-config.json",
+			format:  "json",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "valid toml file",
+			file:    "../testdata/flag-config.toml",
+			format:  "toml",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "no format should use yaml",
+			file:    "../testdata/flag-config.yaml",
+			format:  "",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "unknown fields",
+			file:    "testdata/unknown-field.yaml",
+			format:  "yaml",
+			wantErr: assert.Error,
+			errorMsg: "invalid flag configuration:\n" +
+				"invalid flag unknown-field-flag: owner: unknown field\n" +
+				"invalid flag unknown-field-flag: targeting[0].weight: unknown field",
+		},
+		{
+			name:    "wrong field types",
+			file:    "testdata/wrong-types.json",
+			format:  "json",
+			wantErr: assert.Error,
+			errorMsg: "invalid flag configuration:\n" +
+				"invalid flag wrong-types-flag: disable: invalid type, expected boolean got string\n" +
+				"invalid flag wrong-types-flag: targeting: invalid type, expected array got object\n" +
+				"invalid flag wrong-types-flag: trackEvents: invalid type, expected boolean got number",
+		},
+		{
+			name:    "wrong percentage type",
+			file:    "testdata/invalid-format.yaml",
+			format:  "yaml",
+			wantErr: assert.Error,
+			errorMsg: "invalid flag configuration:\n" +
+				"invalid flag invalid-flag: targeting[0].percentage: invalid type, expected object got string",
+		},
+		{
+			name:    "wrong variation value types",
+			file:    "testdata/type-mismatch.yaml",
+			format:  "yaml",
+			wantErr: assert.Error,
+			errorMsg: "invalid flag configuration:\n" +
+				"invalid flag type-mismatch-flag: variations.B: all variations should have the same type, " +
+				"expected (bool) got (string)",
+		},
+		{
+			name:    "percentages not summing to 100",
+			file:    "testdata/invalid-percentages.yaml",
+			format:  "yaml",
+			wantErr: assert.Error,
+			errorMsg: "invalid flag configuration:\n" +
+				"invalid flag percentage-flag: defaultRule.percentage.C: variation C does not exist\n" +
+				"invalid flag percentage-flag: targeting[0].percentage: percentages should sum to 100, got 90",
+		},
+		{
+			name:     "invalid input format",
+			file:     "../testdata/flag-config.yaml",
+			format:   "swift",
+			wantErr:  assert.Error,
+			errorMsg: "invalid input format: swift",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.file)
+			assert.NoError(t, err)
+
+			err = flagvalidation.ValidateConfiguration(content, tt.format)
+			tt.wantErr(t, err)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestValidateConfiguration_errorDetails(t *testing.T) {
+	content, err := os.ReadFile("testdata/unknown-field.yaml")
+	assert.NoError(t, err)
+
+	err = flagvalidation.ValidateConfiguration(content, "yaml")
+	var configurationError *flagvalidation.ConfigurationError
+	assert.ErrorAs(t, err, &configurationError)
+	assert.Equal(t, []flagvalidation.ValidationError{
+		{Flag: "unknown-field-flag", Field: "owner", Message: "unknown field"},
+		{Flag: "unknown-field-flag", Field: "targeting[0].weight", Message: "unknown field"},
+	}, configurationError.Errors)
+}
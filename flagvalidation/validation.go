@@ -0,0 +1,372 @@
+package flagvalidation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/thomaspoignant/go-feature-flag/internal/dto"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+// ValidationError is an error found in the configuration of a flag.
+type ValidationError struct {
+	// Flag is the name of the flag containing the error.
+	Flag string `json:"flag"`
+
+	// Field is the location of the error inside the flag (ex: targeting[0].percentage).
+	Field string `json:"field"`
+
+	// Message is a description of the error.
+	Message string `json:"message"`
+}
+
+// Error returns a human-readable description of the validation error.
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("invalid flag %s: %s: %s", v.Flag, v.Field, v.Message)
+}
+
+// Validate is checking all the flags of a configuration file.
+// The format of the file can be yaml, json, json5 or toml (default: yaml).
+//
+// It returns the list of the errors found in the flags, an empty list means that the configuration is valid.
+// An error is returned if the configuration cannot be parsed.
+func Validate(config []byte, format string) ([]ValidationError, error) {
+	var flags map[string]dto.DTO
+	if err := unmarshal(config, format, &flags); err != nil {
+		return nil, err
+	}
+
+	// we sort the flags to have a deterministic list of errors
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	validationErrors := make([]ValidationError, 0)
+	internalFlags := make(map[string]flag.InternalFlag, len(flags))
+	for _, key := range keys {
+		flagDto := flags[key]
+		internalFlags[key] = flagDto.Convert()
+		validationErrors = append(validationErrors, validateFlag(key, internalFlags[key])...)
+	}
+
+	cycles := flag.FindPrerequisiteCycles(internalFlags)
+	for _, key := range keys {
+		if err, ok := cycles[key]; ok {
+			validationErrors = append(validationErrors, ValidationError{Flag: key, Field: "prerequisites", Message: err.Error()})
+		}
+	}
+
+	overlaps := flag.FindLayerOverlaps(internalFlags)
+	for _, key := range keys {
+		if err, ok := overlaps[key]; ok {
+			validationErrors = append(validationErrors, ValidationError{Flag: key, Field: "layer", Message: err.Error()})
+		}
+	}
+	return validationErrors, nil
+}
+
+// ValidateVariationReferences is checking that the rules of the flags only use variations declared in the flags
+// (variation, percentages and progressive rollout of the default rule, the targeting and the environments,
+// and the control variation of the holdout).
+// The format of the file can be yaml, json, json5 or toml (default: yaml).
+//
+// It returns one error per unknown variation, an empty list means that all the references are valid.
+// An error is returned if the configuration cannot be parsed.
+func ValidateVariationReferences(config []byte, format string) ([]ValidationError, error) {
+	var flags map[string]dto.DTO
+	if err := unmarshal(config, format, &flags); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	validationErrors := make([]ValidationError, 0)
+	for _, key := range keys {
+		flagDto := flags[key]
+		f := flagDto.Convert()
+		v := &validator{flagName: key, variations: f.GetVariations()}
+		if f.Holdout != nil && f.Holdout.Variation != nil {
+			v.validateVariationName("holdout.variation", f.Holdout.GetVariation())
+		}
+		if rule := f.GetDefaultRule(); rule != nil {
+			v.validateRuleReferences("defaultRule", rule)
+		}
+
+		environments := make([]string, 0, len(f.GetEnvironments()))
+		for name := range f.GetEnvironments() {
+			environments = append(environments, name)
+		}
+		sort.Strings(environments)
+		for _, name := range environments {
+			if rule := f.GetEnvironments()[name].DefaultRule; rule != nil {
+				v.validateRuleReferences("environments."+name+".defaultRule", rule)
+			}
+		}
+
+		for index, rule := range f.GetRules() {
+			if rule.IsDisable() {
+				continue
+			}
+			v.validateRuleReferences(fmt.Sprintf("targeting[%d]", index), &rule)
+		}
+		validationErrors = append(validationErrors, v.errors...)
+	}
+	return validationErrors, nil
+}
+
+// unmarshal is parsing the configuration using the format provided (yaml, json, json5 or toml).
+// If no format is provided, the configuration is parsed as YAML.
+func unmarshal(config []byte, format string, out interface{}) error {
+	var err error
+	switch strings.ToLower(format) {
+	case "toml":
+		err = toml.Unmarshal(config, out)
+	case "json":
+		err = json.Unmarshal(config, out)
+	case "json5", "jsonc":
+		err = utils.UnmarshalFlagsJSON5(config, out)
+	case "yaml", "":
+		err = utils.UnmarshalFlagsYAML(config, out)
+	default:
+		return fmt.Errorf("invalid input format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("could not parse file: %w", err)
+	}
+	return nil
+}
+
+// validateFlag returns all the errors of a flag.
+func validateFlag(flagName string, f flag.InternalFlag) []ValidationError {
+	v := &validator{flagName: flagName, variations: f.GetVariations()}
+
+	if len(v.variations) == 0 {
+		v.add("variations", "no variation available")
+	}
+	v.validateVariationTypes()
+
+	if f.SeedRotation != nil {
+		if rotation, err := time.ParseDuration(*f.SeedRotation); err != nil || rotation <= 0 {
+			v.add("seedRotation", fmt.Sprintf("invalid duration: %s", *f.SeedRotation))
+		}
+	}
+
+	if f.VariationMetadata != nil {
+		names := make([]string, 0, len(*f.VariationMetadata))
+		for name := range *f.VariationMetadata {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v.validateVariationName("variationMetadata."+name, name)
+		}
+	}
+
+	for index, prerequisite := range f.GetPrerequisites() {
+		field := fmt.Sprintf("prerequisites[%d]", index)
+		if prerequisite.Flag == "" {
+			v.add(field+".flag", "missing prerequisite flag")
+		}
+		if prerequisite.Variation == "" {
+			v.add(field+".variation", "missing prerequisite variation")
+		}
+	}
+
+	if f.Layer != nil {
+		if err := f.Layer.IsValid(); err != nil {
+			v.add("layer", err.Error())
+		}
+	}
+
+	if f.Holdout != nil {
+		if err := f.Holdout.IsValid(); err != nil {
+			v.add("holdout.percentage", err.Error())
+		}
+		if f.Holdout.Variation != nil {
+			v.validateVariationName("holdout.variation", f.Holdout.GetVariation())
+		}
+	}
+
+	if f.GetDefaultRule() == nil {
+		v.add("defaultRule", "missing default rule")
+	} else {
+		v.validateRule("defaultRule", f.GetDefaultRule(), true)
+	}
+
+	environments := make([]string, 0, len(f.GetEnvironments()))
+	for name := range f.GetEnvironments() {
+		environments = append(environments, name)
+	}
+	sort.Strings(environments)
+	for _, name := range environments {
+		if rule := f.GetEnvironments()[name].DefaultRule; rule != nil {
+			v.validateRule("environments."+name+".defaultRule", rule, true)
+		}
+	}
+
+	ruleNames := map[string]interface{}{}
+	for index, rule := range f.GetRules() {
+		field := fmt.Sprintf("targeting[%d]", index)
+		v.validateRule(field, &rule, false)
+
+		if rule.GetName() == "" {
+			continue
+		}
+		if _, ok := ruleNames[rule.GetName()]; ok {
+			v.add(field+".name", fmt.Sprintf("duplicated rule name: %s", rule.GetName()))
+		}
+		ruleNames[rule.GetName()] = nil
+	}
+	return v.errors
+}
+
+// validator collects the errors of a flag.
+type validator struct {
+	flagName   string
+	variations map[string]*interface{}
+	errors     []ValidationError
+}
+
+func (v *validator) add(field string, message string) {
+	v.errors = append(v.errors, ValidationError{Flag: v.flagName, Field: field, Message: message})
+}
+
+// validateVariationTypes checks that all variations have the same type.
+func (v *validator) validateVariationTypes() {
+	names := make([]string, 0, len(v.variations))
+	for name := range v.variations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	expectedType := ""
+	for _, name := range names {
+		value := v.variations[name]
+		if value == nil {
+			v.add("variations."+name, "variation value is null")
+			continue
+		}
+		currentType, err := utils.JSONTypeExtractor(*value)
+		if err != nil {
+			v.add("variations."+name, err.Error())
+			continue
+		}
+		if expectedType == "" {
+			expectedType = currentType
+			continue
+		}
+		if currentType != expectedType {
+			v.add("variations."+name,
+				fmt.Sprintf("all variations should have the same type, expected %s got %s", expectedType, currentType))
+		}
+	}
+}
+
+// validateRule checks a targeting rule or the default rule.
+func (v *validator) validateRule(field string, rule *flag.Rule, defaultRule bool) {
+	if !defaultRule && rule.IsDisable() {
+		return
+	}
+
+	if rule.Percentages == nil && rule.ProgressiveRollout == nil && rule.VariationResult == nil {
+		v.add(field, "impossible to return value, no variation, percentage or progressive rollout")
+	}
+
+	if !defaultRule {
+		if rule.Query == nil {
+			v.add(field+".query", "each targeting should have a query")
+		} else if err := flag.ValidateRegexOperators(rule.GetTrimmedQuery()); err != nil {
+			v.add(field+".query", err.Error())
+		} else if err := flag.ValidateDateOperators(rule.GetTrimmedQuery()); err != nil {
+			v.add(field+".query", err.Error())
+		} else if err := flag.ValidateJSONLogicQuery(rule.GetTrimmedQuery()); err != nil {
+			v.add(field+".query", err.Error())
+		}
+	}
+
+	if rule.VariationResult != nil {
+		v.validateVariationName(field+".variation", rule.GetVariationResult())
+	}
+
+	if rule.Percentages != nil {
+		names := make([]string, 0, len(rule.GetPercentages()))
+		for name := range rule.GetPercentages() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v.validateVariationName(field+".percentage."+name, name)
+		}
+		if total := flag.SumPercentages(rule.GetPercentages()); total != 100 {
+			v.add(field+".percentage", fmt.Sprintf("percentages should sum to 100, got %v", total))
+		}
+	}
+
+	if rollout := rule.ProgressiveRollout; rollout != nil {
+		v.validateProgressiveStep(field+".progressiveRollout.initial", rollout.Initial)
+		v.validateProgressiveStep(field+".progressiveRollout.end", rollout.End)
+		if rollout.Initial != nil && rollout.End != nil &&
+			rollout.Initial.Percentage != nil && rollout.End.Percentage != nil &&
+			*rollout.End.Percentage < *rollout.Initial.Percentage {
+			v.add(field+".progressiveRollout",
+				fmt.Sprintf("initial percentage should be lower than end percentage: %v/%v",
+					*rollout.Initial.Percentage, *rollout.End.Percentage))
+		}
+	}
+}
+
+// validateRuleReferences checks that the variations used by a rule exist in the flag.
+func (v *validator) validateRuleReferences(field string, rule *flag.Rule) {
+	if rule.VariationResult != nil {
+		v.validateVariationName(field+".variation", rule.GetVariationResult())
+	}
+
+	names := make([]string, 0, len(rule.GetPercentages()))
+	for name := range rule.GetPercentages() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.validateVariationName(field+".percentage."+name, name)
+	}
+
+	if rollout := rule.ProgressiveRollout; rollout != nil {
+		if rollout.Initial != nil && rollout.Initial.Variation != nil {
+			v.validateVariationName(field+".progressiveRollout.initial.variation", *rollout.Initial.Variation)
+		}
+		if rollout.End != nil && rollout.End.Variation != nil {
+			v.validateVariationName(field+".progressiveRollout.end.variation", *rollout.End.Variation)
+		}
+	}
+}
+
+// validateProgressiveStep checks a step of a progressive rollout.
+func (v *validator) validateProgressiveStep(field string, step *flag.ProgressiveRolloutStep) {
+	if step == nil {
+		v.add(field, "missing progressive rollout step")
+		return
+	}
+	if step.Variation == nil {
+		v.add(field+".variation", "missing variation")
+	} else {
+		v.validateVariationName(field+".variation", *step.Variation)
+	}
+}
+
+// validateVariationName checks that a variation used in a rule exists in the flag.
+func (v *validator) validateVariationName(field string, name string) {
+	if _, ok := v.variations[name]; !ok {
+		v.add(field, fmt.Sprintf("variation %s does not exist", name))
+	}
+}
@@ -0,0 +1,293 @@
+package flagvalidation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/flagvalidation"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		format  string
+		want    []flagvalidation.ValidationError
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "valid yaml file",
+			file:    "../testdata/flag-config.yaml",
+			format:  "yaml",
+			want:    []flagvalidation.ValidationError{},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "yaml file with anchors and fragments",
+			file:    "../testdata/flag-config-yaml-anchors.yaml",
+			format:  "yaml",
+			want:    []flagvalidation.ValidationError{},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "valid json file",
+			file:    "../testdata/flag-config.json",
+			format:  "JSON",
+			want:    []flagvalidation.ValidationError{},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "valid toml file",
+			file:    "../testdata/flag-config.toml",
+			format:  "toml",
+			want:    []flagvalidation.ValidationError{},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "invalid percentages",
+			file:   "testdata/invalid-percentages.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "percentage-flag",
+					Field:   "defaultRule.percentage.C",
+					Message: "variation C does not exist",
+				},
+				{
+					Flag:    "percentage-flag",
+					Field:   "targeting[0].percentage",
+					Message: "percentages should sum to 100, got 90",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "missing variations",
+			file:   "testdata/missing-variations.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "no-default-rule-flag",
+					Field:   "defaultRule",
+					Message: "missing default rule",
+				},
+				{
+					Flag:    "no-variation-flag",
+					Field:   "variations",
+					Message: "no variation available",
+				},
+				{
+					Flag:    "no-variation-flag",
+					Field:   "defaultRule.variation",
+					Message: "variation A does not exist",
+				},
+				{
+					Flag:    "unknown-variation-flag",
+					Field:   "targeting[0].variation",
+					Message: "variation C does not exist",
+				},
+				{
+					Flag:    "unknown-variation-flag",
+					Field:   "targeting[1].progressiveRollout.end.variation",
+					Message: "variation D does not exist",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "variations with different types",
+			file:   "testdata/type-mismatch.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "type-mismatch-flag",
+					Field:   "variations.B",
+					Message: "all variations should have the same type, expected (bool) got (string)",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "invalid rules",
+			file:   "testdata/invalid-rules.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "invalid-rules-flag",
+					Field:   "seedRotation",
+					Message: "invalid duration: every week",
+				},
+				{
+					Flag:    "invalid-rules-flag",
+					Field:   "targeting[0].query",
+					Message: "invalid regex \"[a-\" for attribute email: error parsing regexp: missing closing ]: `[a-`",
+				},
+				{
+					Flag:    "invalid-rules-flag",
+					Field:   "targeting[1].query",
+					Message: "each targeting should have a query",
+				},
+				{
+					Flag:    "invalid-rules-flag",
+					Field:   "targeting[1].name",
+					Message: "duplicated rule name: rule1",
+				},
+				{
+					Flag:    "invalid-rules-flag",
+					Field:   "targeting[2]",
+					Message: "impossible to return value, no variation, percentage or progressive rollout",
+				},
+				{
+					Flag:  "invalid-rules-flag",
+					Field: "targeting[3].query",
+					Message: "invalid date \"2024-13-01\" for attribute signupDate, dates should use the RFC3339 format " +
+						"(ex: \"2024-01-01T00:00:00Z\")",
+				},
+				{
+					Flag:    "invalid-rules-flag",
+					Field:   "targeting[4].query",
+					Message: "invalid JsonLogic query: unsupported operator \"regex\"",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "prerequisites",
+			file:   "testdata/prerequisite-cycle.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "flag-b",
+					Field:   "prerequisites[1].flag",
+					Message: "missing prerequisite flag",
+				},
+				{
+					Flag:    "flag-a",
+					Field:   "prerequisites",
+					Message: "prerequisite cycle detected: flag-a -> flag-b -> flag-a",
+				},
+				{
+					Flag:    "flag-b",
+					Field:   "prerequisites",
+					Message: "prerequisite cycle detected: flag-a -> flag-b -> flag-a",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "variation metadata",
+			file:   "testdata/invalid-variation-metadata.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "metadata-flag",
+					Field:   "variationMetadata.C",
+					Message: "variation C does not exist",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "file that cannot be parsed",
+			file:    "testdata/invalid-format.yaml",
+			format:  "yaml",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "invalid input format",
+			file:    "../testdata/flag-config.yaml",
+			format:  "swift",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.file)
+			assert.NoError(t, err)
+
+			got, err := flagvalidation.Validate(content, tt.format)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateVariationReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		format  string
+		want    []flagvalidation.ValidationError
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "valid yaml file",
+			file:    "../testdata/flag-config.yaml",
+			format:  "yaml",
+			want:    []flagvalidation.ValidationError{},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "unknown variation in percentages",
+			file:   "testdata/invalid-percentages.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "percentage-flag",
+					Field:   "defaultRule.percentage.C",
+					Message: "variation C does not exist",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "unknown variation in rules and progressive rollout",
+			file:   "testdata/missing-variations.yaml",
+			format: "yaml",
+			want: []flagvalidation.ValidationError{
+				{
+					Flag:    "no-variation-flag",
+					Field:   "defaultRule.variation",
+					Message: "variation A does not exist",
+				},
+				{
+					Flag:    "unknown-variation-flag",
+					Field:   "targeting[0].variation",
+					Message: "variation C does not exist",
+				},
+				{
+					Flag:    "unknown-variation-flag",
+					Field:   "targeting[1].progressiveRollout.end.variation",
+					Message: "variation D does not exist",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid input format",
+			file:    "../testdata/flag-config.yaml",
+			format:  "swift",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.file)
+			assert.NoError(t, err)
+
+			got, err := flagvalidation.ValidateVariationReferences(content, tt.format)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := flagvalidation.ValidationError{
+		Flag:    "my-flag",
+		Field:   "targeting[0].percentage",
+		Message: "percentages should sum to 100, got 90",
+	}
+	assert.Equal(t, "invalid flag my-flag: targeting[0].percentage: percentages should sum to 100, got 90", err.Error())
+}
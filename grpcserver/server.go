@@ -0,0 +1,212 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+	"github.com/thomaspoignant/go-feature-flag/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// ServiceName is the name of the gRPC service, see evaluation.proto.
+	ServiceName = "gofeatureflag.evaluation.v1.EvaluationService"
+
+	// EvaluateFullMethodName is the full name of the Evaluate RPC.
+	EvaluateFullMethodName = "/" + ServiceName + "/Evaluate"
+
+	// EvaluateStreamFullMethodName is the full name of the EvaluateStream RPC.
+	EvaluateStreamFullMethodName = "/" + ServiceName + "/EvaluateStream"
+
+	// targetingKey is the field of the context used as the key of the evaluation context.
+	targetingKey = "targetingKey"
+)
+
+// Server is a gRPC server evaluating the flags with a GoFeatureFlag instance (see evaluation.proto).
+// The evaluations are done exactly like with the RawVariation function, so the events are sent to
+// the data exporter.
+type Server struct {
+	client *ffclient.GoFeatureFlag
+}
+
+// NewServer creates a new gRPC evaluation server using the GoFeatureFlag instance to evaluate the flags.
+// The GoFeatureFlag instance is not closed by the server, you are responsible for closing it.
+func NewServer(client *ffclient.GoFeatureFlag) *Server {
+	return &Server{client: client}
+}
+
+// Register registers the EvaluationService on your gRPC server.
+//
+//	grpcServer := grpc.NewServer()
+//	grpcserver.NewServer(goff).Register(grpcServer)
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&serviceDesc, s)
+}
+
+// Evaluate evaluates one flag for an evaluation context.
+// It returns an InvalidArgument error if the request has no flagKey or if the context is invalid.
+func (s *Server) Evaluate(_ context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	return s.evaluate(req)
+}
+
+// EvaluateStream evaluates each request received on the stream and sends back one response per request.
+// An invalid request does not close the stream, a response with the error code GENERAL is sent instead.
+func (s *Server) EvaluateStream(stream grpc.ServerStream) error {
+	for {
+		req := &structpb.Struct{}
+		if err := stream.RecvMsg(req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		res, err := s.evaluate(req)
+		if err != nil {
+			res = errorResponse(req, err)
+		}
+		if err := stream.SendMsg(res); err != nil {
+			return err
+		}
+	}
+}
+
+// evaluate evaluates the flag of the request, it returns an InvalidArgument error if the request is invalid.
+func (s *Server) evaluate(req *structpb.Struct) (*structpb.Struct, error) {
+	request := req.AsMap()
+	flagKey, ok := request["flagKey"].(string)
+	if !ok || flagKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "the request has no flagKey")
+	}
+	evaluationCtx, err := toEvaluationContext(request["context"])
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, _ := s.client.RawVariation(flagKey, evaluationCtx, request["defaultValue"])
+	response, err := toResponse(flagKey, res)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "impossible to convert the result of the flag %s: %v", flagKey, err)
+	}
+	return response, nil
+}
+
+// toEvaluationContext converts the context of the request into an evaluation context,
+// the field targetingKey is used as the key and the other fields are custom attributes.
+func toEvaluationContext(requestCtx interface{}) (ffcontext.Context, error) {
+	if requestCtx == nil {
+		return ffcontext.NewEvaluationContext(""), nil
+	}
+	ctx, ok := requestCtx.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the context of the request must be an object")
+	}
+
+	custom := make(map[string]interface{}, len(ctx))
+	key := ""
+	for name, value := range ctx {
+		if name == targetingKey {
+			if key, ok = value.(string); !ok {
+				return nil, fmt.Errorf("the targetingKey of the context must be a string")
+			}
+			continue
+		}
+		custom[name] = value
+	}
+	return utils.ConvertEvaluationCtxFromRequest(key, custom), nil
+}
+
+// toResponse converts the result of an evaluation into the response of the service.
+func toResponse(flagKey string, res model.RawVarResult) (*structpb.Struct, error) {
+	return structpb.NewStruct(map[string]interface{}{
+		"flagKey":       flagKey,
+		"value":         res.Value,
+		"variationType": res.VariationType,
+		"reason":        res.Reason,
+		"errorCode":     res.ErrorCode,
+		"failed":        res.Failed,
+		"version":       res.Version,
+		"metadata":      toStructMap(res.Metadata),
+	})
+}
+
+// errorResponse is the response sent on the stream for an invalid request.
+func errorResponse(req *structpb.Struct, err error) *structpb.Struct {
+	return &structpb.Struct{Fields: map[string]*structpb.Value{
+		"flagKey":       req.GetFields()["flagKey"],
+		"variationType": structpb.NewStringValue(flag.VariationSDKDefault),
+		"reason":        structpb.NewStringValue(flag.ReasonError),
+		"errorCode":     structpb.NewStringValue(flag.ErrorCodeGeneral),
+		"errorDetails":  structpb.NewStringValue(status.Convert(err).Message()),
+		"failed":        structpb.NewBoolValue(true),
+	}}
+}
+
+// toStructMap returns an empty map if the metadata are nil, so they can be converted in a struct.
+func toStructMap(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return map[string]interface{}{}
+	}
+	return metadata
+}
+
+// evaluationServer is the interface implemented by Server to be registered as the EvaluationService.
+type evaluationServer interface {
+	Evaluate(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+	EvaluateStream(stream grpc.ServerStream) error
+}
+
+func evaluateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(evaluationServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EvaluateFullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(evaluationServer).Evaluate(ctx, req.(*structpb.Struct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func evaluateStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(evaluationServer).EvaluateStream(stream)
+}
+
+// serviceDesc is the description of the EvaluationService, the messages are google.protobuf.Struct
+// so no generated code is needed.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*evaluationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Evaluate",
+			Handler:    evaluateHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EvaluateStream",
+			Handler:       evaluateStreamHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "grpcserver/evaluation.proto",
+}
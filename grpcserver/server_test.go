@@ -0,0 +1,121 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/grpcserver"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inmemoryretriever"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// startServer starts an in-process gRPC evaluation server and returns a connection to it,
+// the caller is responsible for closing the GoFeatureFlag instance.
+func startServer(t *testing.T, exp *mock.Exporter) (*grpc.ClientConn, *ffclient.GoFeatureFlag) {
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"bool-flag": map[string]interface{}{
+					"variations": map[string]interface{}{"enabled": true, "disabled": false},
+					"targeting": []interface{}{
+						map[string]interface{}{"query": `beta eq true`, "variation": "enabled"},
+					},
+					"defaultRule": map[string]interface{}{"variation": "disabled"},
+				},
+			},
+		},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	grpcserver.NewServer(goff).Register(grpcServer)
+	go func() { _ = grpcServer.Serve(listener) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+		grpcServer.Stop()
+	})
+	return conn, goff
+}
+
+func newRequest(t *testing.T, flagKey string, evaluationCtx map[string]interface{}) *structpb.Struct {
+	req, err := structpb.NewStruct(map[string]interface{}{
+		"flagKey":      flagKey,
+		"context":      evaluationCtx,
+		"defaultValue": false,
+	})
+	require.NoError(t, err)
+	return req
+}
+
+func TestServer_Evaluate(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	conn, goff := startServer(t, exp)
+
+	res := &structpb.Struct{}
+	err := conn.Invoke(context.Background(), grpcserver.EvaluateFullMethodName,
+		newRequest(t, "bool-flag", map[string]interface{}{"targetingKey": "user-key", "beta": true}), res)
+	require.NoError(t, err)
+
+	got := res.AsMap()
+	assert.Equal(t, "bool-flag", got["flagKey"])
+	assert.Equal(t, true, got["value"])
+	assert.Equal(t, "enabled", got["variationType"])
+	assert.Equal(t, "TARGETING_MATCH", got["reason"])
+	assert.Equal(t, false, got["failed"])
+
+	// an invalid request returns an InvalidArgument error
+	err = conn.Invoke(context.Background(), grpcserver.EvaluateFullMethodName, &structpb.Struct{}, res)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the evaluation is collected like any other evaluation
+	goff.Close()
+	assert.Len(t, exp.GetExportedEvents(), 1)
+}
+
+func TestServer_EvaluateStream(t *testing.T) {
+	conn, goff := startServer(t, &mock.Exporter{Bulk: true})
+	defer goff.Close()
+
+	stream, err := conn.NewStream(context.Background(),
+		&grpc.StreamDesc{ServerStreams: true, ClientStreams: true}, grpcserver.EvaluateStreamFullMethodName)
+	require.NoError(t, err)
+
+	requests := []*structpb.Struct{
+		newRequest(t, "bool-flag", map[string]interface{}{"targetingKey": "user-1", "beta": true}),
+		newRequest(t, "bool-flag", map[string]interface{}{"targetingKey": "user-2", "beta": false}),
+		{},
+	}
+	wantVariations := []string{"enabled", "disabled", "SdkDefault"}
+	for i, req := range requests {
+		require.NoError(t, stream.SendMsg(req))
+		res := &structpb.Struct{}
+		require.NoError(t, stream.RecvMsg(res))
+		assert.Equal(t, wantVariations[i], res.AsMap()["variationType"])
+	}
+	require.NoError(t, stream.CloseSend())
+}
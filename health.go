@@ -0,0 +1,124 @@
+package ffclient
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthStatus is the state of the flag configuration loaded by go-feature-flag,
+// it can be used to implement a readiness or a liveness probe.
+type HealthStatus struct {
+	// Initialized is true if a flag configuration has been loaded at least once
+	// (from the retrievers or from Config.PersistentFlagConfigurationFile).
+	Initialized bool
+
+	// LastSuccessfulRefresh is the date of the last successful retrieval of the flags,
+	// it is zero if the flags have never been retrieved.
+	LastSuccessfulRefresh time.Time
+
+	// LastRefreshError is the error of the last retrieval of the flags, nil if it succeeded.
+	LastRefreshError error
+}
+
+// RefreshStats are the statistics of the refreshes of the flags (retrieval and parsing of the configuration),
+// they can be used to detect a slow or failing retriever.
+type RefreshStats struct {
+	// RefreshCount is the number of refreshes of the flags, successful or not.
+	RefreshCount int64
+
+	// FailureCount is the number of refreshes that have failed.
+	FailureCount int64
+
+	// ConsecutiveFailures is the number of refreshes that have failed since the last successful refresh.
+	ConsecutiveFailures int64
+
+	// LastRefreshDuration is the duration of the last refresh.
+	LastRefreshDuration time.Duration
+
+	// AverageRefreshDuration is the average duration of the refreshes.
+	AverageRefreshDuration time.Duration
+}
+
+// healthTracker records the results of the retrievals of the flags, it is safe for concurrent use.
+type healthTracker struct {
+	mutex  sync.RWMutex
+	status HealthStatus
+	stats  RefreshStats
+
+	// totalRefreshDuration is the sum of the durations of the refreshes, used to compute the average.
+	totalRefreshDuration time.Duration
+}
+
+// recordRefresh records the result and the duration of a retrieval of the flags.
+func (h *healthTracker) recordRefresh(err error, duration time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.status.LastRefreshError = err
+	if err == nil {
+		h.status.Initialized = true
+		h.status.LastSuccessfulRefresh = time.Now()
+		h.stats.ConsecutiveFailures = 0
+	} else {
+		h.stats.FailureCount++
+		h.stats.ConsecutiveFailures++
+	}
+	h.stats.RefreshCount++
+	h.stats.LastRefreshDuration = duration
+	h.totalRefreshDuration += duration
+	h.stats.AverageRefreshDuration = h.totalRefreshDuration / time.Duration(h.stats.RefreshCount)
+}
+
+// recordPersistedFlags records that the flags have been loaded from the persisted flags
+// because the retrieval failed with retrieveErr.
+func (h *healthTracker) recordPersistedFlags(retrieveErr error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.status.Initialized = true
+	h.status.LastRefreshError = retrieveErr
+}
+
+func (h *healthTracker) get() HealthStatus {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.status
+}
+
+func (h *healthTracker) getStats() RefreshStats {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.stats
+}
+
+// Health returns the state of the flag configuration: if it has been loaded, the date of the last
+// successful refresh and the error of the last refresh.
+// In offline mode, go-feature-flag is always considered as initialized.
+func (g *GoFeatureFlag) Health() HealthStatus {
+	if g == nil {
+		return HealthStatus{}
+	}
+	if g.config.Offline {
+		return HealthStatus{Initialized: true}
+	}
+	return g.health.get()
+}
+
+// Health returns the state of the flag configuration: if it has been loaded, the date of the last
+// successful refresh and the error of the last refresh.
+func Health() HealthStatus {
+	return ff.Health()
+}
+
+// Stats returns the statistics of the refreshes of the flags: number of refreshes and failures,
+// consecutive failures, last and average duration of a refresh.
+func (g *GoFeatureFlag) Stats() RefreshStats {
+	if g == nil {
+		return RefreshStats{}
+	}
+	return g.health.getStats()
+}
+
+// Stats returns the statistics of the refreshes of the flags: number of refreshes and failures,
+// consecutive failures, last and average duration of a refresh.
+func Stats() RefreshStats {
+	return ff.Stats()
+}
@@ -0,0 +1,114 @@
+package ffclient_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+)
+
+// switchRetriever returns an error while failing is true.
+type switchRetriever struct {
+	failing atomic.Bool
+}
+
+func (r *switchRetriever) Retrieve(_ context.Context) ([]byte, error) {
+	if r.failing.Load() {
+		return nil, errors.New("retriever unavailable")
+	}
+	return []byte(`test-flag:
+  variations:
+    A: true
+  defaultRule:
+    variation: A
+`), nil
+}
+
+func TestHealth(t *testing.T) {
+	r := &switchRetriever{}
+	r.failing.Store(true)
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval:         1 * time.Second,
+		Retriever:               r,
+		StartWithRetrieverError: true,
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+
+	// the flags have never been loaded
+	health := gffClient.Health()
+	assert.False(t, health.Initialized)
+	assert.True(t, health.LastSuccessfulRefresh.IsZero())
+	assert.ErrorContains(t, health.LastRefreshError, "retriever unavailable")
+
+	// the retriever is available, the flags are loaded at the next refresh
+	r.failing.Store(false)
+	assert.Eventually(t, func() bool { return gffClient.Health().Initialized }, 3*time.Second, 50*time.Millisecond)
+	health = gffClient.Health()
+	assert.NoError(t, health.LastRefreshError)
+	assert.WithinDuration(t, time.Now(), health.LastSuccessfulRefresh, 2*time.Second)
+	lastSuccess := health.LastSuccessfulRefresh
+
+	// a refresh error is reported, the flags loaded before are still served
+	r.failing.Store(true)
+	assert.Eventually(t, func() bool { return gffClient.Health().LastRefreshError != nil },
+		3*time.Second, 50*time.Millisecond)
+	health = gffClient.Health()
+	assert.True(t, health.Initialized)
+	assert.Equal(t, lastSuccess, health.LastSuccessfulRefresh)
+	assert.ErrorContains(t, health.LastRefreshError, "retriever unavailable")
+}
+
+func TestHealthOffline(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{Offline: true})
+	require.NoError(t, err)
+	defer gffClient.Close()
+	assert.True(t, gffClient.Health().Initialized)
+}
+
+func TestStats(t *testing.T) {
+	r := &switchRetriever{}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       r,
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+
+	// the flags have been retrieved once at startup
+	stats := gffClient.Stats()
+	assert.Equal(t, int64(1), stats.RefreshCount)
+	assert.Equal(t, int64(0), stats.FailureCount)
+	assert.Equal(t, int64(0), stats.ConsecutiveFailures)
+	assert.Positive(t, stats.LastRefreshDuration)
+	assert.Equal(t, stats.LastRefreshDuration, stats.AverageRefreshDuration)
+
+	// the failed refreshes are counted
+	r.failing.Store(true)
+	assert.Eventually(t, func() bool { return gffClient.Stats().ConsecutiveFailures == 2 },
+		5*time.Second, 50*time.Millisecond)
+	stats = gffClient.Stats()
+	assert.Equal(t, int64(3), stats.RefreshCount)
+	assert.Equal(t, int64(2), stats.FailureCount)
+
+	// a successful refresh resets the consecutive failures
+	r.failing.Store(false)
+	assert.Eventually(t, func() bool { return gffClient.Stats().ConsecutiveFailures == 0 },
+		3*time.Second, 50*time.Millisecond)
+	stats = gffClient.Stats()
+	assert.Equal(t, int64(4), stats.RefreshCount)
+	assert.Equal(t, int64(2), stats.FailureCount)
+	assert.Positive(t, stats.AverageRefreshDuration)
+}
+
+func TestStatsOffline(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{Offline: true})
+	require.NoError(t, err)
+	defer gffClient.Close()
+	assert.Equal(t, ffclient.RefreshStats{}, gffClient.Stats())
+}
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"log"
@@ -13,24 +15,35 @@ import (
 	"github.com/thomaspoignant/go-feature-flag/internal/dto"
 
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
-	"gopkg.in/yaml.v3"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
 )
 
+// ErrNotInitialised is returned when reading the flags before the first configuration has been loaded.
+var ErrNotInitialised = errors.New("impossible to read the flag before the initialisation")
+
 type Manager interface {
 	ConvertToFlagStruct(loadedFlags []byte, fileFormat string) (map[string]dto.DTO, error)
+	ConvertToDelta(loadedDelta []byte, fileFormat string) (dto.Delta, error)
 	UpdateCache(newFlags map[string]dto.DTO, log *log.Logger) error
+	ApplyDeltas(deltas []dto.Delta, log *log.Logger) (map[string]dto.DTO, error)
 	Close()
 	GetFlag(key string) (flag.Flag, error)
 	AllFlags() (map[string]flag.Flag, error)
 	GetLatestUpdateDate() time.Time
+	GetGeneration() (int64, string)
 }
 
 type cacheManagerImpl struct {
 	inMemoryCache       Cache
+	currentFlags        map[string]dto.DTO
 	mutex               sync.RWMutex
 	notificationService Service
 	latestUpdate        time.Time
 	logger              *log.Logger
+	// generation is incremented each time the content of the flags changes.
+	generation int64
+	// configurationHash is the hash of the content of the flags of the current generation.
+	configurationHash string
 }
 
 func New(notificationService Service, logger *log.Logger) Manager {
@@ -44,43 +57,81 @@ func New(notificationService Service, logger *log.Logger) Manager {
 
 func (c *cacheManagerImpl) ConvertToFlagStruct(loadedFlags []byte, fileFormat string) (map[string]dto.DTO, error) {
 	var newFlags map[string]dto.DTO
-	var err error
-	switch strings.ToLower(fileFormat) {
-	case "toml":
-		err = toml.Unmarshal(loadedFlags, &newFlags)
-	case "json":
-		err = json.Unmarshal(loadedFlags, &newFlags)
-	default:
-		// default unmarshaller is YAML
-		err = yaml.Unmarshal(loadedFlags, &newFlags)
-	}
+	err := unmarshal(loadedFlags, fileFormat, &newFlags)
 	return newFlags, err
 }
 
+// ConvertToDelta converts a delta document (flags to upsert and keys to delete) into a dto.Delta.
+func (c *cacheManagerImpl) ConvertToDelta(loadedDelta []byte, fileFormat string) (dto.Delta, error) {
+	var delta dto.Delta
+	err := unmarshal(loadedDelta, fileFormat, &delta)
+	return delta, err
+}
+
 func (c *cacheManagerImpl) UpdateCache(newFlags map[string]dto.DTO, log *log.Logger) error {
+	c.mutex.Lock()
+	oldCacheFlags, newCacheFlags := c.replaceFlags(newFlags)
+	c.mutex.Unlock()
+
+	// notify the changes
+	c.notificationService.Notify(oldCacheFlags, newCacheFlags, log)
+	return nil
+}
+
+// ApplyDeltas applies the deltas, in order, on top of the flags currently in the cache.
+// It returns the flags stored in the cache after the update.
+func (c *cacheManagerImpl) ApplyDeltas(deltas []dto.Delta, log *log.Logger) (map[string]dto.DTO, error) {
+	c.mutex.Lock()
+	newFlags := c.currentFlags
+	for _, delta := range deltas {
+		newFlags = delta.Apply(newFlags)
+	}
+	oldCacheFlags, newCacheFlags := c.replaceFlags(newFlags)
+	c.mutex.Unlock()
+
+	// notify the changes
+	c.notificationService.Notify(oldCacheFlags, newCacheFlags, log)
+	return newFlags, nil
+}
+
+// replaceFlags replaces the flags of the cache and returns the old and new flags to compare them,
+// the caller should hold the mutex.
+func (c *cacheManagerImpl) replaceFlags(newFlags map[string]dto.DTO) (map[string]flag.Flag, map[string]flag.Flag) {
 	newCache := NewInMemoryCache(c.logger)
 	newCache.Init(newFlags)
 	newCacheFlags := newCache.All()
 	oldCacheFlags := map[string]flag.Flag{}
 
-	c.mutex.Lock()
 	// collect flags for compare.
 	if c.inMemoryCache != nil {
 		oldCacheFlags = c.inMemoryCache.All()
 	}
 	c.inMemoryCache = newCache
+	c.currentFlags = newFlags
 	c.latestUpdate = time.Now()
-	c.mutex.Unlock()
+	if configurationHash := hashFlags(newFlags); configurationHash != c.configurationHash {
+		c.generation++
+		c.configurationHash = configurationHash
+	}
+	return oldCacheFlags, newCacheFlags
+}
 
-	// notify the changes
-	c.notificationService.Notify(oldCacheFlags, newCacheFlags, log)
-	return nil
+// hashFlags returns a hash of the content of the flags, the keys of the maps are sorted
+// when marshaled so the same flags always have the same hash.
+func hashFlags(flags map[string]dto.DTO) string {
+	content, err := json.Marshal(flags)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
 }
 
 func (c *cacheManagerImpl) Close() {
 	// Clear the cache
 	c.mutex.Lock()
 	c.inMemoryCache = nil
+	c.currentFlags = nil
 	c.mutex.Unlock()
 	if c.notificationService != nil {
 		c.notificationService.Close()
@@ -91,7 +142,7 @@ func (c *cacheManagerImpl) GetFlag(key string) (flag.Flag, error) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	if c.inMemoryCache == nil {
-		return nil, errors.New("impossible to read the flag before the initialisation")
+		return nil, ErrNotInitialised
 	}
 	return c.inMemoryCache.getFlag(key)
 }
@@ -100,7 +151,7 @@ func (c *cacheManagerImpl) AllFlags() (map[string]flag.Flag, error) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	if c.inMemoryCache == nil {
-		return nil, errors.New("impossible to read the flag before the initialisation")
+		return nil, ErrNotInitialised
 	}
 	return c.inMemoryCache.All(), nil
 }
@@ -110,3 +161,27 @@ func (c *cacheManagerImpl) GetLatestUpdateDate() time.Time {
 	defer c.mutex.RUnlock()
 	return c.latestUpdate
 }
+
+// GetGeneration returns the generation of the flags and the hash of their content.
+// The generation starts at 1 with the first configuration loaded and is incremented only when
+// the content of the flags changes.
+func (c *cacheManagerImpl) GetGeneration() (int64, string) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.generation, c.configurationHash
+}
+
+// unmarshal decodes the content using the file format (yaml, json, json5 or toml).
+func unmarshal(content []byte, fileFormat string, out interface{}) error {
+	switch strings.ToLower(fileFormat) {
+	case "toml":
+		return toml.Unmarshal(content, out)
+	case "json":
+		return json.Unmarshal(content, out)
+	case "json5", "jsonc":
+		return utils.UnmarshalFlagsJSON5(content, out)
+	default:
+		// default unmarshaller is YAML
+		return utils.UnmarshalFlagsYAML(content, out)
+	}
+}
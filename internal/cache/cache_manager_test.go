@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/thomaspoignant/go-feature-flag/internal/dto"
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
 
 	"github.com/stretchr/testify/assert"
@@ -456,3 +457,97 @@ func Test_cacheManagerImpl_GetLatestUpdateDate(t *testing.T) {
 
 	assert.True(t, timeBefore.Before(timeAfter))
 }
+
+func Test_ApplyDeltas(t *testing.T) {
+	loadedFlags := []byte(`flag-to-keep:
+  variations:
+    A: "keep"
+  defaultRule:
+    variation: A
+flag-to-update:
+  variations:
+    A: "before"
+  defaultRule:
+    variation: A
+flag-to-delete:
+  variations:
+    A: "delete"
+  defaultRule:
+    variation: A
+`)
+	loadedDelta := []byte(`upsert:
+  flag-to-update:
+    variations:
+      A: "after"
+    defaultRule:
+      variation: A
+  flag-to-add:
+    variations:
+      A: "added"
+    defaultRule:
+      variation: A
+delete:
+  - flag-to-delete
+`)
+
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}), nil)
+	newFlags, err := fCache.ConvertToFlagStruct(loadedFlags, "yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, fCache.UpdateCache(newFlags, nil))
+
+	delta, err := fCache.ConvertToDelta(loadedDelta, "yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"flag-to-delete"}, delta.Delete)
+	flags, err := fCache.ApplyDeltas([]dto.Delta{delta}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, flags, 3)
+
+	allFlags, err := fCache.AllFlags()
+	assert.NoError(t, err)
+	assert.Len(t, allFlags, 3)
+	assert.NotContains(t, allFlags, "flag-to-delete")
+	expected := map[string]string{
+		"flag-to-keep":   "keep",
+		"flag-to-update": "after",
+		"flag-to-add":    "added",
+	}
+	for key, value := range expected {
+		assert.Contains(t, allFlags, key)
+		assert.Equal(t, value, allFlags[key].GetVariationValue("A"), key)
+	}
+}
+
+func Test_CacheGeneration(t *testing.T) {
+	flagsV1 := map[string]dto.DTO{
+		"test-flag": {DTOv1: dto.DTOv1{
+			Variations:  &map[string]*interface{}{"A": testconvert.Interface(true), "B": testconvert.Interface(false)},
+			DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+		}},
+	}
+	flagsV2 := map[string]dto.DTO{
+		"test-flag": {DTOv1: dto.DTOv1{
+			Variations:  &map[string]*interface{}{"A": testconvert.Interface(true), "B": testconvert.Interface(false)},
+			DefaultRule: &flag.Rule{VariationResult: testconvert.String("B")},
+		}},
+	}
+
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}), nil)
+	generation, hash := fCache.GetGeneration()
+	assert.Equal(t, int64(0), generation, "no generation before the first configuration")
+	assert.Empty(t, hash)
+
+	assert.NoError(t, fCache.UpdateCache(flagsV1, nil))
+	generation, hashV1 := fCache.GetGeneration()
+	assert.Equal(t, int64(1), generation)
+	assert.NotEmpty(t, hashV1)
+
+	assert.NoError(t, fCache.UpdateCache(flagsV1, nil))
+	generation, hash = fCache.GetGeneration()
+	assert.Equal(t, int64(1), generation, "an unchanged configuration should keep the generation")
+	assert.Equal(t, hashV1, hash)
+
+	assert.NoError(t, fCache.UpdateCache(flagsV2, nil))
+	generation, hash = fCache.GetGeneration()
+	assert.Equal(t, int64(2), generation, "a changed configuration should increment the generation")
+	assert.NotEqual(t, hashV1, hash)
+}
@@ -71,7 +71,23 @@ func (fc *InMemoryCache) Init(flags map[string]dto.DTO) {
 			cache[key] = flagDto.Convert()
 		} else {
 			fflog.Printf(fc.Logger, "error: [cache] invalid configuration for flag %s: %s", key, err)
+			continue
 		}
+		for _, err := range flagToAdd.InvalidRegexOperators() {
+			fflog.Printf(fc.Logger, "warning: [cache] flag %s: %s, this rule never matches", key, err)
+		}
+	}
+
+	// the flags with a cycle in their prerequisites can't be evaluated
+	for key, err := range flag.FindPrerequisiteCycles(cache) {
+		fflog.Printf(fc.Logger, "error: [cache] invalid configuration for flag %s: %s", key, err)
+		delete(cache, key)
+	}
+
+	// the flags of a layer sharing the same users can't be evaluated
+	for key, err := range flag.FindLayerOverlaps(cache) {
+		fflog.Printf(fc.Logger, "error: [cache] invalid configuration for flag %s: %s", key, err)
+		delete(cache, key)
 	}
 	fc.Flags = cache
 }
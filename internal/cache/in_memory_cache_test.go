@@ -1,6 +1,8 @@
 package cache_test
 
 import (
+	"bytes"
+	"log"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -139,3 +141,62 @@ func TestCopy(t *testing.T) {
 		})
 	}
 }
+
+func TestInit_PrerequisiteCycle(t *testing.T) {
+	newFlag := func(prerequisites ...flag.Prerequisite) dto.DTO {
+		return dto.DTO{
+			DTOv1: dto.DTOv1{
+				Variations: &map[string]*interface{}{
+					"on":  testconvert.Interface(true),
+					"off": testconvert.Interface(false),
+				},
+				DefaultRule:   &flag.Rule{VariationResult: testconvert.String("on")},
+				Prerequisites: &prerequisites,
+			},
+		}
+	}
+
+	c := cache.NewInMemoryCache(nil)
+	c.Init(map[string]dto.DTO{
+		"flag-a": newFlag(flag.Prerequisite{Flag: "flag-b", Variation: "on"}),
+		"flag-b": newFlag(flag.Prerequisite{Flag: "flag-a", Variation: "on"}),
+		"flag-c": newFlag(flag.Prerequisite{Flag: "flag-d", Variation: "on"}),
+		"flag-d": newFlag(),
+	})
+
+	// the flags in the cycle are rejected, the others are kept
+	assert.ElementsMatch(t, []string{"flag-c", "flag-d"}, keys(c.All()))
+}
+
+func TestInit_InvalidRegex(t *testing.T) {
+	var buf bytes.Buffer
+	c := cache.NewInMemoryCache(log.New(&buf, "", 0))
+	c.Init(map[string]dto.DTO{
+		"flag-a": {
+			DTOv1: dto.DTOv1{
+				Variations: &map[string]*interface{}{
+					"on":  testconvert.Interface(true),
+					"off": testconvert.Interface(false),
+				},
+				Rules: &[]flag.Rule{{
+					Name:            testconvert.String("staff"),
+					Query:           testconvert.String(`email matchesRegex "^(.*@gofeatureflag\\.org$"`),
+					VariationResult: testconvert.String("on"),
+				}},
+				DefaultRule: &flag.Rule{VariationResult: testconvert.String("off")},
+			},
+		},
+	})
+
+	// the flag is kept, but the invalid regex is reported with the logger of the cache
+	assert.ElementsMatch(t, []string{"flag-a"}, keys(c.All()))
+	assert.Contains(t, buf.String(), "warning: [cache] flag flag-a: rule staff: invalid regex")
+}
+
+func keys(flags map[string]flag.Flag) []string {
+	result := make([]string, 0, len(flags))
+	for key := range flags {
+		result = append(result, key)
+	}
+	return result
+}
@@ -60,6 +60,10 @@ func (c *notificationService) getDifferences(
 	for key := range oldCache {
 		newFlag, inNewCache := newCache[key]
 		oldFlag := oldCache[key]
+		// the changes of an archived flag are not notified, archiving a flag is notified as an update.
+		if isArchived(oldFlag) && (!inNewCache || isArchived(newFlag)) {
+			continue
+		}
 		if !inNewCache {
 			diff.Deleted[key] = oldFlag
 			continue
@@ -76,8 +80,17 @@ func (c *notificationService) getDifferences(
 	for key := range newCache {
 		if _, inOldCache := oldCache[key]; !inOldCache {
 			f := newCache[key]
+			if isArchived(f) {
+				continue
+			}
 			diff.Added[key] = f
 		}
 	}
 	return diff
 }
+
+// isArchived returns true if the flag is archived.
+func isArchived(f flag.Flag) bool {
+	internalFlag, ok := f.(*flag.InternalFlag)
+	return ok && internalFlag.IsArchived()
+}
@@ -238,6 +238,61 @@ func Test_notificationService_getDifferences(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Archived flags are not notified",
+			args: args{
+				oldCache: map[string]flag.Flag{
+					"archived-flag": &flag.InternalFlag{
+						Archived:    testconvert.Bool(true),
+						Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+						DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+					},
+					"deleted-archived-flag": &flag.InternalFlag{
+						Archived:    testconvert.Bool(true),
+						Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+						DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+					},
+					"archiving-flag": &flag.InternalFlag{
+						Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+						DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+					},
+				},
+				newCache: map[string]flag.Flag{
+					"archived-flag": &flag.InternalFlag{
+						Archived:    testconvert.Bool(true),
+						Variations:  &map[string]*interface{}{"A": testconvert.Interface("B")},
+						DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+					},
+					"added-archived-flag": &flag.InternalFlag{
+						Archived:    testconvert.Bool(true),
+						Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+						DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+					},
+					"archiving-flag": &flag.InternalFlag{
+						Archived:    testconvert.Bool(true),
+						Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+						DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+					},
+				},
+			},
+			want: notifier.DiffCache{
+				Added:   map[string]flag.Flag{},
+				Deleted: map[string]flag.Flag{},
+				Updated: map[string]notifier.DiffUpdated{
+					"archiving-flag": {
+						Before: &flag.InternalFlag{
+							Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+							DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+						},
+						After: &flag.InternalFlag{
+							Archived:    testconvert.Bool(true),
+							Variations:  &map[string]*interface{}{"A": testconvert.Interface("A")},
+							DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2,6 +2,13 @@ package dto
 
 import "github.com/thomaspoignant/go-feature-flag/internal/flag"
 
+const (
+	// booleanShorthandEnabled is the name of the variation serving true for the flags using the shorthand format.
+	booleanShorthandEnabled = "enabled"
+	// booleanShorthandDisabled is the name of the variation serving false for the flags using the shorthand format.
+	booleanShorthandDisabled = "disabled"
+)
+
 // ConvertV1DtoToInternalFlag is converting a DTO to a flag.InternalFlag
 func ConvertV1DtoToInternalFlag(dto DTO) flag.InternalFlag {
 	var experimentation *flag.ExperimentationRollout
@@ -12,15 +19,53 @@ func ConvertV1DtoToInternalFlag(dto DTO) flag.InternalFlag {
 		}
 	}
 
-	return flag.InternalFlag{
-		Variations:      dto.Variations,
-		Rules:           dto.Rules,
-		DefaultRule:     dto.DefaultRule,
-		TrackEvents:     dto.TrackEvents,
-		Disable:         dto.Disable,
-		Version:         dto.Version,
-		Scheduled:       dto.Scheduled,
-		Experimentation: experimentation,
-		Metadata:        dto.Metadata,
+	variations, defaultRule := dto.Variations, dto.DefaultRule
+	if variations == nil && dto.Percentage != nil {
+		variations, defaultRule = expandBooleanShorthand(*dto.Percentage, defaultRule)
+	}
+
+	internalFlag := flag.InternalFlag{
+		Variations:             variations,
+		Rules:                  dto.Rules,
+		DefaultRule:            defaultRule,
+		TrackEvents:            dto.TrackEvents,
+		Disable:                dto.Disable,
+		Archived:               dto.Archived,
+		Version:                dto.Version,
+		Scheduled:              dto.Scheduled,
+		Experimentation:        experimentation,
+		Metadata:               dto.Metadata,
+		VariationMetadata:      dto.VariationMetadata,
+		SeedRotation:           dto.SeedRotation,
+		AnonymousBucketingSalt: dto.AnonymousBucketingSalt,
+		BucketingSalt:          dto.BucketingSalt,
+		ExpirationDate:         dto.ExpirationDate,
+		Prerequisites:          dto.Prerequisites,
+		Layer:                  dto.Layer,
+		Holdout:                dto.Holdout,
+		Environments:           dto.Environments,
+	}
+	internalFlag.ParseSeedRotation()
+	return internalFlag
+}
+
+// expandBooleanShorthand returns the variations and the default rule of a boolean flag defined with
+// the shorthand format, where only the percentage of the users receiving true is configured.
+// The variations are "enabled" (true) and "disabled" (false), the default rule is kept if one is provided.
+func expandBooleanShorthand(percentage float64, defaultRule *flag.Rule,
+) (*map[string]*interface{}, *flag.Rule) {
+	enabled, disabled := interface{}(true), interface{}(false)
+	variations := &map[string]*interface{}{
+		booleanShorthandEnabled:  &enabled,
+		booleanShorthandDisabled: &disabled,
+	}
+	if defaultRule == nil {
+		defaultRule = &flag.Rule{
+			Percentages: &map[string]float64{
+				booleanShorthandEnabled:  percentage,
+				booleanShorthandDisabled: 100 - percentage,
+			},
+		}
 	}
+	return variations, defaultRule
 }
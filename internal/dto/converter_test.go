@@ -1,6 +1,7 @@
 package dto_test
 
 import (
+	"fmt"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/thomaspoignant/go-feature-flag/internal/dto"
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
 	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
 )
 
@@ -1154,3 +1156,49 @@ func TestConvertV0ScheduleStep(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertBooleanShorthand(t *testing.T) {
+	config := []byte(`shorthand-flag:
+  percentage: 20
+expanded-flag:
+  variations:
+    enabled: true
+    disabled: false
+  defaultRule:
+    percentage:
+      enabled: 20
+      disabled: 80
+shorthand-with-rules:
+  percentage: 0
+  targeting:
+    - query: beta eq true
+      variation: enabled
+`)
+	var flags map[string]dto.DTO
+	assert.NoError(t, utils.UnmarshalFlagsYAML(config, &flags))
+
+	shorthand := flags["shorthand-flag"]
+	expanded := flags["expanded-flag"]
+	shorthandFlag, expandedFlag := shorthand.Convert(), expanded.Convert()
+	assert.NoError(t, shorthandFlag.IsValid())
+	assert.Equal(t, expandedFlag.Variations, shorthandFlag.Variations)
+	assert.Equal(t, expandedFlag.DefaultRule, shorthandFlag.DefaultRule)
+
+	// the shorthand is evaluated exactly like its expanded form
+	for i := 0; i < 1000; i++ {
+		user := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+		shorthandValue, shorthandDetails := shorthandFlag.Value("my-flag", user, flag.Context{})
+		expandedValue, expandedDetails := expandedFlag.Value("my-flag", user, flag.Context{})
+		assert.Equal(t, expandedValue, shorthandValue)
+		assert.Equal(t, expandedDetails, shorthandDetails)
+	}
+
+	withRules := flags["shorthand-with-rules"]
+	withRulesFlag := withRules.Convert()
+	assert.NoError(t, withRulesFlag.IsValid())
+	value, _ := withRulesFlag.Value("my-flag",
+		ffcontext.NewEvaluationContextBuilder("user-key").AddCustom("beta", true).Build(), flag.Context{})
+	assert.Equal(t, true, value)
+	value, _ = withRulesFlag.Value("my-flag", ffcontext.NewEvaluationContext("user-key"), flag.Context{})
+	assert.Equal(t, false, value)
+}
@@ -45,6 +45,7 @@ func ConvertV0DtoToInternalFlag(d DTO, isScheduledStep bool) flag.InternalFlag {
 		DefaultRule: defaultRule,
 		TrackEvents: d.TrackEvents,
 		Disable:     d.Disable,
+		Archived:    d.Archived,
 		Version:     d.Version,
 	}
 
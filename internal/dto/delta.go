@@ -0,0 +1,51 @@
+package dto
+
+import "sort"
+
+// Delta is a partial flag configuration returned by a retriever that only sends the flags that have changed.
+// Instead of replacing the full configuration, the delta is applied on top of the current flags.
+type Delta struct {
+	// Upsert contains the flags to add or to replace.
+	Upsert map[string]DTO `json:"upsert,omitempty" yaml:"upsert,omitempty" toml:"upsert,omitempty"`
+
+	// Delete contains the keys of the flags to remove.
+	Delete []string `json:"delete,omitempty" yaml:"delete,omitempty" toml:"delete,omitempty"`
+}
+
+// Apply returns a new map of flags with the delta applied on top of flags.
+// The upserts are applied before the deletes, so a flag present in both is removed.
+func (d Delta) Apply(flags map[string]DTO) map[string]DTO {
+	result := make(map[string]DTO, len(flags)+len(d.Upsert))
+	for key, value := range flags {
+		result[key] = value
+	}
+	for key, value := range d.Upsert {
+		result[key] = value
+	}
+	for _, key := range d.Delete {
+		delete(result, key)
+	}
+	return result
+}
+
+// Merge returns a delta equivalent to applying d and then next.
+// It is used to keep a single delta containing all the changes returned by a retriever.
+func (d Delta) Merge(next Delta) Delta {
+	result := Delta{Upsert: map[string]DTO{}}
+	deleted := map[string]struct{}{}
+	for _, delta := range []Delta{d, next} {
+		for key, value := range delta.Upsert {
+			result.Upsert[key] = value
+			delete(deleted, key)
+		}
+		for _, key := range delta.Delete {
+			delete(result.Upsert, key)
+			deleted[key] = struct{}{}
+		}
+	}
+	for key := range deleted {
+		result.Delete = append(result.Delete, key)
+	}
+	sort.Strings(result.Delete)
+	return result
+}
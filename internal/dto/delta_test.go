@@ -0,0 +1,27 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/internal/dto"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestDelta_Merge(t *testing.T) {
+	flagA := dto.DTO{Version: testconvert.String("1")}
+	flagB := dto.DTO{Version: testconvert.String("2")}
+	flagC := dto.DTO{Version: testconvert.String("3")}
+	flags := map[string]dto.DTO{"flag-a": flagA, "flag-d": flagA}
+
+	first := dto.Delta{Upsert: map[string]dto.DTO{"flag-b": flagB, "flag-c": flagC}, Delete: []string{"flag-a"}}
+	second := dto.Delta{Upsert: map[string]dto.DTO{"flag-a": flagC}, Delete: []string{"flag-c", "flag-d"}}
+
+	merged := first.Merge(second)
+	assert.Equal(t, second.Apply(first.Apply(flags)), merged.Apply(flags))
+	assert.Equal(t, dto.Delta{
+		Upsert: map[string]dto.DTO{"flag-a": flagC, "flag-b": flagB},
+		Delete: []string{"flag-c", "flag-d"},
+	}, merged)
+	assert.Equal(t, first.Apply(flags), dto.Delta{}.Merge(first).Apply(flags))
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
 )
 
@@ -17,6 +19,10 @@ type DTO struct {
 	// Disable is true if the flag is disabled.
 	Disable *bool `json:"disable,omitempty" yaml:"disable,omitempty" toml:"disable,omitempty"`
 
+	// Archived is true if the flag is archived, an archived flag serves the SDK default value
+	// and is not part of the bulk evaluations (AllFlagsState) nor of the change notifications.
+	Archived *bool `json:"archived,omitempty" yaml:"archived,omitempty" toml:"archived,omitempty"`
+
 	// Version (optional) This field contains the version of the flag.
 	// The version is manually managed when you configure your flags and, it is used to display the information
 	// in the notifications and data collection.
@@ -53,6 +59,46 @@ type DTOv1 struct {
 
 	// Metadata is a field containing information about your flag such as an issue tracker link, a description, etc ...
 	Metadata *map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty" toml:"metadata,omitempty" jsonschema:"title=metadata,description=A field containing information about your flag such as an issue tracker link a description etc..."` // nolint: lll
+
+	// VariationMetadata (optional) contains information about each variation (ex: the description of a treatment),
+	// the key is the name of the variation.
+	// The metadata of the variation selected is returned in the details of the evaluation.
+	VariationMetadata *map[string]map[string]interface{} `json:"variationMetadata,omitempty" yaml:"variationMetadata,omitempty" toml:"variationMetadata,omitempty" jsonschema:"title=variationMetadata,description=Information about each variation the key is the name of the variation. The metadata of the variation selected is returned in the details of the evaluation."` // nolint: lll
+
+	// SeedRotation (optional) is the interval after which the users are re-assigned to new buckets
+	// for the percentage rollouts (ex: "168h" to have a new cohort every week).
+	SeedRotation *string `json:"seedRotation,omitempty" yaml:"seedRotation,omitempty" toml:"seedRotation,omitempty" jsonschema:"title=seedRotation,description=Interval after which the users are re-assigned to new buckets for the percentage rollouts (ex: 168h)."` // nolint: lll
+
+	// AnonymousBucketingSalt (optional) is a salt added when bucketing the anonymous users in the percentage rollouts.
+	// It ensures that the bucket of an anonymous user is not correlated to its bucket once identified.
+	AnonymousBucketingSalt *string `json:"anonymousBucketingSalt,omitempty" yaml:"anonymousBucketingSalt,omitempty" toml:"anonymousBucketingSalt,omitempty" jsonschema:"title=anonymousBucketingSalt,description=Salt added when bucketing the anonymous users so their bucket is not correlated to their bucket once identified."` // nolint: lll
+
+	// BucketingSalt (optional) replaces the flag name when bucketing the users in the percentage rollouts.
+	// The flags sharing the same salt assign the same users to the same buckets, the flags with
+	// different salts are not correlated.
+	BucketingSalt *string `json:"bucketingSalt,omitempty" yaml:"bucketingSalt,omitempty" toml:"bucketingSalt,omitempty" jsonschema:"title=bucketingSalt,description=Salt replacing the flag name when bucketing the users. The flags sharing the same salt assign the same users to the same buckets."` // nolint: lll
+
+	// ExpirationDate (optional) is the date after which the flag is expired.
+	// An expired flag always serves the default value, whatever the rules are.
+	ExpirationDate *time.Time `json:"expirationDate,omitempty" yaml:"expirationDate,omitempty" toml:"expirationDate,omitempty" jsonschema:"title=expirationDate,description=Date after which the flag is expired and always serves the default value."` // nolint: lll
+
+	// Prerequisites (optional) are the flags that should serve a specific variation for this flag to be evaluated.
+	// If a prerequisite is not met, the flag serves the default value with the reason PREREQUISITE_FAILED.
+	Prerequisites *[]flag.Prerequisite `json:"prerequisites,omitempty" yaml:"prerequisites,omitempty" toml:"prerequisites,omitempty" jsonschema:"title=prerequisites,description=Flags that should serve a specific variation for this flag to be evaluated."` // nolint: lll
+
+	// Layer (optional) makes the flag mutually exclusive with the other flags of the same layer.
+	// The flag is evaluated only for the users in its slice of the layer, the other users get the
+	// default value with the reason LAYER_EXCLUDED.
+	Layer *flag.Layer `json:"layer,omitempty" yaml:"layer,omitempty" toml:"layer,omitempty" jsonschema:"title=layer,description=Layer of mutually exclusive flags the flag is part of."` // nolint: lll
+
+	// Holdout (optional) is a slice of the users permanently excluded from the flag, they always get the
+	// control variation (or the default value if no variation is set) with the reason HOLDOUT.
+	Holdout *flag.Holdout `json:"holdout,omitempty" yaml:"holdout,omitempty" toml:"holdout,omitempty" jsonschema:"title=holdout,description=Slice of the users permanently excluded from the flag and always getting the control variation."` // nolint: lll
+
+	// Environments (optional) contains the fields overridden for each environment, the key is the name of the
+	// environment. The environment configured in the client selects the override, if the environment is not
+	// in the map the base definition of the flag is used.
+	Environments *map[string]flag.Environment `json:"environments,omitempty" yaml:"environments,omitempty" toml:"environments,omitempty" jsonschema:"title=environments,description=Fields of the flag overridden for each environment. The key is the name of the environment."` // nolint: lll
 }
 
 // DTOv0 describe the fields of a flag.
@@ -1,5 +1,17 @@
 package flag
 
+import (
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+// Hasher computes the hash of a bucketing key, the hash is used to assign the users to the buckets
+// of the percentage rollouts.
+type Hasher interface {
+	Hash(key string) uint32
+}
+
 type Context struct {
 	// EvaluationContextEnrichment will be merged with the evaluation context sent during the evaluation.
 	// It is useful to add common attributes to all the evaluation, such as a server version, environment, ...
@@ -11,6 +23,36 @@ type Context struct {
 
 	// DefaultSdkValue is the default value of the SDK when calling the variation.
 	DefaultSdkValue interface{}
+
+	// EvaluationDate (optional) is the date used to evaluate the flag.
+	// Default: time.Now()
+	EvaluationDate time.Time
+
+	// GetPrerequisite (optional) is used to retrieve the flags used as prerequisites.
+	// If nil, the prerequisites of a flag are considered as not met.
+	GetPrerequisite func(flagKey string) (Flag, error)
+
+	// Disabled (optional) forces the flag to be evaluated as a disabled flag (ex: when the kill switch is on).
+	// Default: false
+	Disabled bool
+
+	// Hasher (optional) is used to compute the hash of the bucketing keys.
+	// Default: the internal hash function of go-feature-flag
+	Hasher Hasher
+
+	// OnPrerequisiteEvaluated (optional) is called every time a prerequisite has been evaluated.
+	OnPrerequisiteEvaluated func(flagKey string, prerequisite Flag, value interface{}, details ResolutionDetails)
+
+	// prerequisiteDepth is the number of nested prerequisites currently evaluated.
+	prerequisiteDepth int
+}
+
+// GetEvaluationDate returns the date to use for the evaluation.
+func (s *Context) GetEvaluationDate() time.Time {
+	if s.EvaluationDate.IsZero() {
+		return time.Now()
+	}
+	return s.EvaluationDate
 }
 
 func (s *Context) AddIntoEvaluationContextEnrichment(key string, value interface{}) {
@@ -19,3 +61,17 @@ func (s *Context) AddIntoEvaluationContextEnrichment(key string, value interface
 	}
 	s.EvaluationContextEnrichment[key] = value
 }
+
+// GetEnvironment returns the environment of the evaluation, set in the enrichment with the key "env".
+func (s *Context) GetEnvironment() string {
+	env, _ := s.EvaluationContextEnrichment["env"].(string)
+	return env
+}
+
+// hash returns the hash of a bucketing key using the Hasher of the context if any.
+func (s *Context) hash(key string) uint32 {
+	if s.Hasher == nil {
+		return utils.Hash(key)
+	}
+	return s.Hasher.Hash(key)
+}
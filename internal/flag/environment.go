@@ -0,0 +1,23 @@
+package flag
+
+// Environment contains the fields of a flag overridden for a specific environment.
+type Environment struct {
+	// DefaultRule (optional) replaces the default rule of the flag in this environment.
+	DefaultRule *Rule `json:"defaultRule,omitempty" yaml:"defaultRule,omitempty" toml:"defaultRule,omitempty" jsonschema:"title=defaultRule,description=Default rule of the flag in this environment."` // nolint: lll
+}
+
+// forEnvironment returns the flag to evaluate in the environment, the fields overridden for
+// this environment replace the ones of the base definition.
+// If the environment is not configured in the flag, the flag itself is returned.
+func (f *InternalFlag) forEnvironment(environment string) *InternalFlag {
+	if f.Environments == nil || environment == "" {
+		return f
+	}
+	override, ok := (*f.Environments)[environment]
+	if !ok || override.DefaultRule == nil {
+		return f
+	}
+	flagCopy := *f
+	flagCopy.DefaultRule = override.DefaultRule
+	return &flagCopy
+}
@@ -0,0 +1,127 @@
+package flag
+
+import (
+	"maps"
+	"regexp"
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+// comparisonClause is matching a comparison in a query, ex: email ew "@gofeatureflag.org", age gt 40, beta pr
+var comparisonClause = regexp.MustCompile(`([a-zA-Z0-9_.\-]+)\s+(?:` +
+	`((?i:eq|ne|gt|lt|ge|le|co|sw|ew|in)\b|==|!=|>=|<=|>|<|` +
+	regexOperator + `\b|` + dateBeforeOperator + `\b|` + dateAfterOperator + `\b)` +
+	`\s+("(?:[^"\\]|\\.)*"|\[[^\]]*\]|[a-zA-Z0-9_.\-]+)` +
+	`|((?i:pr)\b))`)
+
+// RuleTrace is the trace of the evaluation of a rule for an evaluation context.
+type RuleTrace struct {
+	// Name is the name of the rule.
+	Name string `json:"name,omitempty"`
+
+	// RuleIndex is the index of the rule in the targeting, it is nil for the default rule.
+	RuleIndex *int `json:"ruleIndex,omitempty"`
+
+	// Query is the query of the rule.
+	Query string `json:"query,omitempty"`
+
+	// Disabled is true if the rule is disabled, a disabled rule never applies.
+	Disabled bool `json:"disabled"`
+
+	// Matched is true if the query of the rule matches the evaluation context.
+	// It is always true for the default rule.
+	Matched bool `json:"matched"`
+
+	// Comparisons are the comparisons of the query evaluated one by one.
+	// They are not available for the queries in the JSONLogic format.
+	Comparisons []ComparisonTrace `json:"comparisons,omitempty"`
+}
+
+// ComparisonTrace is the result of one comparison of a query for an evaluation context.
+type ComparisonTrace struct {
+	// Clause is the comparison as written in the query, ex: email ew "@gofeatureflag.org"
+	Clause string `json:"clause"`
+
+	// Attribute is the name of the attribute of the evaluation context compared.
+	Attribute string `json:"attribute"`
+
+	// Operator is the operator of the comparison.
+	Operator string `json:"operator"`
+
+	// Operand is the value the attribute is compared to, as written in the query.
+	Operand string `json:"operand,omitempty"`
+
+	// ContextValue is the value of the attribute in the evaluation context, nil if the attribute is missing.
+	ContextValue interface{} `json:"contextValue"`
+
+	// Matched is true if the comparison is true for the evaluation context.
+	Matched bool `json:"matched"`
+}
+
+// Explain returns the trace of each rule of the flag for this evaluation context,
+// the default rule is the last element of the trace.
+// It does not evaluate the flag, the result of the evaluation is given by Value.
+func (f *InternalFlag) Explain(evaluationCtx ffcontext.Context, flagContext Context) []RuleTrace {
+	evaluationDate := flagContext.GetEvaluationDate()
+	f = f.withScheduledRolloutSteps(evaluationDate)
+	f = f.forEnvironment(flagContext.GetEnvironment())
+
+	ctxMap := utils.ContextToMap(evaluationCtx)
+	maps.Copy(ctxMap, flagContext.EvaluationContextEnrichment)
+
+	traces := make([]RuleTrace, 0, len(f.GetRules())+1)
+	for index, rule := range f.GetRules() {
+		ruleIndex := index
+		query := rule.GetTrimmedQuery()
+		trace := RuleTrace{
+			Name:      rule.GetName(),
+			RuleIndex: &ruleIndex,
+			Query:     query,
+			Disabled:  rule.IsDisable(),
+			Matched:   query == "" || evaluateQuery(query, maps.Clone(ctxMap), evaluationDate),
+		}
+		if query != "" && !isJSONLogicQuery(query) {
+			trace.Comparisons = explainQuery(query, ctxMap, evaluationDate)
+		}
+		traces = append(traces, trace)
+	}
+
+	if f.DefaultRule != nil {
+		traces = append(traces, RuleTrace{
+			Name:    f.GetDefaultRule().GetName(),
+			Matched: true,
+		})
+	}
+	return traces
+}
+
+// explainQuery evaluates each comparison of a query in the nikunjy/rules format separately.
+func explainQuery(query string, ctxMap map[string]interface{}, evaluationDate time.Time) []ComparisonTrace {
+	matches := comparisonClause.FindAllStringSubmatchIndex(query, -1)
+	comparisons := make([]ComparisonTrace, 0, len(matches))
+	for _, match := range matches {
+		// the clause is part of a string literal, it is not a comparison.
+		if isInsideStringLiteral(query[:match[0]]) {
+			continue
+		}
+
+		clause := query[match[0]:match[1]]
+		attribute := query[match[2]:match[3]]
+		comparison := ComparisonTrace{
+			Clause:       clause,
+			Attribute:    attribute,
+			ContextValue: getAttributeValue(ctxMap, attribute),
+			Matched:      evaluateQuery(clause, maps.Clone(ctxMap), evaluationDate),
+		}
+		if match[4] != -1 {
+			comparison.Operator = query[match[4]:match[5]]
+			comparison.Operand = query[match[6]:match[7]]
+		} else {
+			comparison.Operator = query[match[8]:match[9]]
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
@@ -0,0 +1,62 @@
+package flag
+
+import (
+	"fmt"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+)
+
+// Holdout is a slice of the users permanently excluded from the flag, whatever the targeting is.
+// The users of the holdout always get the control variation, they are used to measure the long-term
+// impact of the flag.
+type Holdout struct {
+	// Percentage is the percentage of the users in the holdout.
+	Percentage float64 `json:"percentage" yaml:"percentage" toml:"percentage" jsonschema:"required,title=percentage,description=Percentage of the users in the holdout."` // nolint: lll
+
+	// Variation is the control variation served to the users of the holdout.
+	// If not set, the users of the holdout get the SDK default value.
+	Variation *string `json:"variation,omitempty" yaml:"variation,omitempty" toml:"variation,omitempty" jsonschema:"title=variation,description=Control variation served to the users of the holdout. If not set the SDK default value is served."` // nolint: lll
+}
+
+// IsValid is checking if the holdout is valid.
+func (h *Holdout) IsValid() error {
+	if h.Percentage < 0 || h.Percentage > 100 {
+		return fmt.Errorf("invalid holdout: the percentage should be between 0 and 100, got %v", h.Percentage)
+	}
+	return nil
+}
+
+// GetVariation is the getter of the field Variation.
+func (h *Holdout) GetVariation() string {
+	if h.Variation == nil {
+		return ""
+	}
+	return *h.Variation
+}
+
+// isInHoldout is checking if the user is part of the holdout of the flag.
+// The bucket in the holdout depends only on the flag name and the user key, so the holdout
+// is stable when the rules, the percentages or the seed of the flag change.
+func (f *InternalFlag) isInHoldout(flagName string, ctx ffcontext.Context, flagContext Context) bool {
+	if f.Holdout == nil || f.Holdout.Percentage <= 0 {
+		return false
+	}
+	bucket := flagContext.hash("holdout:"+flagName+ctx.GetKey()) % MaxPercentage
+	return bucket < uint32(percentageToBuckets(f.Holdout.Percentage))
+}
+
+// holdoutValue returns the control variation served to the users of the holdout,
+// or the SDK default value if the holdout has no variation.
+func (f *InternalFlag) holdoutValue(flagContext Context) (interface{}, ResolutionDetails) {
+	variation := f.Holdout.GetVariation()
+	if variation == "" {
+		return f.sdkDefaultValue(flagContext, ReasonHoldout, f.isCacheable())
+	}
+	return f.GetVariationValue(variation), ResolutionDetails{
+		Variant:           variation,
+		Reason:            ReasonHoldout,
+		Cacheable:         f.isCacheable(),
+		Metadata:          f.GetMetadata(),
+		VariationMetadata: f.GetVariationMetadata(variation),
+	}
+}
@@ -0,0 +1,103 @@
+package flag_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func newHoldoutFlag(treatmentPercentage float64) *flag.InternalFlag {
+	return &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"treatment": testconvert.Interface(true),
+			"control":   testconvert.Interface(false),
+		},
+		DefaultRule: &flag.Rule{Percentages: &map[string]float64{
+			"treatment": treatmentPercentage,
+			"control":   100 - treatmentPercentage,
+		}},
+		Holdout: &flag.Holdout{Percentage: 5, Variation: testconvert.String("control")},
+	}
+}
+
+func TestInternalFlag_ValueWithHoldout(t *testing.T) {
+	const nbUsers = 10000
+	sdkDefault := flag.Context{DefaultSdkValue: true}
+
+	// the users of the holdout are the same whatever the rollout is
+	var holdout map[string]bool
+	for _, rollout := range []float64{10, 50, 100} {
+		f := newHoldoutFlag(rollout)
+		inHoldout := map[string]bool{}
+		for i := 0; i < nbUsers; i++ {
+			key := fmt.Sprintf("user-%d", i)
+			value, details := f.Value("new-checkout", ffcontext.NewEvaluationContext(key), sdkDefault)
+			if details.Reason == flag.ReasonHoldout {
+				assert.Equal(t, false, value)
+				assert.Equal(t, "control", details.Variant)
+				inHoldout[key] = true
+				continue
+			}
+			if rollout == 100 {
+				assert.Equal(t, true, value, "%s is not in the holdout and should get the treatment", key)
+			}
+		}
+		assert.InDelta(t, nbUsers*0.05, len(inHoldout), nbUsers*0.01)
+		if holdout != nil {
+			assert.Equal(t, holdout, inHoldout, "the holdout should be stable when the rollout changes")
+		}
+		holdout = inHoldout
+	}
+}
+
+func TestInternalFlag_ValueWithHoldout_noVariation(t *testing.T) {
+	f := &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"treatment": testconvert.Interface(true),
+		},
+		DefaultRule: &flag.Rule{VariationResult: testconvert.String("treatment")},
+		Holdout:     &flag.Holdout{Percentage: 100},
+	}
+	value, details := f.Value("new-checkout", ffcontext.NewEvaluationContext("user-key"),
+		flag.Context{DefaultSdkValue: false})
+	assert.Equal(t, false, value)
+	assert.Equal(t, flag.VariationSDKDefault, details.Variant)
+	assert.Equal(t, flag.ReasonHoldout, details.Reason)
+}
+
+func TestHoldout_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		holdout *flag.Holdout
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "valid holdout",
+			holdout: &flag.Holdout{Percentage: 5, Variation: testconvert.String("control")},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "negative percentage",
+			holdout: &flag.Holdout{Percentage: -1},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "percentage above 100",
+			holdout: &flag.Holdout{Percentage: 101},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, tt.holdout.IsValid())
+		})
+	}
+
+	f := newHoldoutFlag(100)
+	f.Holdout.Variation = testconvert.String("unknown")
+	assert.Error(t, f.IsValid())
+}
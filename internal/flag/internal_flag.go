@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 	"maps"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/mitchellh/copystructure"
 	"github.com/thomaspoignant/go-feature-flag/internal/internalerror"
 	"github.com/thomaspoignant/go-feature-flag/internal/utils"
 )
@@ -13,6 +16,9 @@ import (
 const (
 	PercentageMultiplier = float64(1000)
 	MaxPercentage        = uint32(100 * PercentageMultiplier)
+
+	// secondaryKeyDelimiter separates the key and the secondary key of the context in the bucketing key.
+	secondaryKeyDelimiter = "\x00"
 )
 
 // InternalFlag is the internal representation of a flag when using go-feature-flag.
@@ -47,6 +53,10 @@ type InternalFlag struct {
 	// Disable is true if the flag is disabled.
 	Disable *bool `json:"disable,omitempty" yaml:"disable,omitempty" toml:"disable,omitempty"`
 
+	// Archived is true if the flag is archived, an archived flag serves the SDK default value
+	// and is not part of the bulk evaluations (AllFlagsState) nor of the change notifications.
+	Archived *bool `json:"archived,omitempty" yaml:"archived,omitempty" toml:"archived,omitempty"`
+
 	// Version (optional) This field contains the version of the flag.
 	// The version is manually managed when you configure your flags, and it is used to display the information
 	// in the notifications and data collection.
@@ -54,6 +64,45 @@ type InternalFlag struct {
 
 	// Metadata is a field containing information about your flag such as an issue tracker link, a description, etc ...
 	Metadata *map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty" toml:"metadata,omitempty"`
+
+	// VariationMetadata (optional) contains information about each variation, the key is the name of the variation.
+	VariationMetadata *map[string]map[string]interface{} `json:"variationMetadata,omitempty" yaml:"variationMetadata,omitempty" toml:"variationMetadata,omitempty"` // nolint: lll
+
+	// SeedRotation (optional) is the interval after which the users are re-assigned to new buckets
+	// for the percentage rollouts (ex: "168h" to have a new cohort every week).
+	// Within a period the assignment of a user is stable.
+	SeedRotation *string `json:"seedRotation,omitempty" yaml:"seedRotation,omitempty" toml:"seedRotation,omitempty"`
+
+	// ParsedSeedRotation is the duration of SeedRotation, it is set by ParseSeedRotation when the flag is loaded.
+	ParsedSeedRotation time.Duration `json:"-" yaml:"-" toml:"-"`
+
+	// AnonymousBucketingSalt (optional) is a salt added when bucketing the anonymous users in the percentage rollouts.
+	// It ensures that the bucket of an anonymous user is not correlated to its bucket once identified.
+	AnonymousBucketingSalt *string `json:"anonymousBucketingSalt,omitempty" yaml:"anonymousBucketingSalt,omitempty" toml:"anonymousBucketingSalt,omitempty"` // nolint: lll
+
+	// BucketingSalt (optional) replaces the flag name when bucketing the users in the percentage rollouts.
+	// The flags sharing the same salt assign the same users to the same buckets, the flags with
+	// different salts are not correlated.
+	BucketingSalt *string `json:"bucketingSalt,omitempty" yaml:"bucketingSalt,omitempty" toml:"bucketingSalt,omitempty"` // nolint: lll
+
+	// ExpirationDate (optional) is the date after which the flag is expired.
+	// An expired flag always serves the default value, whatever the rules are.
+	ExpirationDate *time.Time `json:"expirationDate,omitempty" yaml:"expirationDate,omitempty" toml:"expirationDate,omitempty"` // nolint: lll
+
+	// Prerequisites (optional) are the flags that should serve a specific variation for this flag to be evaluated.
+	Prerequisites *[]Prerequisite `json:"prerequisites,omitempty" yaml:"prerequisites,omitempty" toml:"prerequisites,omitempty"` // nolint: lll
+
+	// Layer (optional) makes the flag mutually exclusive with the other flags of the same layer.
+	// The flag is evaluated only for the users in its slice of the layer.
+	Layer *Layer `json:"layer,omitempty" yaml:"layer,omitempty" toml:"layer,omitempty"`
+
+	// Holdout (optional) is a slice of the users permanently excluded from the flag, they always get
+	// the control variation whatever the targeting is.
+	Holdout *Holdout `json:"holdout,omitempty" yaml:"holdout,omitempty" toml:"holdout,omitempty"`
+
+	// Environments (optional) contains the fields overridden for each environment, the key is the name of the
+	// environment. The environment configured in the client selects the override.
+	Environments *map[string]Environment `json:"environments,omitempty" yaml:"environments,omitempty" toml:"environments,omitempty"` // nolint: lll
 }
 
 // Value is returning the Value associate to the flag
@@ -62,22 +111,41 @@ func (f *InternalFlag) Value(
 	evaluationCtx ffcontext.Context,
 	flagContext Context,
 ) (interface{}, ResolutionDetails) {
-	f.applyScheduledRolloutSteps()
+	evaluationDate := flagContext.GetEvaluationDate()
+	f = f.withScheduledRolloutSteps(evaluationDate)
 
 	if flagContext.EvaluationContextEnrichment != nil {
 		maps.Copy(evaluationCtx.GetCustom(), flagContext.EvaluationContextEnrichment)
 	}
+	f = f.forEnvironment(flagContext.GetEnvironment())
 
-	if f.IsDisable() || f.isExperimentationOver() {
-		return flagContext.DefaultSdkValue, ResolutionDetails{
-			Variant:   VariationSDKDefault,
-			Reason:    ReasonDisabled,
-			Cacheable: f.isCacheable(),
-			Metadata:  f.GetMetadata(),
-		}
+	if f.IsDisable() || f.IsArchived() || flagContext.Disabled || f.isExperimentationOver(evaluationDate) {
+		return f.sdkDefaultValue(flagContext, ReasonDisabled, f.isCacheable())
+	}
+
+	if f.isExpired(evaluationDate) {
+		return f.sdkDefaultValue(flagContext, ReasonExpired, f.isCacheable())
 	}
 
-	variationSelection, err := f.selectVariation(flagName, evaluationCtx)
+	if f.isInHoldout(flagName, evaluationCtx, flagContext) {
+		return f.holdoutValue(flagContext)
+	}
+
+	if !f.arePrerequisitesMet(evaluationCtx, flagContext) {
+		return f.sdkDefaultValue(flagContext, ReasonPrerequisiteFailed, false)
+	}
+
+	if !f.isInLayer(evaluationCtx, flagContext) {
+		return f.sdkDefaultValue(flagContext, ReasonLayerExcluded, f.isCacheable())
+	}
+
+	variationSelection, err := f.selectVariation(flagName, evaluationCtx, flagContext, evaluationDate)
+	// a rule referencing an unknown variation serves the SDK default value.
+	if err == nil {
+		if _, ok := f.GetVariations()[variationSelection.name]; !ok {
+			err = fmt.Errorf("variation %s does not exist", variationSelection.name)
+		}
+	}
 	if err != nil {
 		return flagContext.DefaultSdkValue,
 			ResolutionDetails{
@@ -95,6 +163,20 @@ func (f *InternalFlag) Value(
 		RuleName:  variationSelection.ruleName,
 		Cacheable: variationSelection.cacheable,
 		Metadata:  f.GetMetadata(),
+
+		ProgressiveRollout: variationSelection.progressiveRollout,
+		VariationMetadata:  f.GetVariationMetadata(variationSelection.name),
+	}
+}
+
+// sdkDefaultValue returns the SDK default value when the flag is not served for this reason.
+func (f *InternalFlag) sdkDefaultValue(flagContext Context, reason ResolutionReason, cacheable bool,
+) (interface{}, ResolutionDetails) {
+	return flagContext.DefaultSdkValue, ResolutionDetails{
+		Variant:   VariationSDKDefault,
+		Reason:    reason,
+		Cacheable: cacheable,
+		Metadata:  f.GetMetadata(),
 	}
 }
 
@@ -119,20 +201,40 @@ func selectEvaluationReason(hasRule bool, targetingMatch bool, isDynamic bool, i
 	return ReasonUnknown
 }
 
+// isExpired checks if the flag has reached its expiration date.
+func (f *InternalFlag) isExpired(evaluationDate time.Time) bool {
+	return f.ExpirationDate != nil && !evaluationDate.Before(*f.ExpirationDate)
+}
+
 func (f *InternalFlag) isCacheable() bool {
-	isDynamic := (f.Scheduled != nil && len(*f.Scheduled) > 0) || f.Experimentation != nil
-	return !isDynamic
+	// the result of a flag with prerequisites depends on the evaluation of other flags.
+	isDynamic := (f.Scheduled != nil && len(*f.Scheduled) > 0) || f.Experimentation != nil ||
+		len(f.GetPrerequisites()) > 0 || f.ExpirationDate != nil || f.SeedRotation != nil
+	return !isDynamic && !f.hasRuleUsingEvaluationDate()
+}
+
+// hasRuleUsingEvaluationDate checks if a rule of the flag compares a date with now,
+// the result of those rules changes over time.
+func (f *InternalFlag) hasRuleUsingEvaluationDate() bool {
+	for _, rule := range f.GetRules() {
+		if usesEvaluationDate(rule.GetQuery()) {
+			return true
+		}
+	}
+	return false
 }
 
 // selectVariation is doing the magic to select the variation that should be used for this specific user
 // to always affect the user to the same segment we are using a hash of the flag name + key
-func (f *InternalFlag) selectVariation(flagName string, ctx ffcontext.Context) (*variationSelection, error) {
-	hashID := utils.Hash(flagName+ctx.GetKey()) % MaxPercentage
+func (f *InternalFlag) selectVariation(flagName string, ctx ffcontext.Context, flagContext Context,
+	evaluationDate time.Time,
+) (*variationSelection, error) {
+	hashID := flagContext.hash(f.bucketingKey(flagName, ctx, evaluationDate)) % MaxPercentage
 	hasRule := len(f.GetRules()) != 0
 	// Check all targeting in order, the first to match will be the one used.
 	if hasRule {
 		for ruleIndex, target := range f.GetRules() {
-			variationName, err := target.Evaluate(ctx, hashID, false)
+			variationName, err := target.evaluate(ctx, hashID, false, evaluationDate)
 			if err != nil {
 				// the targeting does not apply
 				if _, ok := err.(*internalerror.RuleNotApply); ok {
@@ -142,11 +244,12 @@ func (f *InternalFlag) selectVariation(flagName string, ctx ffcontext.Context) (
 			}
 			reason := selectEvaluationReason(hasRule, true, target.IsDynamic(), false)
 			return &variationSelection{
-				name:      variationName,
-				reason:    reason,
-				ruleIndex: &ruleIndex,
-				ruleName:  f.GetRules()[ruleIndex].Name,
-				cacheable: f.isCacheable() && target.ProgressiveRollout == nil,
+				name:               variationName,
+				reason:             reason,
+				ruleIndex:          &ruleIndex,
+				ruleName:           f.GetRules()[ruleIndex].Name,
+				cacheable:          f.isCacheable() && target.ProgressiveRollout == nil,
+				progressiveRollout: target.progressiveRolloutDetails(hashID, evaluationDate),
 			}, err
 		}
 	}
@@ -155,69 +258,132 @@ func (f *InternalFlag) selectVariation(flagName string, ctx ffcontext.Context) (
 		return nil, fmt.Errorf("no default targeting for the flag")
 	}
 
-	variationName, err := f.GetDefaultRule().Evaluate(ctx, hashID, true)
+	variationName, err := f.GetDefaultRule().evaluate(ctx, hashID, true, evaluationDate)
 	if err != nil {
 		return nil, err
 	}
 
 	reason := selectEvaluationReason(hasRule, false, f.GetDefaultRule().IsDynamic(), true)
 	return &variationSelection{
-		name:      variationName,
-		reason:    reason,
-		cacheable: f.isCacheable() && f.GetDefaultRule().ProgressiveRollout == nil,
+		name:               variationName,
+		reason:             reason,
+		cacheable:          f.isCacheable() && f.GetDefaultRule().ProgressiveRollout == nil,
+		progressiveRollout: f.GetDefaultRule().progressiveRolloutDetails(hashID, evaluationDate),
 	}, nil
 }
 
+// bucketingKey returns the key used to compute the bucket of the user.
+// If a seed rotation is configured, the current period is added to the key to re-assign
+// the users to new buckets at each period.
+// If an anonymous bucketing salt is configured, it is added to the key of the anonymous users.
+// If the context has a secondary key, it is added to the key after a delimiter.
+// If a bucketing salt is configured, it replaces the flag name in the key.
+func (f *InternalFlag) bucketingKey(flagName string, ctx ffcontext.Context, evaluationDate time.Time) string {
+	prefix := flagName
+	if salt := f.GetBucketingSalt(); salt != "" {
+		prefix = salt
+	}
+	key := prefix + ctx.GetKey()
+	if secondary := ffcontext.GetSecondaryKey(ctx); secondary != "" {
+		// the delimiter avoids the collisions between the key "ab" with the secondary key "c"
+		// and the key "a" with the secondary key "bc".
+		key += secondaryKeyDelimiter + secondary
+	}
+	if salt := f.GetAnonymousBucketingSalt(); salt != "" && ctx.IsAnonymous() {
+		key += salt
+	}
+	if rotation := f.GetSeedRotation(); rotation > 0 {
+		key += strconv.FormatInt(evaluationDate.UnixNano()/int64(rotation), 10)
+	}
+	return key
+}
+
+// withScheduledRolloutSteps returns the flag as configured at the evaluation date.
+// The scheduled steps are applied on a copy, the flag itself is never modified so it can be
+// evaluated at any date (ex: a date in the past with WithEvaluationTime).
+func (f *InternalFlag) withScheduledRolloutSteps(evaluationDate time.Time) *InternalFlag {
+	if f.Scheduled == nil {
+		return f
+	}
+	hasApplicableStep := false
+	for _, step := range *f.Scheduled {
+		if step.Date != nil && !step.Date.After(evaluationDate) {
+			hasApplicableStep = true
+			break
+		}
+	}
+	if !hasApplicableStep {
+		return f
+	}
+
+	flagCopy, err := copystructure.Copy(f)
+	if err != nil {
+		return f
+	}
+	scheduledFlag := flagCopy.(*InternalFlag)
+	scheduledFlag.applyScheduledRolloutSteps(evaluationDate)
+	return scheduledFlag
+}
+
 // nolint: gocognit
 // applyScheduledRolloutSteps is checking if the flag has a scheduled rollout configured.
-// If yes we merge the changes to the current flag.
-func (f *InternalFlag) applyScheduledRolloutSteps() {
-	evaluationDate := time.Now()
-	if f.Scheduled != nil {
-		for _, steps := range *f.Scheduled {
-			if steps.Date != nil && steps.Date.Before(evaluationDate) {
-				f.Rules = MergeSetOfRules(f.GetRules(), steps.GetRules())
-				if steps.Disable != nil {
-					f.Disable = steps.Disable
-				}
+// If yes, the steps with a date at or before the evaluation date are merged in the flag
+// in chronological order, so the latest applicable step wins when several steps update the same field.
+func (f *InternalFlag) applyScheduledRolloutSteps(evaluationDate time.Time) {
+	if f.Scheduled == nil {
+		return
+	}
 
-				if steps.TrackEvents != nil {
-					f.TrackEvents = steps.TrackEvents
-				}
+	steps := make([]ScheduledStep, 0, len(*f.Scheduled))
+	for _, step := range *f.Scheduled {
+		if step.Date != nil && !step.Date.After(evaluationDate) {
+			steps = append(steps, step)
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].Date.Before(*steps[j].Date)
+	})
+
+	for _, step := range steps {
+		f.Rules = MergeSetOfRules(f.GetRules(), step.GetRules())
+		if step.Disable != nil {
+			f.Disable = step.Disable
+		}
 
-				if steps.DefaultRule != nil {
-					f.DefaultRule.MergeRules(*steps.DefaultRule)
-				}
+		if step.TrackEvents != nil {
+			f.TrackEvents = step.TrackEvents
+		}
 
-				if steps.Variations != nil {
-					for key, value := range steps.GetVariations() {
-						f.GetVariations()[key] = value
-					}
-				}
+		if step.DefaultRule != nil {
+			f.DefaultRule.MergeRules(*step.DefaultRule)
+		}
 
-				if steps.Version != nil {
-					f.Version = steps.Version
-				}
+		if step.Variations != nil {
+			for key, value := range step.GetVariations() {
+				f.GetVariations()[key] = value
+			}
+		}
 
-				if steps.Experimentation != nil {
-					if f.Experimentation == nil {
-						f.Experimentation = &ExperimentationRollout{}
-					}
-					if steps.Experimentation.Start != nil {
-						f.Experimentation.End = steps.Experimentation.End
-					}
-					if steps.Experimentation.End != nil {
-						f.Experimentation.End = steps.Experimentation.End
-					}
-				}
+		if step.Version != nil {
+			f.Version = step.Version
+		}
+
+		if step.Experimentation != nil {
+			if f.Experimentation == nil {
+				f.Experimentation = &ExperimentationRollout{}
+			}
+			if step.Experimentation.Start != nil {
+				f.Experimentation.Start = step.Experimentation.Start
+			}
+			if step.Experimentation.End != nil {
+				f.Experimentation.End = step.Experimentation.End
 			}
 		}
 	}
 }
 
-// isExperimentationOver checks if we are in an experimentation or not
-func (f *InternalFlag) isExperimentationOver() bool {
-	now := time.Now()
+// isExperimentationOver checks if we are in an experimentation or not at the evaluation date.
+func (f *InternalFlag) isExperimentationOver(now time.Time) bool {
 	return f.Experimentation != nil &&
 		((f.Experimentation.Start != nil && now.Before(*f.Experimentation.Start)) ||
 			(f.Experimentation.End != nil && now.After(*f.Experimentation.End)))
@@ -249,6 +415,35 @@ func (f *InternalFlag) IsValid() error {
 		}
 	}
 
+	if f.SeedRotation != nil {
+		if rotation, err := time.ParseDuration(*f.SeedRotation); err != nil || rotation <= 0 {
+			return fmt.Errorf("invalid seedRotation: %s", *f.SeedRotation)
+		}
+	}
+
+	for index, prerequisite := range f.GetPrerequisites() {
+		if prerequisite.Flag == "" || prerequisite.Variation == "" {
+			return fmt.Errorf("invalid prerequisite %d: flag and variation are mandatory", index)
+		}
+	}
+
+	if f.Layer != nil {
+		if err := f.Layer.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	if f.Holdout != nil {
+		if err := f.Holdout.IsValid(); err != nil {
+			return err
+		}
+		if variation := f.Holdout.GetVariation(); variation != "" {
+			if _, ok := f.GetVariations()[variation]; !ok {
+				return fmt.Errorf("invalid holdout: variation %s does not exist", variation)
+			}
+		}
+	}
+
 	// Validate that we have a default Rule
 	if f.GetDefaultRule() == nil {
 		return fmt.Errorf("missing default rule")
@@ -260,6 +455,15 @@ func (f *InternalFlag) IsValid() error {
 		return err
 	}
 
+	for name, environment := range f.GetEnvironments() {
+		if environment.DefaultRule == nil {
+			continue
+		}
+		if err := environment.DefaultRule.IsValid(isDefaultRule); err != nil {
+			return fmt.Errorf("invalid default rule for the environment %s: %w", name, err)
+		}
+	}
+
 	ruleNames := map[string]interface{}{}
 	for _, rule := range f.GetRules() {
 		if err := rule.IsValid(!isDefaultRule); err != nil {
@@ -322,6 +526,14 @@ func (f *InternalFlag) IsDisable() bool {
 	return *f.Disable
 }
 
+// IsArchived is the getter for the field Archived
+func (f *InternalFlag) IsArchived() bool {
+	if f.Archived == nil {
+		return false
+	}
+	return *f.Archived
+}
+
 // GetVersion is the getter for the field Version
 func (f *InternalFlag) GetVersion() string {
 	if f.Version == nil {
@@ -347,3 +559,75 @@ func (f *InternalFlag) GetMetadata() map[string]interface{} {
 	}
 	return *f.Metadata
 }
+
+// GetVariationMetadata return the metadata associated to a variation of the flag
+func (f *InternalFlag) GetVariationMetadata(name string) map[string]interface{} {
+	if f.VariationMetadata == nil {
+		return nil
+	}
+	return (*f.VariationMetadata)[name]
+}
+
+// ParseSeedRotation parses the field SeedRotation, it is called once when the flag is loaded
+// so the duration is not parsed at each evaluation.
+func (f *InternalFlag) ParseSeedRotation() {
+	f.ParsedSeedRotation = parseSeedRotation(f.SeedRotation)
+}
+
+// GetSeedRotation is the getter for the field SeedRotation, it returns 0 if no valid rotation is configured.
+func (f *InternalFlag) GetSeedRotation() time.Duration {
+	if f.ParsedSeedRotation > 0 {
+		return f.ParsedSeedRotation
+	}
+	// the flag has not been loaded from a configuration (ex: a flag created in the code).
+	return parseSeedRotation(f.SeedRotation)
+}
+
+// parseSeedRotation returns the duration of the seed rotation, it returns 0 if the rotation is not valid.
+func parseSeedRotation(seedRotation *string) time.Duration {
+	if seedRotation == nil {
+		return 0
+	}
+	rotation, err := time.ParseDuration(*seedRotation)
+	if err != nil || rotation < 0 {
+		return 0
+	}
+	return rotation
+}
+
+// GetAnonymousBucketingSalt is the getter for the field AnonymousBucketingSalt
+func (f *InternalFlag) GetAnonymousBucketingSalt() string {
+	if f.AnonymousBucketingSalt == nil {
+		return ""
+	}
+	return *f.AnonymousBucketingSalt
+}
+
+// GetEnvironments is the getter for the field Environments
+func (f *InternalFlag) GetEnvironments() map[string]Environment {
+	if f.Environments == nil {
+		return map[string]Environment{}
+	}
+	return *f.Environments
+}
+
+// GetBucketingSalt is the getter for the field BucketingSalt
+func (f *InternalFlag) GetBucketingSalt() string {
+	if f.BucketingSalt == nil {
+		return ""
+	}
+	return *f.BucketingSalt
+}
+
+// GetExpirationDate is the getter for the field ExpirationDate
+func (f *InternalFlag) GetExpirationDate() *time.Time {
+	return f.ExpirationDate
+}
+
+// GetPrerequisites is the getter for the field Prerequisites
+func (f *InternalFlag) GetPrerequisites() []Prerequisite {
+	if f.Prerequisites == nil {
+		return []Prerequisite{}
+	}
+	return *f.Prerequisites
+}
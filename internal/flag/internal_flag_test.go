@@ -8,8 +8,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/internal/dto"
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
 	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestInternalFlag_Value(t *testing.T) {
@@ -1116,6 +1118,10 @@ func TestInternalFlag_Value(t *testing.T) {
 				Variant:   "variation_A",
 				Reason:    flag.ReasonSplit,
 				Cacheable: false,
+				ProgressiveRollout: &flag.ProgressiveRolloutDetails{
+					Phase:      flag.RolloutPhaseInitial,
+					Percentage: 5,
+				},
 				Metadata: map[string]interface{}{
 					"description": "this is a flag",
 					"issue-link":  "https://issue.link/GOFF-1",
@@ -1225,12 +1231,12 @@ func TestInternalFlag_Value(t *testing.T) {
 							Initial: &flag.ProgressiveRolloutStep{
 								Variation:  testconvert.String("variation_A"),
 								Percentage: testconvert.Float64(0),
-								Date:       testconvert.Time(time.Now()),
+								Date:       testconvert.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
 							},
 							End: &flag.ProgressiveRolloutStep{
 								Variation:  testconvert.String("variation_B"),
 								Percentage: testconvert.Float64(100),
-								Date:       testconvert.Time(time.Now().Add(1 * time.Minute)),
+								Date:       testconvert.Time(time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC)),
 							},
 						},
 					},
@@ -1248,6 +1254,7 @@ func TestInternalFlag_Value(t *testing.T) {
 				user:     ffcontext.NewEvaluationContextBuilder("user-key").Build(),
 				flagContext: flag.Context{
 					DefaultSdkValue: "value_default",
+					EvaluationDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				},
 			},
 			want: "value_A",
@@ -1257,6 +1264,10 @@ func TestInternalFlag_Value(t *testing.T) {
 				RuleIndex: testconvert.Int(0),
 				RuleName:  testconvert.String("test-rule"),
 				Cacheable: false,
+				ProgressiveRollout: &flag.ProgressiveRolloutDetails{
+					Phase:      flag.RolloutPhaseInitial,
+					Percentage: 0,
+				},
 				Metadata: map[string]interface{}{
 					"description": "this is a flag",
 					"issue-link":  "https://issue.link/GOFF-1",
@@ -1581,6 +1592,311 @@ func TestFlag_ProgressiveRollout(t *testing.T) {
 	assert.Equal(t, f.GetVariationValue("variation_B"), v3)
 }
 
+func TestFlag_ProgressiveRolloutDetails(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"variation_A": testconvert.Interface("value_A"),
+			"variation_B": testconvert.Interface("value_B"),
+		},
+		DefaultRule: &flag.Rule{
+			ProgressiveRollout: &flag.ProgressiveRollout{
+				Initial: &flag.ProgressiveRolloutStep{
+					Variation:  testconvert.String("variation_A"),
+					Percentage: testconvert.Float64(0),
+					Date:       testconvert.Time(start),
+				},
+				End: &flag.ProgressiveRolloutStep{
+					Variation:  testconvert.String("variation_B"),
+					Percentage: testconvert.Float64(80),
+					Date:       testconvert.Time(start.Add(10 * 24 * time.Hour)),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		evaluationDate time.Time
+		wantPercentage float64
+	}{
+		{
+			name:           "before the ramp",
+			evaluationDate: start.Add(-time.Hour),
+			wantPercentage: 0,
+		},
+		{
+			name:           "middle of the ramp",
+			evaluationDate: start.Add(5 * 24 * time.Hour),
+			wantPercentage: 40,
+		},
+		{
+			name:           "after the ramp",
+			evaluationDate: start.Add(20 * 24 * time.Hour),
+			wantPercentage: 80,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nbEnd := 0
+			for i := 0; i < 1000; i++ {
+				user := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+				_, details := f.Value("test-flag", user, flag.Context{EvaluationDate: tt.evaluationDate})
+				if !assert.NotNil(t, details.ProgressiveRollout) {
+					return
+				}
+				assert.False(t, details.Cacheable)
+				assert.InDelta(t, tt.wantPercentage, details.ProgressiveRollout.Percentage, 0.001)
+
+				// the phase is consistent with the variation served
+				wantPhase := flag.RolloutPhaseInitial
+				if details.Variant == "variation_B" {
+					wantPhase = flag.RolloutPhaseEnd
+					nbEnd++
+				}
+				assert.Equal(t, wantPhase, details.ProgressiveRollout.Phase)
+			}
+			assert.InDelta(t, tt.wantPercentage, float64(nbEnd)/10, 5)
+		})
+	}
+
+	// no progressive rollout details for a rule without progressive rollout
+	static := &flag.InternalFlag{
+		Variations:  &map[string]*interface{}{"variation_A": testconvert.Interface("value_A")},
+		DefaultRule: &flag.Rule{VariationResult: testconvert.String("variation_A")},
+	}
+	_, details := static.Value("test-flag", ffcontext.NewEvaluationContext("user-key"), flag.Context{})
+	assert.Nil(t, details.ProgressiveRollout)
+}
+
+func TestFlag_SeedRotation(t *testing.T) {
+	f := &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"variation_A": testconvert.Interface("value_A"),
+			"variation_B": testconvert.Interface("value_B"),
+		},
+		DefaultRule: &flag.Rule{
+			Percentages: &map[string]float64{
+				"variation_A": 50,
+				"variation_B": 50,
+			},
+		},
+		SeedRotation: testconvert.String("168h"),
+	}
+	assert.NoError(t, f.IsValid())
+
+	evaluate := func(date time.Time) []interface{} {
+		values := make([]interface{}, 0)
+		for i := 0; i < 100; i++ {
+			user := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+			v, _ := f.Value("test-flag", user, flag.Context{EvaluationDate: date})
+			values = append(values, v)
+		}
+		return values
+	}
+
+	periodStart := time.Unix(0, 0).Add(2800 * 168 * time.Hour)
+	firstPeriod := evaluate(periodStart.Add(1 * time.Hour))
+	assert.Equal(t, firstPeriod, evaluate(periodStart.Add(100*time.Hour)), "assignment should be stable within a period")
+	assert.NotEqual(t, firstPeriod, evaluate(periodStart.Add(169*time.Hour)), "assignment should change in a new period")
+
+	// the assignment changes over time, so the result can't be cached
+	f.ParseSeedRotation()
+	assert.Equal(t, 168*time.Hour, f.GetSeedRotation())
+	_, details := f.Value("test-flag", ffcontext.NewEvaluationContext("user-key"), flag.Context{})
+	assert.False(t, details.Cacheable)
+}
+
+func TestFlag_AnonymousBucketingSalt(t *testing.T) {
+	newFlag := func(salt *string) *flag.InternalFlag {
+		return &flag.InternalFlag{
+			Variations: &map[string]*interface{}{
+				"variation_A": testconvert.Interface("value_A"),
+				"variation_B": testconvert.Interface("value_B"),
+			},
+			DefaultRule: &flag.Rule{
+				Percentages: &map[string]float64{
+					"variation_A": 50,
+					"variation_B": 50,
+				},
+			},
+			AnonymousBucketingSalt: salt,
+		}
+	}
+
+	evaluate := func(f *flag.InternalFlag, anonymous bool) []interface{} {
+		values := make([]interface{}, 0)
+		for i := 0; i < 100; i++ {
+			user := ffcontext.NewEvaluationContextBuilder(fmt.Sprintf("user-%d", i)).
+				AddCustom("anonymous", anonymous).Build()
+			v, _ := f.Value("test-flag", user, flag.Context{})
+			values = append(values, v)
+		}
+		return values
+	}
+
+	withoutSalt := newFlag(nil)
+	withSalt := newFlag(testconvert.String("pre-login"))
+	assert.Equal(t, evaluate(withoutSalt, false), evaluate(withoutSalt, true),
+		"without salt anonymous and identified users share the same bucket")
+	assert.Equal(t, evaluate(withoutSalt, false), evaluate(withSalt, false),
+		"identified users are not affected by the salt")
+	assert.NotEqual(t, evaluate(withoutSalt, true), evaluate(withSalt, true),
+		"anonymous users should be bucketed with the salt")
+}
+
+func TestFlag_BucketingSalt(t *testing.T) {
+	newFlag := func(salt *string) *flag.InternalFlag {
+		return &flag.InternalFlag{
+			Variations: &map[string]*interface{}{
+				"variation_A": testconvert.Interface("value_A"),
+				"variation_B": testconvert.Interface("value_B"),
+			},
+			DefaultRule: &flag.Rule{
+				Percentages: &map[string]float64{
+					"variation_A": 50,
+					"variation_B": 50,
+				},
+			},
+			BucketingSalt: salt,
+		}
+	}
+
+	evaluate := func(flagName string, f *flag.InternalFlag) []interface{} {
+		values := make([]interface{}, 0)
+		for i := 0; i < 100; i++ {
+			v, _ := f.Value(flagName, ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i)), flag.Context{})
+			values = append(values, v)
+		}
+		return values
+	}
+
+	assert.Equal(t,
+		evaluate("checkout-flow", newFlag(testconvert.String("checkout-experiment"))),
+		evaluate("checkout-banner", newFlag(testconvert.String("checkout-experiment"))),
+		"flags sharing the same salt should assign the same users to the same buckets")
+	assert.NotEqual(t,
+		evaluate("checkout-flow", newFlag(testconvert.String("checkout-experiment"))),
+		evaluate("checkout-banner", newFlag(testconvert.String("banner-experiment"))),
+		"flags with different salts should not be correlated")
+	assert.NotEqual(t,
+		evaluate("checkout-flow", newFlag(nil)),
+		evaluate("checkout-banner", newFlag(nil)),
+		"without salt the flags should not be correlated")
+}
+
+func TestFlag_SecondaryKey(t *testing.T) {
+	f := &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"variation_A": testconvert.Interface("value_A"),
+			"variation_B": testconvert.Interface("value_B"),
+		},
+		DefaultRule: &flag.Rule{
+			Percentages: &map[string]float64{
+				"variation_A": 50,
+				"variation_B": 50,
+			},
+		},
+	}
+
+	evaluate := func(buildContext func(key string) ffcontext.Context) []interface{} {
+		values := make([]interface{}, 0)
+		for i := 0; i < 100; i++ {
+			v, _ := f.Value("test-flag", buildContext(fmt.Sprintf("user-%d", i)), flag.Context{})
+			values = append(values, v)
+		}
+		return values
+	}
+
+	withoutSecondary := evaluate(func(key string) ffcontext.Context {
+		return ffcontext.NewEvaluationContext(key)
+	})
+	assert.Equal(t, withoutSecondary, evaluate(func(key string) ffcontext.Context {
+		return ffcontext.NewEvaluationContextBuilder(key).AddCustom("email", "john.doe@gofeatureflag.org").Build()
+	}), "without secondary key the buckets should not change")
+	assert.NotEqual(t, withoutSecondary, evaluate(func(key string) ffcontext.Context {
+		return ffcontext.NewEvaluationContextBuilder(key).Secondary("checkout").Build()
+	}), "the secondary key should be mixed in the bucketing")
+	assert.NotEqual(t, evaluate(func(key string) ffcontext.Context {
+		return ffcontext.NewEvaluationContextBuilder(key + "-a").Secondary("checkout").Build()
+	}), evaluate(func(key string) ffcontext.Context {
+		return ffcontext.NewEvaluationContextBuilder(key).Secondary("-acheckout").Build()
+	}), "the key and the secondary key should be delimited in the bucketing")
+}
+
+func TestFlag_ExpirationDate(t *testing.T) {
+	expirationDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		expirationDate *time.Time
+		evaluationDate time.Time
+		want           interface{}
+		want1          flag.ResolutionDetails
+	}{
+		{
+			name:           "before expiration date",
+			expirationDate: &expirationDate,
+			evaluationDate: expirationDate.Add(-1 * time.Minute),
+			want:           "value_B",
+			want1: flag.ResolutionDetails{
+				Variant:   "variation_B",
+				Reason:    flag.ReasonTargetingMatch,
+				RuleIndex: testconvert.Int(0),
+				// the result changes at the expiration date, so it can't be cached.
+				Cacheable: false,
+			},
+		},
+		{
+			name:           "after expiration date",
+			expirationDate: &expirationDate,
+			evaluationDate: expirationDate.Add(1 * time.Minute),
+			want:           "default-sdk",
+			want1: flag.ResolutionDetails{
+				Variant:   flag.VariationSDKDefault,
+				Reason:    flag.ReasonExpired,
+				Cacheable: false,
+			},
+		},
+		{
+			name:           "no expiration date",
+			evaluationDate: expirationDate.Add(1 * time.Minute),
+			want:           "value_B",
+			want1: flag.ResolutionDetails{
+				Variant:   "variation_B",
+				Reason:    flag.ReasonTargetingMatch,
+				RuleIndex: testconvert.Int(0),
+				Cacheable: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flag.InternalFlag{
+				Variations: &map[string]*interface{}{
+					"variation_A": testconvert.Interface("value_A"),
+					"variation_B": testconvert.Interface("value_B"),
+				},
+				Rules: &[]flag.Rule{
+					{
+						Query:           testconvert.String("key eq \"user-key\""),
+						VariationResult: testconvert.String("variation_B"),
+					},
+				},
+				DefaultRule: &flag.Rule{
+					VariationResult: testconvert.String("variation_A"),
+				},
+				ExpirationDate: tt.expirationDate,
+			}
+			got, got1 := f.Value("test-flag", ffcontext.NewEvaluationContext("user-key"), flag.Context{
+				DefaultSdkValue: "default-sdk",
+				EvaluationDate:  tt.evaluationDate,
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+		})
+	}
+}
+
 func TestInternalFlag_GetVariations(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1726,6 +2042,7 @@ func TestInternalFlag_IsValid(t *testing.T) {
 		Experimentation *flag.ExperimentationRollout
 		Scheduled       *[]flag.ScheduledStep
 		Metadata        *map[string]interface{}
+		SeedRotation    *string
 	}
 	tests := []struct {
 		name     string
@@ -1865,7 +2182,7 @@ func TestInternalFlag_IsValid(t *testing.T) {
 					"issue-link":  "https://issue.link/GOFF-1",
 				},
 			},
-			errorMsg: "invalid percentages",
+			errorMsg: "invalid percentages, percentages should sum to 100, got 110",
 			wantErr:  assert.Error,
 		},
 		{
@@ -1895,9 +2212,63 @@ func TestInternalFlag_IsValid(t *testing.T) {
 					},
 				},
 			},
-			errorMsg: "invalid percentages",
+			errorMsg: "invalid percentages, percentages should sum to 100, got 110",
+			wantErr:  assert.Error,
+		},
+		{
+			name: "three-way split not summing to 100",
+			fields: fields{
+				Variations: &map[string]*interface{}{
+					"A": testconvert.Interface("A"),
+					"B": testconvert.Interface("B"),
+					"C": testconvert.Interface("C"),
+				},
+				DefaultRule: &flag.Rule{
+					Percentages: &map[string]float64{
+						"A": 25,
+						"B": 25,
+						"C": 40,
+					},
+				},
+			},
+			errorMsg: "invalid percentages, percentages should sum to 100, got 90",
 			wantErr:  assert.Error,
 		},
+		{
+			name: "negative percentage",
+			fields: fields{
+				Variations: &map[string]*interface{}{
+					"A": testconvert.Interface("A"),
+					"B": testconvert.Interface("B"),
+				},
+				DefaultRule: &flag.Rule{
+					Percentages: &map[string]float64{
+						"A": 110,
+						"B": -10,
+					},
+				},
+			},
+			errorMsg: "invalid percentages, percentage of variation B should be positive, got -10",
+			wantErr:  assert.Error,
+		},
+		{
+			name: "valid three-way split",
+			fields: fields{
+				Variations: &map[string]*interface{}{
+					"A": testconvert.Interface("A"),
+					"B": testconvert.Interface("B"),
+					"C": testconvert.Interface("C"),
+				},
+				DefaultRule: &flag.Rule{
+					Percentages: &map[string]float64{
+						"A": 25,
+						"B": 25,
+						"C": 50,
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
 		{
 			name: "targeting without query",
 			fields: fields{
@@ -2005,6 +2376,34 @@ func TestInternalFlag_IsValid(t *testing.T) {
 			errorMsg: "",
 			wantErr:  assert.NoError,
 		},
+		{
+			name: "invalid seedRotation",
+			fields: fields{
+				Variations: &map[string]*interface{}{
+					"A": testconvert.Interface("A"),
+				},
+				DefaultRule: &flag.Rule{
+					VariationResult: testconvert.String("A"),
+				},
+				SeedRotation: testconvert.String("every week"),
+			},
+			errorMsg: "invalid seedRotation: every week",
+			wantErr:  assert.Error,
+		},
+		{
+			name: "negative seedRotation",
+			fields: fields{
+				Variations: &map[string]*interface{}{
+					"A": testconvert.Interface("A"),
+				},
+				DefaultRule: &flag.Rule{
+					VariationResult: testconvert.String("A"),
+				},
+				SeedRotation: testconvert.String("-1h"),
+			},
+			errorMsg: "invalid seedRotation: -1h",
+			wantErr:  assert.Error,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2018,6 +2417,7 @@ func TestInternalFlag_IsValid(t *testing.T) {
 				Version:         tt.fields.Version,
 				Scheduled:       tt.fields.Scheduled,
 				Experimentation: tt.fields.Experimentation,
+				SeedRotation:    tt.fields.SeedRotation,
 			}
 			err := f.IsValid()
 			errMsg := ""
@@ -2029,3 +2429,225 @@ func TestInternalFlag_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestInternalFlag_WeightedSplitDistribution(t *testing.T) {
+	f := flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"A": testconvert.Interface("A"),
+			"B": testconvert.Interface("B"),
+			"C": testconvert.Interface("C"),
+		},
+		DefaultRule: &flag.Rule{
+			Percentages: &map[string]float64{
+				"A": 25,
+				"B": 25,
+				"C": 50,
+			},
+		},
+	}
+	assert.NoError(t, f.IsValid())
+
+	const nbUsers = 10000
+	distribution := map[string]int{}
+	for i := 0; i < nbUsers; i++ {
+		ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+		value, details := f.Value("weighted-flag", ctx, flag.Context{})
+		assert.Equal(t, flag.ReasonSplit, details.Reason)
+		assert.Equal(t, details.Variant, value)
+
+		// the same user is always assigned to the same variation
+		sameValue, _ := f.Value("weighted-flag", ctx, flag.Context{})
+		assert.Equal(t, value, sameValue)
+		distribution[details.Variant]++
+	}
+
+	assert.InDelta(t, 0.25, float64(distribution["A"])/nbUsers, 0.02)
+	assert.InDelta(t, 0.25, float64(distribution["B"])/nbUsers, 0.02)
+	assert.InDelta(t, 0.50, float64(distribution["C"])/nbUsers, 0.02)
+}
+
+func TestInternalFlag_SplitStableWhenVariationsAreReordered(t *testing.T) {
+	flagFiles := []string{`
+variations:
+  A: "A"
+  B: "B"
+  C: "C"
+defaultRule:
+  percentage:
+    A: 20
+    B: 30
+    C: 50
+`, `
+variations:
+  C: "C"
+  A: "A"
+  B: "B"
+defaultRule:
+  percentage:
+    B: 30
+    C: 50
+    A: 20
+`}
+
+	const nbUsers = 1000
+	var assignments []map[string]string
+	for _, flagFile := range flagFiles {
+		var d dto.DTO
+		assert.NoError(t, yaml.Unmarshal([]byte(flagFile), &d))
+		f := d.Convert()
+		assert.NoError(t, f.IsValid())
+
+		assignment := make(map[string]string, nbUsers)
+		for i := 0; i < nbUsers; i++ {
+			ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+			_, details := f.Value("reordered-flag", ctx, flag.Context{})
+			assert.Equal(t, flag.ReasonSplit, details.Reason)
+			assignment[ctx.GetKey()] = details.Variant
+		}
+		assignments = append(assignments, assignment)
+	}
+	// the buckets are computed from the variation names, the order in the file does not move any user
+	assert.Equal(t, assignments[0], assignments[1])
+}
+
+func TestInternalFlag_DefaultRuleSplitWithTargeting(t *testing.T) {
+	newFlag := func() flag.InternalFlag {
+		return flag.InternalFlag{
+			Variations: &map[string]*interface{}{
+				"A": testconvert.Interface("A"),
+				"B": testconvert.Interface("B"),
+			},
+			Rules: &[]flag.Rule{
+				{
+					Query:           testconvert.String(`beta eq true`),
+					VariationResult: testconvert.String("A"),
+				},
+			},
+			DefaultRule: &flag.Rule{
+				Percentages: &map[string]float64{
+					"A": 50,
+					"B": 50,
+				},
+			},
+		}
+	}
+	firstFlag, secondFlag := newFlag(), newFlag()
+	assert.NoError(t, firstFlag.IsValid())
+
+	// a user matching the targeting rule is not part of the split
+	betaUser := ffcontext.NewEvaluationContextBuilder("beta-user").AddCustom("beta", true).Build()
+	_, details := firstFlag.Value("first-flag", betaUser, flag.Context{})
+	assert.Equal(t, flag.ReasonTargetingMatch, details.Reason)
+
+	const nbUsers = 10000
+	distribution := map[string]int{}
+	sameVariation := 0
+	for i := 0; i < nbUsers; i++ {
+		ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+		firstValue, firstDetails := firstFlag.Value("first-flag", ctx, flag.Context{})
+		assert.Equal(t, flag.ReasonSplit, firstDetails.Reason)
+		distribution[firstDetails.Variant]++
+
+		secondValue, _ := secondFlag.Value("second-flag", ctx, flag.Context{})
+		if firstValue == secondValue {
+			sameVariation++
+		}
+	}
+
+	assert.InDelta(t, 0.5, float64(distribution["A"])/nbUsers, 0.02)
+	assert.InDelta(t, 0.5, float64(distribution["B"])/nbUsers, 0.02)
+	// the bucketing includes the flag key, the 2 splits are not correlated.
+	assert.InDelta(t, 0.5, float64(sameVariation)/nbUsers, 0.02)
+}
+
+func TestInternalFlag_ScheduledStepsOrder(t *testing.T) {
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wednesday := monday.Add(48 * time.Hour)
+	friday := monday.Add(96 * time.Hour)
+
+	f := flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"A": testconvert.Interface("value_A"),
+			"B": testconvert.Interface("value_B"),
+			"C": testconvert.Interface("value_C"),
+		},
+		Rules: &[]flag.Rule{
+			{
+				Name:            testconvert.String("beta"),
+				Query:           testconvert.String("beta eq true"),
+				VariationResult: testconvert.String("B"),
+			},
+		},
+		DefaultRule: &flag.Rule{VariationResult: testconvert.String("A")},
+		// the steps are not sorted, they are applied in chronological order
+		Scheduled: &[]flag.ScheduledStep{
+			{
+				Date: testconvert.Time(friday),
+				InternalFlag: flag.InternalFlag{
+					// the beta rule is removed and the default rule overrides the one of wednesday
+					Rules:       &[]flag.Rule{{Name: testconvert.String("beta"), Disable: testconvert.Bool(true)}},
+					DefaultRule: &flag.Rule{VariationResult: testconvert.String("C")},
+				},
+			},
+			{
+				Date: testconvert.Time(monday),
+				InternalFlag: flag.InternalFlag{
+					Rules: &[]flag.Rule{
+						{
+							Name:            testconvert.String("internal"),
+							Query:           testconvert.String(`role eq "internal"`),
+							VariationResult: testconvert.String("C"),
+						},
+					},
+				},
+			},
+			{
+				Date: testconvert.Time(wednesday),
+				InternalFlag: flag.InternalFlag{
+					DefaultRule: &flag.Rule{VariationResult: testconvert.String("B")},
+				},
+			},
+		},
+	}
+
+	beta := ffcontext.NewEvaluationContextBuilder("beta-user").AddCustom("beta", true).Build()
+	internal := ffcontext.NewEvaluationContextBuilder("internal-user").AddCustom("role", "internal").Build()
+	other := ffcontext.NewEvaluationContext("other-user")
+	users := []ffcontext.Context{beta, internal, other}
+
+	tests := []struct {
+		name           string
+		evaluationDate time.Time
+		// want contains the values expected for the beta, internal and other users.
+		want []string
+	}{
+		{
+			name:           "before the first step",
+			evaluationDate: monday.Add(-time.Second),
+			want:           []string{"value_B", "value_A", "value_A"},
+		},
+		{
+			name:           "at the date of the first step",
+			evaluationDate: monday,
+			want:           []string{"value_B", "value_C", "value_A"},
+		},
+		{
+			name:           "after the second step",
+			evaluationDate: wednesday.Add(time.Hour),
+			want:           []string{"value_B", "value_C", "value_B"},
+		},
+		{
+			name:           "after the last step",
+			evaluationDate: friday.Add(time.Hour),
+			want:           []string{"value_C", "value_C", "value_C"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for index, user := range users {
+				got, _ := f.Value("my-flag", user, flag.Context{EvaluationDate: tt.evaluationDate})
+				assert.Equal(t, tt.want[index], got, "wrong value for %s", user.GetKey())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package flag
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+)
+
+// Layer is a group of mutually exclusive flags.
+// The users are bucketed with the id of the layer, each flag of the layer owns a slice of the
+// users, so a user is part of only one flag of the layer at a time.
+type Layer struct {
+	// ID is the identifier of the layer, the flags with the same id share the same users.
+	ID string `json:"id" yaml:"id" toml:"id" jsonschema:"required,title=id,description=Identifier of the layer. The flags with the same id are mutually exclusive."` // nolint: lll
+
+	// Start is the percentage where the slice of the flag starts in the layer (inclusive).
+	Start float64 `json:"start" yaml:"start" toml:"start" jsonschema:"title=start,description=Percentage where the slice of the flag starts in the layer (inclusive)."` // nolint: lll
+
+	// End is the percentage where the slice of the flag ends in the layer (exclusive).
+	End float64 `json:"end" yaml:"end" toml:"end" jsonschema:"required,title=end,description=Percentage where the slice of the flag ends in the layer (exclusive)."` // nolint: lll
+}
+
+// IsValid is checking if the layer is valid.
+func (l *Layer) IsValid() error {
+	if l.ID == "" {
+		return fmt.Errorf("invalid layer: id is mandatory")
+	}
+	if l.Start < 0 || l.End > 100 || l.Start >= l.End {
+		return fmt.Errorf("invalid layer %s: the slice should be between 0 and 100 with start < end", l.ID)
+	}
+	return nil
+}
+
+// isInLayer is checking if the user is part of the slice of the layer owned by the flag.
+// The bucket in the layer depends only on the layer id and the user key, so changing the slice
+// of a flag does not move the users of the other flags of the layer.
+func (f *InternalFlag) isInLayer(ctx ffcontext.Context, flagContext Context) bool {
+	if f.Layer == nil {
+		return true
+	}
+	bucket := flagContext.hash("layer:"+f.Layer.ID+ctx.GetKey()) % MaxPercentage
+	return bucket >= uint32(percentageToBuckets(f.Layer.Start)) && bucket < uint32(percentageToBuckets(f.Layer.End))
+}
+
+// FindLayerOverlaps returns an error for each flag that owns a slice overlapping the slice
+// of another flag of the same layer.
+func FindLayerOverlaps(flags map[string]InternalFlag) map[string]error {
+	// we sort the keys to always report the same overlaps.
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	overlaps := map[string]error{}
+	for i, key := range keys {
+		layer := flags[key].Layer
+		if layer == nil {
+			continue
+		}
+		for _, otherKey := range keys[i+1:] {
+			other := flags[otherKey].Layer
+			if other == nil || other.ID != layer.ID {
+				continue
+			}
+			if layer.Start < other.End && other.Start < layer.End {
+				overlaps[key] = fmt.Errorf("slice of layer %s overlaps with flag %s", layer.ID, otherKey)
+				overlaps[otherKey] = fmt.Errorf("slice of layer %s overlaps with flag %s", layer.ID, key)
+			}
+		}
+	}
+	return overlaps
+}
@@ -0,0 +1,98 @@
+package flag_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func newLayerFlag(layer *flag.Layer) *flag.InternalFlag {
+	return &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"treatment": testconvert.Interface(true),
+			"control":   testconvert.Interface(false),
+		},
+		DefaultRule: &flag.Rule{VariationResult: testconvert.String("treatment")},
+		Layer:       layer,
+	}
+}
+
+func TestInternalFlag_ValueWithLayer(t *testing.T) {
+	checkout := newLayerFlag(&flag.Layer{ID: "checkout", Start: 0, End: 50})
+	banner := newLayerFlag(&flag.Layer{ID: "checkout", Start: 50, End: 100})
+	sdkDefault := flag.Context{DefaultSdkValue: false}
+
+	inCheckout, inBanner := 0, 0
+	for i := 0; i < 1000; i++ {
+		ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+		checkoutValue, checkoutDetails := checkout.Value("checkout-flow", ctx, sdkDefault)
+		bannerValue, bannerDetails := banner.Value("checkout-banner", ctx, sdkDefault)
+
+		assert.False(t, checkoutValue == true && bannerValue == true,
+			"user-%d should not be in both treatments", i)
+		assert.NotEqual(t, checkoutDetails.Reason, bannerDetails.Reason,
+			"user-%d should be part of exactly one flag of the layer", i)
+		if checkoutValue == true {
+			inCheckout++
+		} else {
+			assert.Equal(t, flag.ReasonLayerExcluded, checkoutDetails.Reason)
+		}
+		if bannerValue == true {
+			inBanner++
+		} else {
+			assert.Equal(t, flag.ReasonLayerExcluded, bannerDetails.Reason)
+		}
+	}
+	assert.InDelta(t, 500, inCheckout, 60)
+	assert.InDelta(t, 500, inBanner, 60)
+}
+
+func TestInternalFlag_ValueWithLayer_resizeSlice(t *testing.T) {
+	before := newLayerFlag(&flag.Layer{ID: "checkout", Start: 50, End: 100})
+	// a new flag takes the slice [0, 30[, the slice of the existing flag is reduced
+	after := newLayerFlag(&flag.Layer{ID: "checkout", Start: 60, End: 100})
+
+	for i := 0; i < 1000; i++ {
+		ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+		valueAfter, _ := after.Value("checkout-banner", ctx, flag.Context{})
+		if valueAfter == true {
+			valueBefore, _ := before.Value("checkout-banner", ctx, flag.Context{})
+			assert.Equal(t, true, valueBefore, "user-%d should not be reshuffled", i)
+		}
+	}
+}
+
+func TestFindLayerOverlaps(t *testing.T) {
+	flags := map[string]flag.InternalFlag{
+		"flag-a": *newLayerFlag(&flag.Layer{ID: "checkout", Start: 0, End: 50}),
+		"flag-b": *newLayerFlag(&flag.Layer{ID: "checkout", Start: 40, End: 100}),
+		"flag-c": *newLayerFlag(&flag.Layer{ID: "search", Start: 0, End: 100}),
+		"flag-d": *newLayerFlag(nil),
+	}
+	overlaps := flag.FindLayerOverlaps(flags)
+	assert.Len(t, overlaps, 2)
+	assert.Contains(t, overlaps, "flag-a")
+	assert.Contains(t, overlaps, "flag-b")
+}
+
+func TestLayer_IsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		layer   flag.Layer
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "valid layer", layer: flag.Layer{ID: "checkout", Start: 0, End: 50}, wantErr: assert.NoError},
+		{name: "missing id", layer: flag.Layer{Start: 0, End: 50}, wantErr: assert.Error},
+		{name: "start after end", layer: flag.Layer{ID: "checkout", Start: 60, End: 50}, wantErr: assert.Error},
+		{name: "end over 100", layer: flag.Layer{ID: "checkout", Start: 0, End: 120}, wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, tt.layer.IsValid())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package flag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+)
+
+// maxPrerequisiteDepth is the maximum number of nested prerequisites we evaluate,
+// it protects the evaluation against the cycles that have not been detected when loading the flags.
+const maxPrerequisiteDepth = 10
+
+// Prerequisite is a flag that should serve a specific variation for the current flag to be evaluated.
+type Prerequisite struct {
+	// Flag is the key of the flag used as a prerequisite.
+	Flag string `json:"flag" yaml:"flag" toml:"flag" jsonschema:"required,title=flag,description=Key of the flag used as a prerequisite."` // nolint: lll
+
+	// Variation is the name of the variation the prerequisite flag should serve.
+	Variation string `json:"variation" yaml:"variation" toml:"variation" jsonschema:"required,title=variation,description=Name of the variation the prerequisite flag should serve."` // nolint: lll
+}
+
+// arePrerequisitesMet is checking that all the prerequisites of the flag serve the expected variation
+// for this evaluation context.
+func (f *InternalFlag) arePrerequisitesMet(evaluationCtx ffcontext.Context, flagContext Context) bool {
+	prerequisites := f.GetPrerequisites()
+	if len(prerequisites) == 0 {
+		return true
+	}
+	if flagContext.GetPrerequisite == nil || flagContext.prerequisiteDepth >= maxPrerequisiteDepth {
+		return false
+	}
+
+	prerequisiteContext := flagContext
+	prerequisiteContext.DefaultSdkValue = nil
+	prerequisiteContext.prerequisiteDepth++
+	for _, prerequisite := range prerequisites {
+		prerequisiteFlag, err := flagContext.GetPrerequisite(prerequisite.Flag)
+		if err != nil || prerequisiteFlag == nil {
+			return false
+		}
+		value, details := prerequisiteFlag.Value(prerequisite.Flag, evaluationCtx, prerequisiteContext)
+		if flagContext.OnPrerequisiteEvaluated != nil {
+			flagContext.OnPrerequisiteEvaluated(prerequisite.Flag, prerequisiteFlag, value, details)
+		}
+		if details.ErrorCode != "" || details.Variant != prerequisite.Variation {
+			return false
+		}
+	}
+	return true
+}
+
+// FindPrerequisiteCycles returns an error for each flag that is part of a cycle of prerequisites.
+// ex: flag-a requires flag-b and flag-b requires flag-a.
+func FindPrerequisiteCycles(flags map[string]InternalFlag) map[string]error {
+	const (
+		notVisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(flags))
+	cycles := map[string]error{}
+
+	// we sort the keys to always report the same cycle path.
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var visit func(key string, path []string)
+	visit = func(key string, path []string) {
+		f, ok := flags[key]
+		if !ok || state[key] == done {
+			return
+		}
+		if state[key] == inProgress {
+			// the cycle starts where the key appears for the first time in the path.
+			start := 0
+			for i, p := range path {
+				if p == key {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), key)
+			for _, cycleKey := range cycle {
+				if _, alreadyReported := cycles[cycleKey]; !alreadyReported {
+					cycles[cycleKey] = fmt.Errorf("prerequisite cycle detected: %s", strings.Join(cycle, " -> "))
+				}
+			}
+			return
+		}
+
+		state[key] = inProgress
+		for _, prerequisite := range f.GetPrerequisites() {
+			visit(prerequisite.Flag, append(path, key))
+		}
+		state[key] = done
+	}
+
+	for _, key := range keys {
+		if state[key] == notVisited {
+			visit(key, nil)
+		}
+	}
+	return cycles
+}
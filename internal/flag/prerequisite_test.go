@@ -0,0 +1,184 @@
+package flag_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func newPrerequisiteFlag(variation string, prerequisites ...flag.Prerequisite) *flag.InternalFlag {
+	return &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"on":  testconvert.Interface(true),
+			"off": testconvert.Interface(false),
+		},
+		DefaultRule:   &flag.Rule{VariationResult: testconvert.String(variation)},
+		Prerequisites: &prerequisites,
+	}
+}
+
+func TestInternalFlag_ValueWithPrerequisites(t *testing.T) {
+	flags := map[string]*flag.InternalFlag{
+		"parent-on":  newPrerequisiteFlag("on"),
+		"parent-off": newPrerequisiteFlag("off"),
+		"grand-parent-off-parent": newPrerequisiteFlag("on",
+			flag.Prerequisite{Flag: "parent-off", Variation: "on"}),
+	}
+	getPrerequisite := func(flagKey string) (flag.Flag, error) {
+		f, ok := flags[flagKey]
+		if !ok {
+			return nil, fmt.Errorf("flag %s not found", flagKey)
+		}
+		return f, nil
+	}
+
+	tests := []struct {
+		name            string
+		prerequisites   []flag.Prerequisite
+		getPrerequisite func(flagKey string) (flag.Flag, error)
+		want            interface{}
+		wantReason      flag.ResolutionReason
+	}{
+		{
+			name:            "prerequisite satisfied",
+			prerequisites:   []flag.Prerequisite{{Flag: "parent-on", Variation: "on"}},
+			getPrerequisite: getPrerequisite,
+			want:            true,
+			wantReason:      flag.ReasonStatic,
+		},
+		{
+			name:            "prerequisite serving another variation",
+			prerequisites:   []flag.Prerequisite{{Flag: "parent-off", Variation: "on"}},
+			getPrerequisite: getPrerequisite,
+			want:            "sdk-default",
+			wantReason:      flag.ReasonPrerequisiteFailed,
+		},
+		{
+			name: "one of the prerequisites not satisfied",
+			prerequisites: []flag.Prerequisite{
+				{Flag: "parent-on", Variation: "on"},
+				{Flag: "parent-off", Variation: "on"},
+			},
+			getPrerequisite: getPrerequisite,
+			want:            "sdk-default",
+			wantReason:      flag.ReasonPrerequisiteFailed,
+		},
+		{
+			name:            "nested prerequisite not satisfied",
+			prerequisites:   []flag.Prerequisite{{Flag: "grand-parent-off-parent", Variation: "on"}},
+			getPrerequisite: getPrerequisite,
+			want:            "sdk-default",
+			wantReason:      flag.ReasonPrerequisiteFailed,
+		},
+		{
+			name:            "unknown prerequisite",
+			prerequisites:   []flag.Prerequisite{{Flag: "unknown", Variation: "on"}},
+			getPrerequisite: getPrerequisite,
+			want:            "sdk-default",
+			wantReason:      flag.ReasonPrerequisiteFailed,
+		},
+		{
+			name:          "no way to retrieve the prerequisites",
+			prerequisites: []flag.Prerequisite{{Flag: "parent-on", Variation: "on"}},
+			want:          "sdk-default",
+			wantReason:    flag.ReasonPrerequisiteFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPrerequisiteFlag("on", tt.prerequisites...)
+			evaluated := map[string]string{}
+			got, details := f.Value("my-flag", ffcontext.NewEvaluationContext("user-key"), flag.Context{
+				DefaultSdkValue: "sdk-default",
+				GetPrerequisite: tt.getPrerequisite,
+				OnPrerequisiteEvaluated: func(flagKey string, _ flag.Flag, _ interface{}, d flag.ResolutionDetails) {
+					evaluated[flagKey] = d.Variant
+				},
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantReason, details.Reason)
+			if tt.wantReason == flag.ReasonPrerequisiteFailed {
+				assert.Equal(t, flag.VariationSDKDefault, details.Variant)
+			}
+			if tt.name == "prerequisite satisfied" {
+				assert.Equal(t, map[string]string{"parent-on": "on"}, evaluated)
+			}
+		})
+	}
+}
+
+func TestInternalFlag_ValueWithPrerequisiteCycle(t *testing.T) {
+	// a cycle not detected at load time should not make the evaluation loop forever.
+	flagA := newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-b", Variation: "on"})
+	flagB := newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-a", Variation: "on"})
+	flags := map[string]flag.Flag{"flag-a": flagA, "flag-b": flagB}
+
+	got, details := flagA.Value("flag-a", ffcontext.NewEvaluationContext("user-key"), flag.Context{
+		DefaultSdkValue: false,
+		GetPrerequisite: func(flagKey string) (flag.Flag, error) { return flags[flagKey], nil },
+	})
+	assert.Equal(t, false, got)
+	assert.Equal(t, flag.ReasonPrerequisiteFailed, details.Reason)
+}
+
+func TestFindPrerequisiteCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]flag.InternalFlag
+		want  map[string]string
+	}{
+		{
+			name: "no cycle",
+			flags: map[string]flag.InternalFlag{
+				"flag-a": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-b", Variation: "on"}),
+				"flag-b": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-c", Variation: "on"}),
+				"flag-c": *newPrerequisiteFlag("on"),
+				"flag-d": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-c", Variation: "on"}),
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "flag requiring itself",
+			flags: map[string]flag.InternalFlag{
+				"flag-a": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-a", Variation: "on"}),
+			},
+			want: map[string]string{
+				"flag-a": "prerequisite cycle detected: flag-a -> flag-a",
+			},
+		},
+		{
+			name: "cycle between 3 flags",
+			flags: map[string]flag.InternalFlag{
+				"flag-a": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-b", Variation: "on"}),
+				"flag-b": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-c", Variation: "on"}),
+				"flag-c": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-a", Variation: "on"}),
+				"flag-d": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "flag-a", Variation: "on"}),
+			},
+			want: map[string]string{
+				"flag-a": "prerequisite cycle detected: flag-a -> flag-b -> flag-c -> flag-a",
+				"flag-b": "prerequisite cycle detected: flag-a -> flag-b -> flag-c -> flag-a",
+				"flag-c": "prerequisite cycle detected: flag-a -> flag-b -> flag-c -> flag-a",
+			},
+		},
+		{
+			name: "prerequisite on an unknown flag is not a cycle",
+			flags: map[string]flag.InternalFlag{
+				"flag-a": *newPrerequisiteFlag("on", flag.Prerequisite{Flag: "unknown", Variation: "on"}),
+			},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for key, err := range flag.FindPrerequisiteCycles(tt.flags) {
+				got[key] = err.Error()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -21,6 +21,12 @@ type ResolutionDetails struct {
 	// Cacheable is set to true if an SDK/provider can cache the value locally.
 	Cacheable bool
 
+	// ProgressiveRollout (optional) is the state of the progressive rollout if the rule which applied has one.
+	ProgressiveRollout *ProgressiveRolloutDetails
+
 	// Metadata is a field containing information about your flag such as an issue tracker link, a description, etc ...
 	Metadata map[string]interface{}
+
+	// VariationMetadata is the metadata of the variation used when evaluating the flag.
+	VariationMetadata map[string]interface{}
 }
@@ -21,7 +21,24 @@ const (
 	// ReasonDisabled Indicates that the feature flag is disabled
 	ReasonDisabled ResolutionReason = "DISABLED"
 
-	// ReasonDefault The resolved value was the result of the flag being disabled in the management system.
+	// ReasonExpired Indicates that the feature flag has reached its expiration date
+	// and is serving the default value.
+	ReasonExpired ResolutionReason = "EXPIRED"
+
+	// ReasonPrerequisiteFailed Indicates that at least one prerequisite of the feature flag
+	// did not serve the expected variation and that the flag is serving the default value.
+	ReasonPrerequisiteFailed ResolutionReason = "PREREQUISITE_FAILED"
+
+	// ReasonLayerExcluded Indicates that the user is not part of the slice of the layer
+	// owned by the feature flag and that the flag is serving the default value.
+	ReasonLayerExcluded ResolutionReason = "LAYER_EXCLUDED"
+
+	// ReasonHoldout Indicates that the user is part of the holdout of the feature flag
+	// and that the flag is serving the control variation.
+	ReasonHoldout ResolutionReason = "HOLDOUT"
+
+	// ReasonDefault The resolved value was the result of the default rule of the flag,
+	// because no targeting rule matched.
 	ReasonDefault ResolutionReason = "DEFAULT"
 
 	// ReasonStatic	Indicates that the feature flag evaluated to a
@@ -40,4 +57,16 @@ const (
 
 	// ReasonOffline Indicates that GO Feature Flag is currently evaluating in offline mode.
 	ReasonOffline ResolutionReason = "OFFLINE"
+
+	// ReasonFallback Indicates that the flag does not exist and that the value was computed
+	// by the DefaultValueProvider of the client.
+	ReasonFallback ResolutionReason = "FALLBACK"
+
+	// ReasonOverride Indicates that the variation has been pinned for this evaluation context
+	// with an override of the client.
+	ReasonOverride ResolutionReason = "OVERRIDE"
+
+	// ReasonTargetingKeyMissing Indicates that the evaluation context has no targeting key
+	// and that the client requires one to evaluate the flags.
+	ReasonTargetingKeyMissing ResolutionReason = "TARGETING_KEY_MISSING"
 )
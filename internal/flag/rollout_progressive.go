@@ -13,6 +13,27 @@ type ProgressiveRollout struct {
 	End *ProgressiveRolloutStep `json:"end,omitempty" yaml:"end,omitempty" toml:"end,omitempty" jsonschema:"title=initial,description=A description of the end state of the rollout."` // nolint: lll
 }
 
+// RolloutPhase indicates which variation of a progressive rollout is served to an evaluation context.
+type RolloutPhase = string
+
+const (
+	// RolloutPhaseInitial means that the evaluation context receives the initial variation of the rollout.
+	RolloutPhaseInitial RolloutPhase = "INITIAL"
+
+	// RolloutPhaseEnd means that the evaluation context receives the end variation of the rollout.
+	RolloutPhaseEnd RolloutPhase = "END"
+)
+
+// ProgressiveRolloutDetails describes the state of a progressive rollout for an evaluation.
+type ProgressiveRolloutDetails struct {
+	// Phase indicates if the evaluation context receives the initial or the end variation.
+	Phase RolloutPhase `json:"phase"`
+
+	// Percentage is the effective percentage of evaluation contexts receiving the end variation
+	// at the evaluation date.
+	Percentage float64 `json:"percentage"`
+}
+
 // ProgressiveRolloutStep define a progressive rollout step (initial and end)
 type ProgressiveRolloutStep struct {
 	// Variation - name of the variation for this step
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -43,15 +44,22 @@ type Rule struct {
 // Evaluate is checking if the rule apply to for the user.
 // If yes it returns the variation you should use for this rule.
 func (r *Rule) Evaluate(ctx ffcontext.Context, hashID uint32, isDefault bool,
+) (string, error) {
+	return r.evaluate(ctx, hashID, isDefault, time.Now())
+}
+
+// evaluate is checking if the rule apply to for the user at the evaluation date.
+func (r *Rule) evaluate(ctx ffcontext.Context, hashID uint32, isDefault bool, evaluationDate time.Time,
 ) (string, error) {
 	// Check if the rule apply for this user
-	ruleApply := isDefault || r.GetQuery() == "" || parser.Evaluate(r.GetTrimmedQuery(), utils.ContextToMap(ctx))
+	ruleApply := isDefault || r.GetQuery() == "" ||
+		evaluateQuery(r.GetTrimmedQuery(), utils.ContextToMap(ctx), evaluationDate)
 	if !ruleApply || (!isDefault && r.IsDisable()) {
 		return "", &internalerror.RuleNotApply{Context: ctx}
 	}
 
 	if r.ProgressiveRollout != nil {
-		variation, err := r.getVariationFromProgressiveRollout(hashID)
+		variation, err := r.getVariationFromProgressiveRollout(hashID, evaluationDate)
 		if err != nil {
 			return variation, err
 		}
@@ -72,6 +80,17 @@ func (r *Rule) Evaluate(ctx ffcontext.Context, hashID uint32, isDefault bool,
 	return "", fmt.Errorf("error in the configuration, no variation available for this rule")
 }
 
+// evaluateQuery is checking if the query match the evaluation context.
+func evaluateQuery(query string, ctxMap map[string]interface{}, evaluationDate time.Time) bool {
+	if isJSONLogicQuery(query) {
+		return evaluateJSONLogicQuery(query, ctxMap)
+	}
+	query, ctxMap = applyRegexOperators(query, ctxMap)
+	query, ctxMap = applyDateOperators(query, ctxMap, evaluationDate)
+	query, ctxMap = applyNumericCoercion(query, ctxMap)
+	return parser.Evaluate(query, ctxMap)
+}
+
 // IsDynamic is a function that allows to know if the rule has a dynamic result or not.
 func (r *Rule) IsDynamic() bool {
 	hasPercentage100 := false
@@ -84,7 +103,34 @@ func (r *Rule) IsDynamic() bool {
 	return r.ProgressiveRollout != nil || (r.Percentages != nil && len(r.GetPercentages()) > 0 && !hasPercentage100)
 }
 
-func (r *Rule) getVariationFromProgressiveRollout(hash uint32) (string, error) {
+func (r *Rule) getVariationFromProgressiveRollout(hash uint32, evaluationDate time.Time) (string, error) {
+	details, err := r.getProgressiveRolloutState(hash, evaluationDate)
+	if err != nil {
+		return "", err
+	}
+	if details.Phase == RolloutPhaseEnd {
+		return r.ProgressiveRollout.End.getVariation(), nil
+	}
+	return r.ProgressiveRollout.Initial.getVariation(), nil
+}
+
+// progressiveRolloutDetails returns the state of the progressive rollout of the rule at the evaluation date,
+// it returns nil if the rule has no valid progressive rollout.
+func (r *Rule) progressiveRolloutDetails(hash uint32, evaluationDate time.Time) *ProgressiveRolloutDetails {
+	if r.ProgressiveRollout == nil {
+		return nil
+	}
+	details, err := r.getProgressiveRolloutState(hash, evaluationDate)
+	if err != nil {
+		return nil
+	}
+	return details
+}
+
+// getProgressiveRolloutState returns the effective percentage of the progressive rollout at the evaluation date
+// and the phase of the rollout for this hash.
+func (r *Rule) getProgressiveRolloutState(hash uint32, evaluationDate time.Time,
+) (*ProgressiveRolloutDetails, error) {
 	isRolloutValid := r.ProgressiveRollout != nil &&
 		r.ProgressiveRollout.Initial != nil &&
 		r.ProgressiveRollout.Initial.Date != nil &&
@@ -93,34 +139,41 @@ func (r *Rule) getVariationFromProgressiveRollout(hash uint32) (string, error) {
 		r.ProgressiveRollout.End.Date != nil &&
 		r.ProgressiveRollout.End.Variation != nil &&
 		r.ProgressiveRollout.End.Date.After(*r.ProgressiveRollout.Initial.Date)
+	if !isRolloutValid {
+		return nil, fmt.Errorf("error in the progressive rollout, missing params")
+	}
 
-	if isRolloutValid {
-		now := time.Now()
-		if now.Before(*r.ProgressiveRollout.Initial.Date) {
-			return *r.ProgressiveRollout.Initial.Variation, nil
-		}
+	now := evaluationDate
+	if now.Before(*r.ProgressiveRollout.Initial.Date) {
+		return &ProgressiveRolloutDetails{Phase: RolloutPhaseInitial, Percentage: 0}, nil
+	}
 
-		// We are between initial and end
-		initialPercentage := r.ProgressiveRollout.Initial.getPercentage() * PercentageMultiplier
-		if r.ProgressiveRollout.End.getPercentage() == 0 || r.ProgressiveRollout.End.getPercentage() > 100 {
-			max := float64(100)
-			r.ProgressiveRollout.End.Percentage = &max
-		}
-		endPercentage := r.ProgressiveRollout.End.getPercentage() * PercentageMultiplier
+	// We are between initial and end
+	initialPercentage := percentageToBuckets(r.ProgressiveRollout.Initial.getPercentage())
+	if r.ProgressiveRollout.End.getPercentage() == 0 || r.ProgressiveRollout.End.getPercentage() > 100 {
+		max := float64(100)
+		r.ProgressiveRollout.End.Percentage = &max
+	}
+	endPercentage := percentageToBuckets(r.ProgressiveRollout.End.getPercentage())
 
+	currentPercentage := endPercentage
+	if now.Before(*r.ProgressiveRollout.End.Date) {
 		nbSec := r.ProgressiveRollout.End.Date.Unix() - r.ProgressiveRollout.Initial.Date.Unix()
 		percentage := endPercentage - initialPercentage
 		percentPerSec := percentage / float64(nbSec)
 
 		c := now.Unix() - r.ProgressiveRollout.Initial.Date.Unix()
-		currentPercentage := float64(c)*percentPerSec + initialPercentage
+		currentPercentage = float64(c)*percentPerSec + initialPercentage
+	}
 
-		if hash < uint32(currentPercentage) {
-			return r.ProgressiveRollout.End.getVariation(), nil
-		}
-		return r.ProgressiveRollout.Initial.getVariation(), nil
+	details := &ProgressiveRolloutDetails{
+		Phase:      RolloutPhaseInitial,
+		Percentage: currentPercentage / PercentageMultiplier,
 	}
-	return "", fmt.Errorf("error in the progressive rollout, missing params")
+	if hash < uint32(currentPercentage) {
+		details.Phase = RolloutPhaseEnd
+	}
+	return details, nil
 }
 
 func (r *Rule) getVariationFromPercentage(hash uint32) (string, error) {
@@ -138,6 +191,8 @@ func (r *Rule) getVariationFromPercentage(hash uint32) (string, error) {
 }
 
 // getPercentageBuckets compute a map containing the buckets of each variation for this rule.
+// The buckets are contiguous, so changing the weight of a variation only moves the users at the edge of its bucket.
+// The buckets only depend on the variation names, reordering the variations in the flag file does not move any user.
 func (r *Rule) getPercentageBuckets() (map[string]percentageBucket, error) {
 	percentageBuckets := make(map[string]percentageBucket, len(r.GetPercentages()))
 	percentage := r.GetPercentages()
@@ -158,7 +213,7 @@ func (r *Rule) getPercentageBuckets() (map[string]percentageBucket, error) {
 		if index != 0 {
 			startBucket = percentageBuckets[variationNames[index-1]].end
 		}
-		endBucket := startBucket + (percentage[varName] * PercentageMultiplier)
+		endBucket := startBucket + percentageToBuckets(percentage[varName])
 
 		percentageBuckets[varName] = percentageBucket{
 			start: startBucket,
@@ -174,6 +229,21 @@ func (r *Rule) getPercentageBuckets() (map[string]percentageBucket, error) {
 	return percentageBuckets, nil
 }
 
+// percentageToBuckets converts a percentage into a number of buckets, the percentage is rounded to the
+// resolution of the buckets (0.001%) so fractional percentages (ex: 0.1) are not affected by floating point errors.
+func percentageToBuckets(percentage float64) float64 {
+	return math.Round(percentage * PercentageMultiplier)
+}
+
+// SumPercentages returns the sum of the percentages rounded to the resolution of the buckets (0.001%).
+func SumPercentages(percentages map[string]float64) float64 {
+	total := float64(0)
+	for _, percentage := range percentages {
+		total += percentageToBuckets(percentage)
+	}
+	return total / PercentageMultiplier
+}
+
 // MergeRules is merging 2 rules.
 // It is used when we have to update a rule in a scheduled rollout.
 func (r *Rule) MergeRules(updatedRule Rule) {
@@ -210,6 +280,10 @@ func (r *Rule) MergeRules(updatedRule Rule) {
 		}
 		r.Percentages = &mergedPercentages
 	}
+
+	if updatedRule.Disable != nil {
+		r.Disable = updatedRule.Disable
+	}
 }
 
 // IsValid is checking if the rule is valid
@@ -229,13 +303,14 @@ func (r *Rule) IsValid(defaultRule bool) error {
 
 	// Validate the percentage of the rule
 	if r.Percentages != nil {
-		count := float64(0)
-		for _, p := range r.GetPercentages() {
-			count += p
+		for variation, p := range r.GetPercentages() {
+			if p < 0 {
+				return fmt.Errorf("invalid percentages, percentage of variation %s should be positive, got %v", variation, p)
+			}
 		}
 
-		if count != 100 {
-			return fmt.Errorf("invalid percentages")
+		if count := SumPercentages(r.GetPercentages()); count != 100 {
+			return fmt.Errorf("invalid percentages, percentages should sum to 100, got %v", count)
 		}
 	}
 
@@ -0,0 +1,103 @@
+package flag
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// dateBeforeOperator is the operator used in a query to check if a date attribute is before another date.
+	// ex: trialEnd dateBefore now
+	dateBeforeOperator = "dateBefore"
+
+	// dateAfterOperator is the operator used in a query to check if a date attribute is after another date.
+	// ex: signupDate dateAfter "2024-01-01T00:00:00Z"
+	dateAfterOperator = "dateAfter"
+
+	// dateNow is the special operand used to compare a date attribute with the evaluation time.
+	dateNow = "now"
+
+	// dateAttributePrefix is the prefix of the attributes we are injecting in the evaluation context
+	// to replace the date operators by something the query parser understands.
+	dateAttributePrefix = "goffDateResult"
+)
+
+// dateClause is matching the date operators in a query, ex: signupDate dateAfter "2024-01-01T00:00:00Z"
+var dateClause = regexp.MustCompile(`([a-zA-Z0-9_.\-]+)\s+(` + dateBeforeOperator + `|` + dateAfterOperator + `)\s+` +
+	`("(?:[^"\\]|\\.)*"|` + dateNow + `\b)`)
+
+// applyDateOperators is replacing all the date operators of the query by an equality check
+// on an attribute injected in the evaluation context containing the result of the comparison.
+// The operand now is replaced by the evaluation date.
+// If the query does not contain any date operator, the query and the context are returned unchanged.
+func applyDateOperators(query string, ctxMap map[string]interface{}, now time.Time,
+) (string, map[string]interface{}) {
+	if !strings.Contains(query, dateBeforeOperator) && !strings.Contains(query, dateAfterOperator) {
+		return query, ctxMap
+	}
+
+	index := 0
+	query = dateClause.ReplaceAllStringFunc(query, func(clause string) string {
+		submatches := dateClause.FindStringSubmatch(clause)
+		attributeName := fmt.Sprintf("%s%d", dateAttributePrefix, index)
+		index++
+		ctxMap[attributeName] = compareDates(getAttributeValue(ctxMap, submatches[1]), submatches[2], submatches[3], now)
+		return attributeName + " eq true"
+	})
+	return query, ctxMap
+}
+
+// compareDates is checking if the value is before or after the operand.
+// If the value or the operand are not valid RFC3339 dates, we consider that the comparison does not match.
+func compareDates(value interface{}, operator string, operand string, now time.Time) bool {
+	strValue, ok := value.(string)
+	if !ok {
+		return false
+	}
+	date, err := time.Parse(time.RFC3339, strValue)
+	if err != nil {
+		return false
+	}
+	reference, err := parseDateOperand(operand, now)
+	if err != nil {
+		return false
+	}
+	if operator == dateBeforeOperator {
+		return date.Before(reference)
+	}
+	return date.After(reference)
+}
+
+// parseDateOperand returns the date of the operand, the operand is either now or a quoted RFC3339 date.
+func parseDateOperand(operand string, now time.Time) (time.Time, error) {
+	if operand == dateNow {
+		return now, nil
+	}
+	return time.Parse(time.RFC3339, strings.Trim(operand, `"`))
+}
+
+// usesEvaluationDate checks if the query compares a date attribute with now.
+func usesEvaluationDate(query string) bool {
+	if !strings.Contains(query, dateBeforeOperator) && !strings.Contains(query, dateAfterOperator) {
+		return false
+	}
+	for _, submatches := range dateClause.FindAllStringSubmatch(query, -1) {
+		if submatches[3] == dateNow {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDateOperators checks that all the dates used with the date operators in the query are valid.
+func ValidateDateOperators(query string) error {
+	for _, submatches := range dateClause.FindAllStringSubmatch(query, -1) {
+		if _, err := parseDateOperand(submatches[3], time.Time{}); err != nil {
+			return fmt.Errorf("invalid date %s for attribute %s, dates should use the RFC3339 format "+
+				"(ex: \"2024-01-01T00:00:00Z\")", submatches[3], submatches[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package flag
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// parsedJSONLogicQueries is a cache of the parsed JsonLogic queries, the key is the query.
+var parsedJSONLogicQueries sync.Map
+
+// parsedJSONLogicQuery is the result of the parsing of a JsonLogic query.
+type parsedJSONLogicQuery struct {
+	expression interface{}
+	err        error
+	// logOnce is used to log only once when a query is invalid
+	logOnce sync.Once
+}
+
+// isJSONLogicQuery checks if the query is a JsonLogic expression (ex: {"and": [...]}) instead of
+// a query in the nikunjy/rules format.
+func isJSONLogicQuery(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "{")
+}
+
+// evaluateJSONLogicQuery is checking if the JsonLogic query matches the evaluation context.
+// An attribute missing from the context is considered as null, and if the query is invalid it does not match.
+func evaluateJSONLogicQuery(query string, ctxMap map[string]interface{}) bool {
+	parsed := getParsedJSONLogicQuery(query)
+	if parsed.err != nil {
+		parsed.logOnce.Do(func() {
+			log.Printf("warning: invalid JsonLogic query %q: %v", query, parsed.err)
+		})
+		return false
+	}
+	result, err := evaluateJSONLogic(parsed.expression, ctxMap)
+	if err != nil {
+		return false
+	}
+	return isTruthy(result)
+}
+
+// ValidateJSONLogicQuery checks that the query is a valid JsonLogic expression using only the supported operators.
+// Queries using the nikunjy/rules format are ignored.
+func ValidateJSONLogicQuery(query string) error {
+	if !isJSONLogicQuery(query) {
+		return nil
+	}
+	if parsed := getParsedJSONLogicQuery(query); parsed.err != nil {
+		return fmt.Errorf("invalid JsonLogic query: %v", parsed.err)
+	}
+	return nil
+}
+
+// getParsedJSONLogicQuery returns the parsed version of the query, the result is cached to avoid
+// parsing the query at each evaluation.
+func getParsedJSONLogicQuery(query string) *parsedJSONLogicQuery {
+	if cached, ok := parsedJSONLogicQueries.Load(query); ok {
+		return cached.(*parsedJSONLogicQuery)
+	}
+	var expression interface{}
+	err := json.Unmarshal([]byte(query), &expression)
+	if err == nil {
+		err = checkJSONLogicOperators(expression)
+	}
+	cached, _ := parsedJSONLogicQueries.LoadOrStore(query, &parsedJSONLogicQuery{expression: expression, err: err})
+	return cached.(*parsedJSONLogicQuery)
+}
+
+// checkJSONLogicOperators checks that all the operators of the expression are supported.
+func checkJSONLogicOperators(expression interface{}) error {
+	switch value := expression.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if err := checkJSONLogicOperators(item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		operator, args, err := getJSONLogicOperation(value)
+		if err != nil {
+			return err
+		}
+		if !isSupportedJSONLogicOperator(operator) {
+			return fmt.Errorf("unsupported operator %q", operator)
+		}
+		return checkJSONLogicOperators(args)
+	}
+	return nil
+}
+
+func isSupportedJSONLogicOperator(operator string) bool {
+	switch operator {
+	case "var", "and", "or", "!", "not", "!!", "==", "!=", "<", "<=", ">", ">=", "in":
+		return true
+	default:
+		return false
+	}
+}
+
+// getJSONLogicOperation returns the operator and the arguments of an operation, ex: {"==": [1, 1]}.
+func getJSONLogicOperation(operation map[string]interface{}) (string, []interface{}, error) {
+	if len(operation) != 1 {
+		return "", nil, fmt.Errorf("an operation should have exactly one operator, got %d", len(operation))
+	}
+	for operator, args := range operation {
+		// the arguments can be a single value when there is only one argument, ex: {"var": "email"}
+		if list, ok := args.([]interface{}); ok {
+			return operator, list, nil
+		}
+		return operator, []interface{}{args}, nil
+	}
+	return "", nil, nil
+}
+
+// evaluateJSONLogic evaluates the expression, "and" and "or" stop evaluating their arguments
+// as soon as the result is known.
+func evaluateJSONLogic(expression interface{}, ctxMap map[string]interface{}) (interface{}, error) {
+	switch value := expression.(type) {
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			evaluated, err := evaluateJSONLogic(item, ctxMap)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, evaluated)
+		}
+		return result, nil
+	case map[string]interface{}:
+		return evaluateJSONLogicOperation(value, ctxMap)
+	default:
+		return value, nil
+	}
+}
+
+// nolint: gocyclo
+func evaluateJSONLogicOperation(operation map[string]interface{}, ctxMap map[string]interface{}) (interface{}, error) {
+	operator, args, err := getJSONLogicOperation(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "and":
+		for _, arg := range args {
+			result, err := evaluateJSONLogic(arg, ctxMap)
+			if err != nil {
+				return nil, err
+			}
+			if !isTruthy(result) {
+				return false, nil
+			}
+		}
+		return len(args) > 0, nil
+	case "or":
+		for _, arg := range args {
+			result, err := evaluateJSONLogic(arg, ctxMap)
+			if err != nil {
+				return nil, err
+			}
+			if isTruthy(result) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	values, err := evaluateJSONLogic(args, ctxMap)
+	if err != nil {
+		return nil, err
+	}
+	evaluatedArgs := values.([]interface{})
+
+	switch operator {
+	case "var":
+		return jsonLogicVar(evaluatedArgs, ctxMap)
+	case "!", "not":
+		if len(evaluatedArgs) != 1 {
+			return nil, fmt.Errorf("operator %q expects 1 argument, got %d", operator, len(evaluatedArgs))
+		}
+		return !isTruthy(evaluatedArgs[0]), nil
+	case "!!":
+		if len(evaluatedArgs) != 1 {
+			return nil, fmt.Errorf("operator %q expects 1 argument, got %d", operator, len(evaluatedArgs))
+		}
+		return isTruthy(evaluatedArgs[0]), nil
+	case "==", "!=":
+		if len(evaluatedArgs) != 2 {
+			return nil, fmt.Errorf("operator %q expects 2 arguments, got %d", operator, len(evaluatedArgs))
+		}
+		equal := jsonLogicEqual(evaluatedArgs[0], evaluatedArgs[1])
+		return equal == (operator == "=="), nil
+	case "<", "<=", ">", ">=":
+		return jsonLogicCompare(operator, evaluatedArgs)
+	case "in":
+		if len(evaluatedArgs) != 2 {
+			return nil, fmt.Errorf("operator %q expects 2 arguments, got %d", operator, len(evaluatedArgs))
+		}
+		return jsonLogicIn(evaluatedArgs[0], evaluatedArgs[1]), nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %q", operator)
+	}
+}
+
+// jsonLogicVar returns the value of the attribute, ex: {"var": "company.name"} or {"var": ["company.name", "default"]}.
+// If the attribute is missing, the default value or null is returned.
+func jsonLogicVar(args []interface{}, ctxMap map[string]interface{}) (interface{}, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("operator \"var\" expects 1 or 2 arguments, got %d", len(args))
+	}
+	attributePath, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("operator \"var\" expects the name of an attribute, got %v", args[0])
+	}
+	value := getAttributeValue(ctxMap, attributePath)
+	if value == nil && len(args) == 2 {
+		return args[1], nil
+	}
+	return value, nil
+}
+
+// jsonLogicEqual compares 2 values, the numbers are compared whatever their types are.
+func jsonLogicEqual(left interface{}, right interface{}) bool {
+	_, leftIsString := left.(string)
+	_, rightIsString := right.(string)
+	leftNumber, leftOk := toNumber(left)
+	rightNumber, rightOk := toNumber(right)
+	if leftOk && rightOk && !leftIsString && !rightIsString {
+		return leftNumber == rightNumber
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// jsonLogicCompare compares numbers, or strings if both values are strings.
+// A missing attribute or a value that is not comparable never matches.
+// The form {"<": [1, {"var": "age"}, 10]} checks that the value is between the 2 bounds.
+func jsonLogicCompare(operator string, args []interface{}) (bool, error) {
+	if len(args) != 2 && !(len(args) == 3 && (operator == "<" || operator == "<=")) {
+		return false, fmt.Errorf("operator %q expects 2 arguments, got %d", operator, len(args))
+	}
+	for i := 0; i < len(args)-1; i++ {
+		cmp, ok := compareJSONLogicValues(args[i], args[i+1])
+		if !ok {
+			return false, nil
+		}
+		var match bool
+		switch operator {
+		case "<":
+			match = cmp < 0
+		case "<=":
+			match = cmp <= 0
+		case ">":
+			match = cmp > 0
+		case ">=":
+			match = cmp >= 0
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compareJSONLogicValues returns -1, 0 or 1 if left is lower, equal or greater than right.
+func compareJSONLogicValues(left interface{}, right interface{}) (int, bool) {
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	if leftIsString && rightIsString {
+		return strings.Compare(leftString, rightString), true
+	}
+	leftNumber, leftOk := toNumber(left)
+	rightNumber, rightOk := toNumber(right)
+	if !leftOk || !rightOk {
+		return 0, false
+	}
+	switch {
+	case leftNumber < rightNumber:
+		return -1, true
+	case leftNumber > rightNumber:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// jsonLogicIn checks if the value is in the list or if the value is a substring of the string.
+func jsonLogicIn(value interface{}, container interface{}) bool {
+	switch haystack := container.(type) {
+	case []interface{}:
+		for _, item := range haystack {
+			if jsonLogicEqual(value, item) {
+				return true
+			}
+		}
+		return false
+	case string:
+		needle, ok := value.(string)
+		return ok && strings.Contains(haystack, needle)
+	}
+	// the lists coming from the evaluation context can have any type (ex: []string).
+	rv := reflect.ValueOf(container)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if jsonLogicEqual(value, rv.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTruthy follows the JsonLogic rules: null, false, 0, "" and [] are false, everything else is true.
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	if number, ok := toNumber(value); ok {
+		return number != 0
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package flag
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericAttributePrefix is the prefix of the attributes we are injecting in the evaluation context
+// to replace the values coerced to numbers in the numeric comparisons.
+const numericAttributePrefix = "goffNumericValue"
+
+// numericClause is matching a numeric comparison in a query, ex: age gt 40, age >= "40"
+var numericClause = regexp.MustCompile(
+	`([a-zA-Z0-9_.\-]+)\s+((?i:gt|lt|ge|le)|>=|<=|>|<)\s+("(?:[^"\\]|\\.)*"|-?[0-9]+(?:\.[0-9]+)?)`)
+
+// applyNumericCoercion is replacing the numeric comparisons (gt, lt, ge, le) mixing a number and a string
+// by a comparison between 2 numbers, the coerced value of the attribute is injected in the evaluation context.
+// ex: with the context {"age": "42"} the clause age gt 40 is evaluated as 42 gt 40.
+//
+// The comparisons between 2 strings are not coerced, and if a value cannot be coerced the clause does not match.
+func applyNumericCoercion(query string, ctxMap map[string]interface{}) (string, map[string]interface{}) {
+	matches := numericClause.FindAllStringSubmatchIndex(query, -1)
+	if len(matches) == 0 {
+		return query, ctxMap
+	}
+
+	var result strings.Builder
+	lastIndex := 0
+	index := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		// the clause is part of a string literal, we don't touch it.
+		if isInsideStringLiteral(query[:start]) {
+			continue
+		}
+
+		attributeName := query[match[2]:match[3]]
+		operator := query[match[4]:match[5]]
+		literal := query[match[6]:match[7]]
+
+		attributeValue := getAttributeValue(ctxMap, attributeName)
+		_, attributeIsString := attributeValue.(string)
+		_, attributeIsNumber := toNumber(attributeValue)
+		attributeIsNumber = attributeIsNumber && !attributeIsString
+		literalIsString := strings.HasPrefix(literal, `"`)
+		if !(attributeIsString && !literalIsString) && !(attributeIsNumber && literalIsString) {
+			// nothing to coerce: missing attribute, 2 numbers, 2 strings or a type we can't compare.
+			continue
+		}
+
+		left, leftOk := toNumber(attributeValue)
+		right, rightOk := toNumber(unquote(literal))
+		injectedName := fmt.Sprintf("%s%d", numericAttributePrefix, index)
+		index++
+
+		var clause string
+		if leftOk && rightOk {
+			ctxMap[injectedName] = left
+			clause = fmt.Sprintf("%s %s %s", injectedName, operator, strconv.FormatFloat(right, 'f', -1, 64))
+		} else {
+			ctxMap[injectedName] = false
+			clause = injectedName + " eq true"
+		}
+		result.WriteString(query[lastIndex:start])
+		result.WriteString(clause)
+		lastIndex = end
+	}
+	result.WriteString(query[lastIndex:])
+	return result.String(), ctxMap
+}
+
+// toNumber converts a number or a string representing a number to a float64.
+func toNumber(value interface{}) (float64, bool) {
+	if str, ok := value.(string); ok {
+		number, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		return number, err == nil
+	}
+	if value == nil {
+		return 0, false
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// unquote removes the quotes of a string literal of the query, other literals are returned unchanged.
+func unquote(literal string) interface{} {
+	if !strings.HasPrefix(literal, `"`) {
+		return literal
+	}
+	return strings.ReplaceAll(literal[1:len(literal)-1], `\"`, `"`)
+}
+
+// isInsideStringLiteral checks if the end of the query prefix is inside a string literal,
+// by counting the unescaped quotes.
+func isInsideStringLiteral(prefix string) bool {
+	quotes := 0
+	for i := 0; i < len(prefix); i++ {
+		if prefix[i] == '\\' {
+			i++
+			continue
+		}
+		if prefix[i] == '"' {
+			quotes++
+		}
+	}
+	return quotes%2 == 1
+}
@@ -0,0 +1,153 @@
+package flag
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// regexOperator is the operator used in a query to check if a string attribute matches a regex.
+	// ex: email matchesRegex "^.*@gofeatureflag\.org$"
+	regexOperator = "matchesRegex"
+
+	// regexAttributePrefix is the prefix of the attributes we are injecting in the evaluation context
+	// to replace the regex operators by something the query parser understands.
+	regexAttributePrefix = "goffRegexResult"
+
+	// maxCompiledRegexes is the maximum number of compiled patterns kept in memory,
+	// when the limit is reached the oldest pattern is evicted.
+	maxCompiledRegexes = 1000
+)
+
+// regexClause is matching the regex operator in a query, ex: email matchesRegex "^.*@gofeatureflag\.org$"
+var regexClause = regexp.MustCompile(`([a-zA-Z0-9_.\-]+)\s+` + regexOperator + `\s+"((?:[^"\\]|\\.)*)"`)
+
+// compiledRegexes is a bounded cache of the compiled regexes, the key is the pattern used in the query.
+var compiledRegexes = &regexCache{entries: map[string]*compiledRegex{}}
+
+// compiledRegex is the result of the compilation of a pattern.
+type compiledRegex struct {
+	regex *regexp.Regexp
+	err   error
+}
+
+// regexCache is a cache of compiled regexes with a maximum size.
+type regexCache struct {
+	mutex   sync.RWMutex
+	entries map[string]*compiledRegex
+	// order contains the patterns in their insertion order, it is used to evict the oldest pattern.
+	order []string
+}
+
+// get returns the compiled version of the pattern, the pattern is compiled if not in the cache.
+func (c *regexCache) get(pattern string) *compiledRegex {
+	c.mutex.RLock()
+	cached, ok := c.entries[pattern]
+	c.mutex.RUnlock()
+	if ok {
+		return cached
+	}
+
+	regex, err := regexp.Compile(pattern)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cached, ok := c.entries[pattern]; ok {
+		return cached
+	}
+	if len(c.order) >= maxCompiledRegexes {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	cached = &compiledRegex{regex: regex, err: err}
+	c.entries[pattern] = cached
+	c.order = append(c.order, pattern)
+	return cached
+}
+
+// applyRegexOperators is replacing all the regex operators of the query by an equality check
+// on an attribute injected in the evaluation context containing the result of the regex.
+// If the query does not contain any regex operator, the query and the context are returned unchanged.
+func applyRegexOperators(query string, ctxMap map[string]interface{}) (string, map[string]interface{}) {
+	if !strings.Contains(query, regexOperator) {
+		return query, ctxMap
+	}
+
+	var result strings.Builder
+	lastIndex := 0
+	index := 0
+	for _, match := range regexClause.FindAllStringSubmatchIndex(query, -1) {
+		start, end := match[0], match[1]
+		// the clause is part of a string literal, we don't touch it.
+		if isInsideStringLiteral(query[:start]) {
+			continue
+		}
+
+		attributeValue := getAttributeValue(ctxMap, query[match[2]:match[3]])
+		attributeName := fmt.Sprintf("%s%d", regexAttributePrefix, index)
+		index++
+		ctxMap[attributeName] = matchRegex(query[match[4]:match[5]], attributeValue)
+		result.WriteString(query[lastIndex:start])
+		result.WriteString(attributeName + " eq true")
+		lastIndex = end
+	}
+	result.WriteString(query[lastIndex:])
+	return result.String(), ctxMap
+}
+
+// matchRegex is checking if the value matches the pattern.
+// If the pattern is invalid or the value is not a string, we consider that the value does not match.
+// The invalid patterns are reported when the flags are loaded.
+func matchRegex(pattern string, value interface{}) bool {
+	strValue, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	regex := compiledRegexes.get(strings.ReplaceAll(pattern, `\"`, `"`))
+	if regex.err != nil {
+		return false
+	}
+	return regex.regex.MatchString(strValue)
+}
+
+// getAttributeValue returns the value of an attribute in the context,
+// nested attributes are separated by a dot.
+func getAttributeValue(ctxMap map[string]interface{}, attributePath string) interface{} {
+	var current interface{} = ctxMap
+	for _, part := range strings.Split(attributePath, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// ValidateRegexOperators checks that all the patterns used with the regex operator in the query are valid.
+func ValidateRegexOperators(query string) error {
+	for _, match := range regexClause.FindAllStringSubmatchIndex(query, -1) {
+		if isInsideStringLiteral(query[:match[0]]) {
+			continue
+		}
+		pattern := strings.ReplaceAll(query[match[4]:match[5]], `\"`, `"`)
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid regex %q for attribute %s: %w", pattern, query[match[2]:match[3]], err)
+		}
+	}
+	return nil
+}
+
+// InvalidRegexOperators returns an error for each targeting rule of the flag using an invalid regex,
+// those rules never match.
+func (f *InternalFlag) InvalidRegexOperators() []error {
+	var errs []error
+	for _, rule := range f.GetRules() {
+		if err := ValidateRegexOperators(rule.GetTrimmedQuery()); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.GetName(), err))
+		}
+	}
+	return errs
+}
@@ -478,6 +478,60 @@ func TestRule_Evaluate(t *testing.T) {
 			args:    args{},
 			wantErr: assert.Error,
 		},
+		{
+			name: "User match the regex query",
+			rule: flag.Rule{
+				Name:            testconvert.String("rule1"),
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(`email matchesRegex "^.*@gofeatureflag\.org$" and key eq "abc"`),
+			},
+			args: args{
+				user: ffcontext.NewEvaluationContextBuilder("abc").
+					AddCustom("email", "john.doe@gofeatureflag.org").Build(),
+			},
+			want:    "variation_A",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "User does not match the regex query",
+			rule: flag.Rule{
+				Name:            testconvert.String("rule1"),
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(`email matchesRegex "^.*@gofeatureflag\.org$"`),
+			},
+			args: args{
+				user: ffcontext.NewEvaluationContextBuilder("abc").
+					AddCustom("email", "john.doe@gofeatureflag.com").Build(),
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "Regex operator inside a string literal should not be replaced",
+			rule: flag.Rule{
+				Name:            testconvert.String("rule1"),
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(`description eq "email matchesRegex \"^.*$\""`),
+			},
+			args: args{
+				user: ffcontext.NewEvaluationContextBuilder("abc").
+					AddCustom("description", `email matchesRegex "^.*$"`).Build(),
+			},
+			want:    "variation_A",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "Invalid regex should not match",
+			rule: flag.Rule{
+				Name:            testconvert.String("rule1"),
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(`email matchesRegex "^(.*@gofeatureflag\.org$"`),
+			},
+			args: args{
+				user: ffcontext.NewEvaluationContextBuilder("abc").
+					AddCustom("email", "john.doe@gofeatureflag.org").Build(),
+			},
+			wantErr: assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -695,3 +749,366 @@ func TestRule_MergeRules(t *testing.T) {
 		})
 	}
 }
+
+func TestRule_EvaluateNumericCoercion(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		age   interface{}
+		want  bool
+	}{
+		{name: "gt string attribute with number", query: `age gt 40`, age: "42", want: true},
+		{name: "gt string attribute with number not matching", query: `age gt 40`, age: "38", want: false},
+		{name: "> number attribute with string", query: `age > "40"`, age: 42, want: true},
+		{name: "lt string attribute with number", query: `age lt 40`, age: "38.5", want: true},
+		{name: "< number attribute with string", query: `age < "40"`, age: 42.0, want: false},
+		{name: "ge string attribute with number", query: `age ge 40`, age: "40", want: true},
+		{name: ">= number attribute with string", query: `age >= "40.5"`, age: 40, want: false},
+		{name: "le string attribute with number", query: `age le 40`, age: "40", want: true},
+		{name: "<= number attribute with string", query: `age <= "-1"`, age: -2, want: true},
+		{name: "native numbers", query: `age gt 40`, age: 42, want: true},
+		{name: "non coercible string does not match gt", query: `age gt 40`, age: "forty-two", want: false},
+		{name: "non coercible string does not match le", query: `age le 40`, age: "forty-two", want: false},
+		{name: "non coercible literal does not match", query: `age lt "forty"`, age: 42, want: false},
+		{name: "equality on strings is not coerced", query: `age eq "42.0"`, age: "42", want: false},
+		{name: "coercion combined with other clauses", query: `age gt 40 and key eq "abc"`, age: "42", want: true},
+		{name: "string literal containing a comparison", query: `name eq "age gt 40"`, age: "38", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := flag.Rule{
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(tt.query),
+			}
+			user := ffcontext.NewEvaluationContextBuilder("abc").AddCustom("age", tt.age).Build()
+			got, err := rule.Evaluate(user, 0, false)
+			if tt.want {
+				assert.NoError(t, err)
+				assert.Equal(t, "variation_A", got)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRule_EvaluateDateOperators(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		query      string
+		signupDate interface{}
+		want       bool
+	}{
+		{name: "dateBefore literal", query: `signupDate dateBefore "2024-01-01T00:00:00Z"`, signupDate: "2023-12-31T23:59:59Z", want: true},
+		{name: "dateBefore literal not matching", query: `signupDate dateBefore "2024-01-01T00:00:00Z"`, signupDate: "2024-01-02T00:00:00Z", want: false},
+		{name: "dateBefore equal date", query: `signupDate dateBefore "2024-01-01T00:00:00Z"`, signupDate: "2024-01-01T00:00:00Z", want: false},
+		{name: "dateAfter literal", query: `signupDate dateAfter "2024-01-01T00:00:00Z"`, signupDate: "2024-01-01T00:00:01Z", want: true},
+		{name: "dateAfter literal not matching", query: `signupDate dateAfter "2024-01-01T00:00:00Z"`, signupDate: "2023-06-01T00:00:00Z", want: false},
+		{name: "dateAfter equal date", query: `signupDate dateAfter "2024-01-01T00:00:00Z"`, signupDate: "2024-01-01T00:00:00Z", want: false},
+		{name: "dateAfter with timezone offset", query: `signupDate dateAfter "2024-01-01T00:00:00Z"`, signupDate: "2024-01-01T00:30:00-01:00", want: true},
+		{name: "dateBefore now", query: `signupDate dateBefore now`, signupDate: now.Add(-time.Hour).Format(time.RFC3339), want: true},
+		{name: "dateAfter now", query: `signupDate dateAfter now`, signupDate: now.Add(time.Hour).Format(time.RFC3339), want: true},
+		{name: "dateAfter now not matching", query: `signupDate dateAfter now`, signupDate: now.Add(-time.Hour).Format(time.RFC3339), want: false},
+		{name: "trial window", query: `signupDate dateBefore now and trialEnd dateAfter now`, signupDate: now.Add(-time.Hour).Format(time.RFC3339), want: true},
+		{name: "invalid attribute date", query: `signupDate dateBefore now`, signupDate: "yesterday", want: false},
+		{name: "invalid literal date", query: `signupDate dateBefore "2024-13-01"`, signupDate: "2024-01-01T00:00:00Z", want: false},
+		{name: "attribute is not a string", query: `signupDate dateBefore now`, signupDate: 1704067200, want: false},
+		{name: "missing attribute", query: `other dateBefore now`, signupDate: "2024-01-01T00:00:00Z", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := flag.Rule{
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(tt.query),
+			}
+			user := ffcontext.NewEvaluationContextBuilder("abc").
+				AddCustom("signupDate", tt.signupDate).
+				AddCustom("trialEnd", now.Add(24*time.Hour).Format(time.RFC3339)).
+				Build()
+			got, err := rule.Evaluate(user, 0, false)
+			if tt.want {
+				assert.NoError(t, err)
+				assert.Equal(t, "variation_A", got)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestInternalFlag_ValueWithDateOperators_cacheable(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		wantCacheable bool
+	}{
+		{name: "literal date", query: `signupDate dateAfter "2024-01-01T00:00:00Z"`, wantCacheable: true},
+		{name: "relative to now", query: `trialEnd dateAfter now`, wantCacheable: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flag.InternalFlag{
+				Variations: &map[string]*interface{}{
+					"variation_A": testconvert.Interface("value_A"),
+					"variation_B": testconvert.Interface("value_B"),
+				},
+				Rules: &[]flag.Rule{{
+					Query:           testconvert.String(tt.query),
+					VariationResult: testconvert.String("variation_A"),
+				}},
+				DefaultRule: &flag.Rule{VariationResult: testconvert.String("variation_B")},
+			}
+			// the result of a rule using now changes over time, even when the rule does not match
+			_, details := f.Value("test-flag", ffcontext.NewEvaluationContext("user-key"), flag.Context{})
+			assert.Equal(t, "variation_B", details.Variant)
+			assert.Equal(t, tt.wantCacheable, details.Cacheable)
+		})
+	}
+}
+
+func TestRule_EvaluateJSONLogic(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{
+			name:  "nested and/or matching",
+			query: `{"and": [{"==": [{"var": "country"}, "FR"]}, {"or": [{">=": [{"var": "age"}, 40]}, {"in": [{"var": "plan"}, ["pro", "enterprise"]]}]}]}`,
+			want:  true,
+		},
+		{
+			name:  "nested and/or not matching",
+			query: `{"and": [{"==": [{"var": "country"}, "FR"]}, {"or": [{">": [{"var": "age"}, 50]}, {"in": [{"var": "plan"}, ["free"]]}]}]}`,
+			want:  false,
+		},
+		{
+			name:  "not",
+			query: `{"!": {"==": [{"var": "country"}, "US"]}}`,
+			want:  true,
+		},
+		{
+			name:  "not with not alias",
+			query: `{"not": [{"==": [{"var": "country"}, "FR"]}]}`,
+			want:  false,
+		},
+		{
+			name:  "nested attribute",
+			query: `{"==": [{"var": "company.name"}, "GO Feature Flag"]}`,
+			want:  true,
+		},
+		{
+			name:  "missing attribute in an or subtree",
+			query: `{"or": [{">": [{"var": "missing.attribute"}, 10]}, {"==": [{"var": "country"}, "FR"]}]}`,
+			want:  true,
+		},
+		{
+			name:  "missing attribute in an and subtree",
+			query: `{"and": [{"==": [{"var": "country"}, "FR"]}, {"==": [{"var": "missing"}, "value"]}]}`,
+			want:  false,
+		},
+		{
+			name:  "missing attribute with not",
+			query: `{"!": {"==": [{"var": "missing"}, "value"]}}`,
+			want:  true,
+		},
+		{
+			name:  "missing attribute with default value",
+			query: `{"==": [{"var": ["missing", "default"]}, "default"]}`,
+			want:  true,
+		},
+		{
+			name:  "or short-circuits before an invalid subtree",
+			query: `{"or": [{"==": [{"var": "country"}, "FR"]}, {"!": [1, 2]}]}`,
+			want:  true,
+		},
+		{
+			name:  "and short-circuits before an invalid subtree",
+			query: `{"!": {"and": [{"==": [{"var": "country"}, "US"]}, {"!": [1, 2]}]}}`,
+			want:  true,
+		},
+		{
+			name:  "invalid subtree evaluated",
+			query: `{"and": [{"==": [{"var": "country"}, "FR"]}, {"!": [1, 2]}]}`,
+			want:  false,
+		},
+		{
+			name:  "between",
+			query: `{"<": [18, {"var": "age"}, 65]}`,
+			want:  true,
+		},
+		{
+			name:  "unsupported operator",
+			query: `{"regex": [{"var": "country"}, "FR"]}`,
+			want:  false,
+		},
+		{
+			name:  "invalid json",
+			query: `{"==": [{"var": "country"}, "FR"]`,
+			want:  false,
+		},
+		{
+			name:  "simple query syntax still supported",
+			query: `country eq "FR" and (age ge 40 or plan in ["pro", "enterprise"])`,
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := flag.Rule{
+				VariationResult: testconvert.String("variation_A"),
+				Query:           testconvert.String(tt.query),
+			}
+			user := ffcontext.NewEvaluationContextBuilder("abc").
+				AddCustom("country", "FR").
+				AddCustom("age", 42).
+				AddCustom("plan", "pro").
+				AddCustom("company", map[string]interface{}{"name": "GO Feature Flag"}).
+				Build()
+			got, err := rule.Evaluate(user, 0, false)
+			if tt.want {
+				assert.NoError(t, err)
+				assert.Equal(t, "variation_A", got)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRule_FractionalPercentageRamp(t *testing.T) {
+	newFlag := func(percentage float64) *flag.InternalFlag {
+		return &flag.InternalFlag{
+			Variations: &map[string]*interface{}{
+				"on":  testconvert.Interface(true),
+				"off": testconvert.Interface(false),
+			},
+			DefaultRule: &flag.Rule{
+				Percentages: &map[string]float64{"on": percentage, "off": 100 - percentage},
+			},
+		}
+	}
+
+	const nbUsers = 100000
+	previous := map[string]bool{}
+	for _, percentage := range []float64{0.1, 0.2, 0.3, 0.4, 0.5} {
+		f := newFlag(percentage)
+		assert.NoError(t, f.IsValid())
+
+		current := map[string]bool{}
+		for i := 0; i < nbUsers; i++ {
+			key := fmt.Sprintf("user-%d", i)
+			value, _ := f.Value("ramp-flag", ffcontext.NewEvaluationContext(key), flag.Context{})
+			if value == true {
+				current[key] = true
+			}
+		}
+		for key := range previous {
+			assert.True(t, current[key], "%s should stay on when ramping to %v%%", key, percentage)
+		}
+		assert.Greater(t, len(current), len(previous), "more users should be on at %v%%", percentage)
+		assert.InDelta(t, percentage*nbUsers/100, len(current), percentage*nbUsers/100*0.5)
+		previous = current
+	}
+}
+
+func TestRule_IsValidFractionalPercentages(t *testing.T) {
+	rule := flag.Rule{
+		Percentages: &map[string]float64{"A": 0.3, "B": 99.6, "C": 0.1},
+	}
+	assert.NoError(t, rule.IsValid(true), "fractional percentages summing to 100 should be valid")
+	assert.Equal(t, float64(100), flag.SumPercentages(rule.GetPercentages()))
+
+	rule = flag.Rule{
+		Percentages: &map[string]float64{"A": 0.3, "B": 99.6},
+	}
+	assert.Error(t, rule.IsValid(true))
+}
+
+// fixedHasher puts all the users in the same bucket.
+type fixedHasher uint32
+
+func (h fixedHasher) Hash(_ string) uint32 {
+	return uint32(h)
+}
+
+func TestPercentageBoundaries(t *testing.T) {
+	variations := &map[string]*interface{}{
+		"on":  testconvert.Interface(true),
+		"off": testconvert.Interface(false),
+	}
+	split := func(percentage float64) *flag.InternalFlag {
+		return &flag.InternalFlag{
+			Variations: variations,
+			DefaultRule: &flag.Rule{
+				Percentages: &map[string]float64{"on": percentage, "off": 100 - percentage},
+			},
+		}
+	}
+	progressive := func(percentage float64) *flag.InternalFlag {
+		return &flag.InternalFlag{
+			Variations: variations,
+			DefaultRule: &flag.Rule{
+				ProgressiveRollout: &flag.ProgressiveRollout{
+					Initial: &flag.ProgressiveRolloutStep{
+						Variation:  testconvert.String("off"),
+						Percentage: testconvert.Float64(0),
+						Date:       testconvert.Time(time.Now().Add(-10 * time.Second)),
+					},
+					End: &flag.ProgressiveRolloutStep{
+						Variation:  testconvert.String("on"),
+						Percentage: testconvert.Float64(percentage),
+						Date:       testconvert.Time(time.Now().Add(-1 * time.Second)),
+					},
+				},
+			},
+		}
+	}
+	holdout := func(percentage float64) *flag.InternalFlag {
+		f := split(100)
+		f.Holdout = &flag.Holdout{Percentage: percentage, Variation: testconvert.String("off")}
+		return f
+	}
+	layer := func(percentage float64) *flag.InternalFlag {
+		f := split(100)
+		f.Layer = &flag.Layer{ID: "layer", Start: 0, End: percentage}
+		return f
+	}
+
+	// 2.01 * PercentageMultiplier is 2009.9999999999998 in floating point.
+	tests := []struct {
+		name       string
+		newFlag    func(percentage float64) *flag.InternalFlag
+		percentage float64
+		lastBucket uint32
+	}{
+		{name: "split 4.35%", newFlag: split, percentage: 4.35, lastBucket: 4349},
+		{name: "split 0.1%", newFlag: split, percentage: 0.1, lastBucket: 99},
+		{name: "split 2.01%", newFlag: split, percentage: 2.01, lastBucket: 2009},
+		{name: "progressive rollout 4.35%", newFlag: progressive, percentage: 4.35, lastBucket: 4349},
+		{name: "progressive rollout 0.1%", newFlag: progressive, percentage: 0.1, lastBucket: 99},
+		{name: "progressive rollout 2.01%", newFlag: progressive, percentage: 2.01, lastBucket: 2009},
+		{name: "holdout 4.35%", newFlag: holdout, percentage: 4.35, lastBucket: 4349},
+		{name: "holdout 0.1%", newFlag: holdout, percentage: 0.1, lastBucket: 99},
+		{name: "holdout 2.01%", newFlag: holdout, percentage: 2.01, lastBucket: 2009},
+		{name: "layer 4.35%", newFlag: layer, percentage: 4.35, lastBucket: 4349},
+		{name: "layer 0.1%", newFlag: layer, percentage: 0.1, lastBucket: 99},
+		{name: "layer 2.01%", newFlag: layer, percentage: 2.01, lastBucket: 2009},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.newFlag(tt.percentage)
+			// result returns the variation and the reason of the evaluation for a user in this bucket.
+			result := func(bucket uint32) string {
+				_, details := f.Value("boundary-flag", ffcontext.NewEvaluationContext("user-key"),
+					flag.Context{Hasher: fixedHasher(bucket)})
+				return details.Variant + "/" + details.Reason
+			}
+			assert.Equal(t, result(tt.lastBucket-1), result(tt.lastBucket))
+			assert.NotEqual(t, result(tt.lastBucket), result(tt.lastBucket+1),
+				"the bucket %d should be the last one of the %v%%", tt.lastBucket, tt.percentage)
+			assert.Equal(t, result(tt.lastBucket+1), result(tt.lastBucket+2))
+		})
+	}
+}
@@ -17,4 +17,7 @@ type variationSelection struct {
 
 	// cacheable is set to true if a provider/SDK can cache the value
 	cacheable bool
+
+	// progressiveRollout (optional) is the state of the progressive rollout of the rule which applied
+	progressiveRollout *ProgressiveRolloutDetails
 }
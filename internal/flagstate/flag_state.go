@@ -14,4 +14,5 @@ type FlagState struct {
 	ErrorCode     flag.ErrorCode         `json:"errorCode"`
 	Reason        flag.ResolutionReason  `json:"reason"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Version       string                 `json:"version,omitempty"`
 }
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"encoding/json"
+)
+
+// UnmarshalFlagsJSON5 decodes a JSON flag file that can contain comments (// and /* */)
+// and trailing commas, as allowed by JSON5 and JSONC.
+// The comments and trailing commas are removed and the content is decoded as strict JSON,
+// so the result is the same as with a JSON file without comments.
+func UnmarshalFlagsJSON5(content []byte, out interface{}) error {
+	return json.Unmarshal(removeTrailingCommas(removeJSONComments(content)), out)
+}
+
+// removeJSONComments replaces the comments outside the strings with spaces.
+// The line breaks are kept so the positions in the parsing errors are still meaningful.
+func removeJSONComments(content []byte) []byte {
+	res := make([]byte, 0, len(content))
+	inString := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case inString:
+			res = append(res, c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				res = append(res, content[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			res = append(res, c)
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				res = append(res, ' ')
+				i++
+			}
+			if i < len(content) {
+				res = append(res, '\n')
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			res = append(res, ' ', ' ')
+			i += 2
+			for i < len(content) && !(content[i] == '*' && i+1 < len(content) && content[i+1] == '/') {
+				if content[i] == '\n' {
+					res = append(res, '\n')
+				} else {
+					res = append(res, ' ')
+				}
+				i++
+			}
+			if i < len(content) {
+				// skip the closing "*/"
+				res = append(res, ' ', ' ')
+				i++
+			}
+		default:
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// removeTrailingCommas removes the commas followed by the end of an object or an array.
+// The content must not contain comments anymore.
+func removeTrailingCommas(content []byte) []byte {
+	res := make([]byte, 0, len(content))
+	inString := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case inString:
+			res = append(res, c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				res = append(res, content[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			res = append(res, c)
+		case c == ',' && isFollowedByClosingBracket(content[i+1:]):
+			res = append(res, ' ')
+		default:
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// isFollowedByClosingBracket returns true if the first non whitespace character is } or ].
+func isFollowedByClosingBracket(content []byte) bool {
+	for _, c := range content {
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '}', ']':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
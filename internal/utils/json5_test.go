@@ -0,0 +1,61 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+func TestUnmarshalFlagsJSON5(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]interface{}
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "strict JSON",
+			content: `{"flag-a": {"disable": true}}`,
+			want:    map[string]interface{}{"flag-a": map[string]interface{}{"disable": true}},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "comments and trailing commas",
+			content: `{
+  // line comment
+  "flag-a": {
+    /* block
+       comment */
+    "disable": true, // end of line comment
+    "tags": ["a", "b",],
+  },
+}`,
+			want: map[string]interface{}{
+				"flag-a": map[string]interface{}{"disable": true, "tags": []interface{}{"a", "b"}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "comment characters in strings are kept",
+			content: `{"url": "https://example.com/*path*/", "query": "a eq \"//,}\"",}`,
+			want:    map[string]interface{}{"url": "https://example.com/*path*/", "query": `a eq "//,}"`},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid JSON",
+			content: `{"flag-a": }`,
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			err := utils.UnmarshalFlagsJSON5([]byte(tt.content), &got)
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import "gopkg.in/yaml.v3"
+
+// YAMLFragmentsKey is the reserved top-level key of a YAML flag file that is not a flag.
+// It is used to define fragments shared by several flags with anchors and aliases, ex:
+//
+//	x-fragments:
+//	  beta-users: &beta-users
+//	    query: beta eq true
+//	    variation: enabled
+//	my-flag:
+//	  targeting:
+//	    - *beta-users
+const YAMLFragmentsKey = "x-fragments"
+
+// UnmarshalFlagsYAML decodes a YAML flag file, the anchors and aliases (including the merge keys "<<")
+// are expanded and the top-level key YAMLFragmentsKey is ignored.
+func UnmarshalFlagsYAML(content []byte, out interface{}) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return err
+	}
+	if node.Kind == 0 {
+		// empty document
+		return nil
+	}
+	RemoveYAMLFragments(&node)
+	return node.Decode(out)
+}
+
+// RemoveYAMLFragments removes the top-level key YAMLFragmentsKey from the document.
+// The aliases referencing the fragments are still resolved since they point to the nodes directly.
+func RemoveYAMLFragments(node *yaml.Node) {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return
+		}
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == YAMLFragmentsKey {
+			continue
+		}
+		content = append(content, node.Content[i], node.Content[i+1])
+	}
+	node.Content = content
+}
@@ -0,0 +1,66 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+func TestUnmarshalFlagsYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]interface{}
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "fragments are removed and aliases expanded",
+			content: `x-fragments:
+  shared: &shared
+    variation: A
+flag-a:
+  defaultRule: *shared
+flag-b:
+  <<: *shared
+  disable: true
+`,
+			want: map[string]interface{}{
+				"flag-a": map[string]interface{}{"defaultRule": map[string]interface{}{"variation": "A"}},
+				"flag-b": map[string]interface{}{"variation": "A", "disable": true},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "keys starting with a dot are flags",
+			content: `.flag-a:
+  defaultRule:
+    variation: A
+`,
+			want: map[string]interface{}{
+				".flag-a": map[string]interface{}{"defaultRule": map[string]interface{}{"variation": "A"}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "empty document",
+			content: "",
+			want:    nil,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid yaml",
+			content: "flag-a: [",
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			err := utils.UnmarshalFlagsYAML([]byte(tt.content), &got)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
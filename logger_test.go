@@ -0,0 +1,81 @@
+package ffclient_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records returns the JSON log records written in the buffer.
+func (b *syncBuffer) records(t *testing.T) []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records := make([]map[string]interface{}, 0)
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLeveledLogger(t *testing.T) {
+	logs := &syncBuffer{}
+	r := &switchRetriever{}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       r,
+		LeveledLogger:   slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+
+	// the components using a *log.Logger are logging in the leveled logger
+	assert.Eventually(t, func() bool {
+		for _, record := range logs.records(t) {
+			if record["level"] == "INFO" && record["msg"] == "flag test-flag added" {
+				return true
+			}
+		}
+		return false
+	}, 3*time.Second, 50*time.Millisecond)
+
+	// a retriever failure is logged at error level with the error as attribute
+	r.failing.Store(true)
+	var errorRecord map[string]interface{}
+	assert.Eventually(t, func() bool {
+		for _, record := range logs.records(t) {
+			if record["level"] == "ERROR" {
+				errorRecord = record
+				return true
+			}
+		}
+		return false
+	}, 3*time.Second, 50*time.Millisecond)
+	require.NotNil(t, errorRecord)
+	assert.Equal(t, "error while updating the cache", errorRecord["msg"])
+	assert.Equal(t, "retriever unavailable", errorRecord["error"])
+}
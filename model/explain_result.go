@@ -0,0 +1,16 @@
+package model
+
+import "github.com/thomaspoignant/go-feature-flag/internal/flag"
+
+// ExplainResult contains the result of the evaluation of a flag and the trace of each rule considered.
+type ExplainResult struct {
+	FlagKey       string                `json:"flagKey"`
+	Value         interface{}           `json:"value"`
+	VariationType string                `json:"variationType"`
+	Reason        flag.ResolutionReason `json:"reason"`
+	ErrorCode     flag.ErrorCode        `json:"errorCode"`
+	// RuleIndex is the index of the rule used to select the variation, nil if no targeting rule has been used.
+	RuleIndex *int `json:"ruleIndex,omitempty"`
+	// Rules is the trace of each rule of the flag, the default rule is the last element.
+	Rules []flag.RuleTrace `json:"rules"`
+}
@@ -18,6 +18,11 @@ type VariationResult[T JSONType] struct {
 	Value         T                      `json:"value"`
 	Cacheable     bool                   `json:"cacheable"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	RuleIndex     *int                   `json:"ruleIndex,omitempty"`
+	// ProgressiveRollout is the state of the progressive rollout, if the rule which applied has one.
+	ProgressiveRollout *flag.ProgressiveRolloutDetails `json:"progressiveRollout,omitempty"`
+	// VariationMetadata is the metadata of the variation selected.
+	VariationMetadata map[string]interface{} `json:"variationMetadata,omitempty"`
 }
 
 // RawVarResult is the result of the raw variation call.
@@ -32,4 +37,9 @@ type RawVarResult struct {
 	Value         interface{}            `json:"value"`
 	Cacheable     bool                   `json:"cacheable"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	RuleIndex     *int                   `json:"ruleIndex,omitempty"`
+	// ProgressiveRollout is the state of the progressive rollout, if the rule which applied has one.
+	ProgressiveRollout *flag.ProgressiveRolloutDetails `json:"progressiveRollout,omitempty"`
+	// VariationMetadata is the metadata of the variation selected.
+	VariationMetadata map[string]interface{} `json:"variationMetadata,omitempty"`
 }
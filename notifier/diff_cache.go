@@ -21,3 +21,54 @@ type DiffUpdated struct {
 	Before flag.Flag `json:"old_value"`
 	After  flag.Flag `json:"new_value"`
 }
+
+// Merge returns the differences between the state before d and the state after next,
+// next being the differences that happened after d.
+// A flag added and then deleted is not part of the result, a flag deleted and then added again is updated.
+func (d *DiffCache) Merge(next DiffCache) DiffCache {
+	merged := DiffCache{
+		Deleted: map[string]flag.Flag{},
+		Added:   map[string]flag.Flag{},
+		Updated: map[string]DiffUpdated{},
+	}
+	for key, f := range d.Deleted {
+		merged.Deleted[key] = f
+	}
+	for key, f := range d.Added {
+		merged.Added[key] = f
+	}
+	for key, u := range d.Updated {
+		merged.Updated[key] = u
+	}
+
+	for key, f := range next.Added {
+		if before, ok := merged.Deleted[key]; ok {
+			delete(merged.Deleted, key)
+			merged.Updated[key] = DiffUpdated{Before: before, After: f}
+			continue
+		}
+		merged.Added[key] = f
+	}
+	for key, u := range next.Updated {
+		switch previous, updated := merged.Updated[key]; {
+		case updated:
+			merged.Updated[key] = DiffUpdated{Before: previous.Before, After: u.After}
+		case merged.Added[key] != nil:
+			merged.Added[key] = u.After
+		default:
+			merged.Updated[key] = u
+		}
+	}
+	for key, f := range next.Deleted {
+		if _, ok := merged.Added[key]; ok {
+			delete(merged.Added, key)
+			continue
+		}
+		if previous, ok := merged.Updated[key]; ok {
+			delete(merged.Updated, key)
+			f = previous.Before
+		}
+		merged.Deleted[key] = f
+	}
+	return merged
+}
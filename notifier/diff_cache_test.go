@@ -204,3 +204,85 @@ func TestDiffCache_HasDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffCache_Merge(t *testing.T) {
+	flagA := &flag.InternalFlag{Version: testconvert.String("A")}
+	flagB := &flag.InternalFlag{Version: testconvert.String("B")}
+	flagC := &flag.InternalFlag{Version: testconvert.String("C")}
+	empty := func() notifier.DiffCache {
+		return notifier.DiffCache{
+			Deleted: map[string]flag.Flag{},
+			Added:   map[string]flag.Flag{},
+			Updated: map[string]notifier.DiffUpdated{},
+		}
+	}
+	tests := []struct {
+		name  string
+		first notifier.DiffCache
+		next  notifier.DiffCache
+		want  func() notifier.DiffCache
+	}{
+		{
+			name:  "different flags",
+			first: notifier.DiffCache{Added: map[string]flag.Flag{"flag-1": flagA}},
+			next:  notifier.DiffCache{Deleted: map[string]flag.Flag{"flag-2": flagB}},
+			want: func() notifier.DiffCache {
+				d := empty()
+				d.Added["flag-1"] = flagA
+				d.Deleted["flag-2"] = flagB
+				return d
+			},
+		},
+		{
+			name:  "added then updated",
+			first: notifier.DiffCache{Added: map[string]flag.Flag{"flag-1": flagA}},
+			next:  notifier.DiffCache{Updated: map[string]notifier.DiffUpdated{"flag-1": {Before: flagA, After: flagB}}},
+			want: func() notifier.DiffCache {
+				d := empty()
+				d.Added["flag-1"] = flagB
+				return d
+			},
+		},
+		{
+			name:  "added then deleted",
+			first: notifier.DiffCache{Added: map[string]flag.Flag{"flag-1": flagA}},
+			next:  notifier.DiffCache{Deleted: map[string]flag.Flag{"flag-1": flagA}},
+			want:  empty,
+		},
+		{
+			name:  "updated twice",
+			first: notifier.DiffCache{Updated: map[string]notifier.DiffUpdated{"flag-1": {Before: flagA, After: flagB}}},
+			next:  notifier.DiffCache{Updated: map[string]notifier.DiffUpdated{"flag-1": {Before: flagB, After: flagC}}},
+			want: func() notifier.DiffCache {
+				d := empty()
+				d.Updated["flag-1"] = notifier.DiffUpdated{Before: flagA, After: flagC}
+				return d
+			},
+		},
+		{
+			name:  "updated then deleted",
+			first: notifier.DiffCache{Updated: map[string]notifier.DiffUpdated{"flag-1": {Before: flagA, After: flagB}}},
+			next:  notifier.DiffCache{Deleted: map[string]flag.Flag{"flag-1": flagB}},
+			want: func() notifier.DiffCache {
+				d := empty()
+				d.Deleted["flag-1"] = flagA
+				return d
+			},
+		},
+		{
+			name:  "deleted then added",
+			first: notifier.DiffCache{Deleted: map[string]flag.Flag{"flag-1": flagA}},
+			next:  notifier.DiffCache{Added: map[string]flag.Flag{"flag-1": flagB}},
+			want: func() notifier.DiffCache {
+				d := empty()
+				d.Updated["flag-1"] = notifier.DiffUpdated{Before: flagA, After: flagB}
+				return d
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want(), tt.first.Merge(tt.next))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package grpcnotifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/thomaspoignant/go-feature-flag/notifier"
+	"github.com/thomaspoignant/go-feature-flag/notifier/grpcnotifier/grpcnotifierpb"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Notifier calls the Notify RPC of a gRPC server implementing the FlagChangeService
+// (see grpcnotifierpb/notifier.proto) every time the flag configuration has changed.
+//
+// The connection to the server is reused across the notifications, if a call fails
+// the connection is closed and a new one is dialed.
+type Notifier struct {
+	// Target is the address of your gRPC server (ex: "localhost:50051", "dns:///notifier.internal:443").
+	Target string
+
+	// DialOptions (optional) are the options used to create the gRPC connection.
+	// Default: an insecure connection (grpc.WithTransportCredentials(insecure.NewCredentials()))
+	DialOptions []grpc.DialOption
+
+	// Meta (optional) information that you want to send to your server.
+	// Default: the hostname of the machine in the key "hostname"
+	Meta map[string]string
+
+	// Timeout (optional) is the maximum duration of a call to the server.
+	// Default: 10 seconds
+	Timeout time.Duration
+
+	conn   *grpc.ClientConn
+	client grpcnotifierpb.FlagChangeServiceClient
+	mutex  sync.Mutex
+	init   sync.Once
+}
+
+// Notify sends the keys of the flags that have changed to the gRPC server.
+func (c *Notifier) Notify(diff notifier.DiffCache) error {
+	if c.Target == "" {
+		return fmt.Errorf("invalid notifier configuration, no target provided for the gRPC notifier")
+	}
+
+	c.init.Do(func() {
+		if c.Meta == nil {
+			c.Meta = make(map[string]string)
+		}
+		// if no hostname provided we return the hostname of the current machine
+		if _, ok := c.Meta["hostname"]; !ok {
+			hostname, _ := os.Hostname()
+			c.Meta["hostname"] = hostname
+		}
+	})
+
+	change := &grpcnotifierpb.FlagChange{
+		Added:   sortedKeys(diff.Added),
+		Deleted: sortedKeys(diff.Deleted),
+		Updated: sortedKeys(diff.Updated),
+		Meta:    c.Meta,
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	err := c.send(change)
+	if err != nil {
+		// the connection may be broken, we dial a new one and retry once.
+		_ = c.closeConnection()
+		err = c.send(change)
+	}
+	if err != nil {
+		_ = c.closeConnection()
+		return fmt.Errorf("error: (gRPC Notifier) impossible to notify %s: %v", c.Target, err)
+	}
+	return nil
+}
+
+// Close closes the connection to the gRPC server.
+func (c *Notifier) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.closeConnection()
+}
+
+// send calls the Notify RPC, the connection is created if needed.
+func (c *Notifier) send(change *grpcnotifierpb.FlagChange) error {
+	if c.conn == nil {
+		dialOptions := c.DialOptions
+		if len(dialOptions) == 0 {
+			dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		}
+		conn, err := grpc.NewClient(c.Target, dialOptions...)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+		c.client = grpcnotifierpb.NewFlagChangeServiceClient(conn)
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err := c.client.Notify(ctx, change)
+	return err
+}
+
+// closeConnection closes the current connection, the next call will dial a new one.
+func (c *Notifier) closeConnection() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.client = nil
+	return err
+}
+
+// sortedKeys returns the keys of the map in alphabetical order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package grpcnotifier_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/notifier"
+	"github.com/thomaspoignant/go-feature-flag/notifier/grpcnotifier"
+	"github.com/thomaspoignant/go-feature-flag/notifier/grpcnotifier/grpcnotifierpb"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type flagChangeServer struct {
+	grpcnotifierpb.UnimplementedFlagChangeServiceServer
+	mutex    sync.Mutex
+	received []*grpcnotifierpb.FlagChange
+}
+
+func (s *flagChangeServer) Notify(
+	_ context.Context, change *grpcnotifierpb.FlagChange) (*grpcnotifierpb.NotifyResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.received = append(s.received, change)
+	return &grpcnotifierpb.NotifyResponse{}, nil
+}
+
+// startServer starts an in-process gRPC server listening on an in-memory connection.
+func startServer(t *testing.T) (*flagChangeServer, *grpc.Server, *bufconn.Listener) {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := &flagChangeServer{}
+	grpcServer := grpc.NewServer()
+	grpcnotifierpb.RegisterFlagChangeServiceServer(grpcServer, srv)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+	return srv, grpcServer, listener
+}
+
+func dialerOptions(getListener func() *bufconn.Listener, dials *atomic.Int32) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			dials.Add(1)
+			return getListener().DialContext(ctx)
+		}),
+	}
+}
+
+func testDiff() notifier.DiffCache {
+	return notifier.DiffCache{
+		Added: map[string]flag.Flag{
+			"flag-b": &flag.InternalFlag{},
+			"flag-a": &flag.InternalFlag{},
+		},
+		Deleted: map[string]flag.Flag{
+			"flag-c": &flag.InternalFlag{},
+		},
+		Updated: map[string]notifier.DiffUpdated{
+			"flag-d": {
+				Before: &flag.InternalFlag{Disable: testconvert.Bool(false)},
+				After:  &flag.InternalFlag{Disable: testconvert.Bool(true)},
+			},
+		},
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	srv, _, listener := startServer(t)
+	var dials atomic.Int32
+	n := &grpcnotifier.Notifier{
+		Target:      "passthrough:///bufnet",
+		DialOptions: dialerOptions(func() *bufconn.Listener { return listener }, &dials),
+		Meta:        map[string]string{"app.name": "my app"},
+	}
+	defer func() { _ = n.Close() }()
+
+	require.NoError(t, n.Notify(testDiff()))
+	require.NoError(t, n.Notify(notifier.DiffCache{}))
+
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	require.Len(t, srv.received, 2)
+	assert.Equal(t, []string{"flag-a", "flag-b"}, srv.received[0].GetAdded())
+	assert.Equal(t, []string{"flag-c"}, srv.received[0].GetDeleted())
+	assert.Equal(t, []string{"flag-d"}, srv.received[0].GetUpdated())
+	assert.Equal(t, "my app", srv.received[0].GetMeta()["app.name"])
+	assert.Contains(t, srv.received[0].GetMeta(), "hostname")
+	assert.Empty(t, srv.received[1].GetAdded())
+	assert.Equal(t, int32(1), dials.Load(), "the connection should be reused across notifications")
+}
+
+func TestNotifier_NotifyRedialAfterFailure(t *testing.T) {
+	_, firstServer, firstListener := startServer(t)
+	listener := firstListener
+	var mutex sync.Mutex
+	var dials atomic.Int32
+	n := &grpcnotifier.Notifier{
+		Target: "passthrough:///bufnet",
+		DialOptions: dialerOptions(func() *bufconn.Listener {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return listener
+		}, &dials),
+	}
+	defer func() { _ = n.Close() }()
+	require.NoError(t, n.Notify(testDiff()))
+
+	// the server is restarted, the existing connection is not usable anymore.
+	firstServer.Stop()
+	secondSrv, _, secondListener := startServer(t)
+	mutex.Lock()
+	listener = secondListener
+	mutex.Unlock()
+
+	require.NoError(t, n.Notify(testDiff()))
+	secondSrv.mutex.Lock()
+	defer secondSrv.mutex.Unlock()
+	assert.Len(t, secondSrv.received, 1)
+	assert.Equal(t, int32(2), dials.Load())
+}
+
+func TestNotifier_NotifyError(t *testing.T) {
+	t.Run("no target", func(t *testing.T) {
+		n := &grpcnotifier.Notifier{}
+		err := n.Notify(testDiff())
+		assert.EqualError(t, err, "invalid notifier configuration, no target provided for the gRPC notifier")
+	})
+
+	t.Run("server unavailable", func(t *testing.T) {
+		_, grpcServer, listener := startServer(t)
+		grpcServer.Stop()
+		var dials atomic.Int32
+		n := &grpcnotifier.Notifier{
+			Target:      "passthrough:///bufnet",
+			DialOptions: dialerOptions(func() *bufconn.Listener { return listener }, &dials),
+		}
+		defer func() { _ = n.Close() }()
+		err := n.Notify(testDiff())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error: (gRPC Notifier) impossible to notify passthrough:///bufnet")
+	})
+}
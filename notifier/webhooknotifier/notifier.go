@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/notifier"
 
@@ -80,14 +81,63 @@ type Notifier struct {
 	// Headers (optional) the list of Headers to send to the endpoint
 	Headers map[string][]string
 
+	// MinInterval (optional) is the minimum time between 2 calls to the webhook.
+	// The differences received before the end of this interval are merged and sent
+	// in a single request when the interval is over.
+	// Default: 0, the webhook is called for every difference.
+	MinInterval time.Duration
+
 	httpClient internal.HTTPClient
 	init       sync.Once
+
+	// rateMutex protects the fields used to respect the MinInterval.
+	rateMutex sync.Mutex
+	lastSent  time.Time
+	// pending is the difference waiting for the end of the MinInterval, nil if nothing is waiting.
+	pending *notifier.DiffCache
 }
 
 func (c *Notifier) Notify(diff notifier.DiffCache) error {
 	if c.EndpointURL == "" {
 		return fmt.Errorf("invalid notifier configuration, no endpointURL provided for the webhook notifier")
 	}
+	if c.MinInterval <= 0 {
+		return c.send(diff)
+	}
+
+	c.rateMutex.Lock()
+	if c.pending != nil {
+		// a call is already waiting for the end of the interval, the difference is sent with it.
+		merged := c.pending.Merge(diff)
+		c.pending = &merged
+		c.rateMutex.Unlock()
+		return nil
+	}
+	wait := time.Until(c.lastSent.Add(c.MinInterval))
+	if wait <= 0 {
+		c.lastSent = time.Now()
+		c.rateMutex.Unlock()
+		return c.send(diff)
+	}
+	c.pending = &diff
+	c.rateMutex.Unlock()
+
+	time.Sleep(wait)
+
+	c.rateMutex.Lock()
+	merged := *c.pending
+	c.pending = nil
+	c.lastSent = time.Now()
+	c.rateMutex.Unlock()
+	if !merged.HasDiff() {
+		// the changes cancelled each other (ex: a flag added and deleted).
+		return nil
+	}
+	return c.send(merged)
+}
+
+// send calls the webhook with the difference.
+func (c *Notifier) send(diff notifier.DiffCache) error {
 
 	// init the notifier
 	c.init.Do(func() {
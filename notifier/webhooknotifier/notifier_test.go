@@ -3,9 +3,11 @@ package webhooknotifier
 import (
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
@@ -317,3 +319,61 @@ func Test_webhookNotifier_no_meta_data(t *testing.T) {
 
 	assert.NotEmpty(t, m["meta"])
 }
+
+func Test_webhookNotifier_MinInterval(t *testing.T) {
+	var mutex sync.Mutex
+	// addedFlags contains the keys of the flags added for each request received.
+	var addedFlags [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Flags struct {
+				Added map[string]json.RawMessage `json:"added"`
+			} `json:"flags"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		keys := make([]string, 0, len(body.Flags.Added))
+		for key := range body.Flags.Added {
+			keys = append(keys, key)
+		}
+		mutex.Lock()
+		addedFlags = append(addedFlags, keys)
+		mutex.Unlock()
+	}))
+	defer srv.Close()
+
+	c := Notifier{
+		EndpointURL: srv.URL,
+		MinInterval: 200 * time.Millisecond,
+		httpClient:  &http.Client{},
+	}
+	diffAdding := func(key string) notifier.DiffCache {
+		return notifier.DiffCache{
+			Added:   map[string]flag.Flag{key: &flag.InternalFlag{}},
+			Deleted: map[string]flag.Flag{},
+			Updated: map[string]notifier.DiffUpdated{},
+		}
+	}
+
+	// the first difference is sent directly.
+	start := time.Now()
+	assert.NoError(t, c.Notify(diffAdding("flag-0")))
+
+	// 3 differences during the interval are merged in a single request.
+	var wg sync.WaitGroup
+	for _, key := range []string{"flag-1", "flag-2", "flag-3"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			assert.NoError(t, c.Notify(diffAdding(key)))
+		}(key)
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	mutex.Lock()
+	defer mutex.Unlock()
+	if assert.Len(t, addedFlags, 2) {
+		assert.Equal(t, []string{"flag-0"}, addedFlags[0])
+		assert.ElementsMatch(t, []string{"flag-1", "flag-2", "flag-3"}, addedFlags[1])
+	}
+}
@@ -0,0 +1,95 @@
+package ffclient
+
+import (
+	"sync"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+)
+
+// overrideStore contains the variations pinned for a targeting key, it is safe for concurrent use.
+type overrideStore struct {
+	mutex sync.RWMutex
+	// overrides is the variation pinned by flag key and then by targeting key.
+	overrides map[string]map[string]string
+}
+
+func (o *overrideStore) set(flagKey string, targetingKey string, variation string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.overrides == nil {
+		o.overrides = make(map[string]map[string]string)
+	}
+	if o.overrides[flagKey] == nil {
+		o.overrides[flagKey] = make(map[string]string)
+	}
+	o.overrides[flagKey][targetingKey] = variation
+}
+
+func (o *overrideStore) clear(flagKey string, targetingKey string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	delete(o.overrides[flagKey], targetingKey)
+	if len(o.overrides[flagKey]) == 0 {
+		delete(o.overrides, flagKey)
+	}
+}
+
+func (o *overrideStore) get(flagKey string, targetingKey string) (string, bool) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	variation, ok := o.overrides[flagKey][targetingKey]
+	return variation, ok
+}
+
+// evaluate returns the value of the variation pinned for the evaluation context.
+// It returns false if there is no override or if the variation does not exist in the flag anymore.
+func (o *overrideStore) evaluate(flagKey string, f flag.Flag, evaluationCtx ffcontext.Context,
+) (interface{}, flag.ResolutionDetails, bool) {
+	if evaluationCtx == nil {
+		return nil, flag.ResolutionDetails{}, false
+	}
+	variation, ok := o.get(flagKey, evaluationCtx.GetKey())
+	if !ok {
+		return nil, flag.ResolutionDetails{}, false
+	}
+	value := f.GetVariationValue(variation)
+	if value == nil {
+		return nil, flag.ResolutionDetails{}, false
+	}
+	return value, flag.ResolutionDetails{
+		Variant:   variation,
+		Reason:    flag.ReasonOverride,
+		Cacheable: false,
+		Metadata:  f.GetMetadata(),
+	}, true
+}
+
+// SetOverride pins the variation of a flag for a targeting key, the override is consulted before
+// evaluating the flag and the variation is served with the reason OVERRIDE.
+// It is useful to force a user in a specific variation for debugging purpose, the overrides are kept
+// in memory only and are not shared with the other instances.
+// If the variation does not exist in the flag, the flag is evaluated normally.
+func (g *GoFeatureFlag) SetOverride(flagKey string, targetingKey string, variation string) {
+	if g != nil {
+		g.overrides.set(flagKey, targetingKey, variation)
+	}
+}
+
+// ClearOverride removes the variation pinned for a flag and a targeting key.
+func (g *GoFeatureFlag) ClearOverride(flagKey string, targetingKey string) {
+	if g != nil {
+		g.overrides.clear(flagKey, targetingKey)
+	}
+}
+
+// SetOverride pins the variation of a flag for a targeting key, the variation is served with the reason OVERRIDE.
+// The overrides are kept in memory only.
+func SetOverride(flagKey string, targetingKey string, variation string) {
+	ff.SetOverride(flagKey, targetingKey, variation)
+}
+
+// ClearOverride removes the variation pinned for a flag and a targeting key.
+func ClearOverride(flagKey string, targetingKey string) {
+	ff.ClearOverride(flagKey, targetingKey)
+}
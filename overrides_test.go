@@ -0,0 +1,53 @@
+package ffclient_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+)
+
+func TestOverrides(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+
+	pinnedUser := ffcontext.NewEvaluationContext("pinned-user")
+	otherUser := ffcontext.NewEvaluationContext("other-user")
+	gffClient.SetOverride("test-flag", "pinned-user", "True")
+
+	got, err := gffClient.BoolVariationDetails("test-flag", pinnedUser, false)
+	assert.NoError(t, err)
+	assert.True(t, got.Value)
+	assert.Equal(t, "True", got.VariationType)
+	assert.Equal(t, flag.ReasonOverride, got.Reason)
+	allFlags := gffClient.AllFlagsState(pinnedUser)
+	assert.Equal(t, flag.ReasonOverride, allFlags.GetFlags()["test-flag"].Reason)
+
+	// the other users are evaluated normally
+	got, err = gffClient.BoolVariationDetails("test-flag", otherUser, true)
+	assert.NoError(t, err)
+	assert.False(t, got.Value)
+	assert.Equal(t, "Default", got.VariationType)
+	assert.Equal(t, flag.ReasonDefault, got.Reason)
+
+	// an unknown variation is ignored
+	gffClient.SetOverride("test-flag", "other-user", "unknown-variation")
+	got, err = gffClient.BoolVariationDetails("test-flag", otherUser, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Default", got.VariationType)
+
+	gffClient.ClearOverride("test-flag", "pinned-user")
+	got, err = gffClient.BoolVariationDetails("test-flag", pinnedUser, true)
+	assert.NoError(t, err)
+	assert.False(t, got.Value)
+	assert.Equal(t, flag.ReasonDefault, got.Reason)
+}
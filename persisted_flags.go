@@ -0,0 +1,53 @@
+package ffclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+	"github.com/thomaspoignant/go-feature-flag/internal/dto"
+)
+
+// persistFlags saves the flags in a JSON file, the file is written in a temporary file first
+// and renamed to never leave a partial snapshot on the disk.
+// If the file already contains these flags, it is not written again.
+func persistFlags(path string, flags map[string]dto.DTO) error {
+	content, err := json.Marshal(flags)
+	if err != nil {
+		return err
+	}
+	if previous, err := os.ReadFile(path); err == nil && bytes.Equal(previous, content) {
+		return nil
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// loadPersistedFlags loads the flags saved by persistFlags in the cache.
+func loadPersistedFlags(path string, cacheManager cache.Manager, config Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("impossible to read the persisted flags: %v", err)
+	}
+	flags, err := cacheManager.ConvertToFlagStruct(content, "json")
+	if err != nil {
+		return fmt.Errorf("impossible to parse the persisted flags: %v", err)
+	}
+	return cacheManager.UpdateCache(flags, config.internalLogger().GetLogLogger())
+}
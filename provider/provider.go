@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+	"github.com/thomaspoignant/go-feature-flag/model"
+)
+
+// providerName is the name of the provider returned in the metadata.
+const providerName = "GO Feature Flag In Process Provider"
+
+// Provider is an OpenFeature provider evaluating the flags in process with a GoFeatureFlag instance,
+// you don't need a relay proxy to use it.
+type Provider struct {
+	client *ffclient.GoFeatureFlag
+}
+
+// NewProvider creates a new OpenFeature provider using the GoFeatureFlag instance to evaluate the flags.
+// The GoFeatureFlag instance is not closed by the provider, you are responsible for closing it.
+func NewProvider(client *ffclient.GoFeatureFlag) *Provider {
+	return &Provider{client: client}
+}
+
+// Metadata returns the metadata of the provider.
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: providerName}
+}
+
+// Hooks returns the hooks of the provider, there is none.
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+// BooleanEvaluation evaluates a boolean flag.
+func (p *Provider) BooleanEvaluation(_ context.Context, flagKey string, defaultValue bool,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.BoolResolutionDetail {
+	res, err := p.client.BoolVariationDetails(flagKey, toEvaluationContext(evalCtx), defaultValue)
+	return openfeature.BoolResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: toProviderResolutionDetail(res, err),
+	}
+}
+
+// StringEvaluation evaluates a string flag.
+func (p *Provider) StringEvaluation(_ context.Context, flagKey string, defaultValue string,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	res, err := p.client.StringVariationDetails(flagKey, toEvaluationContext(evalCtx), defaultValue)
+	return openfeature.StringResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: toProviderResolutionDetail(res, err),
+	}
+}
+
+// FloatEvaluation evaluates a float64 flag.
+func (p *Provider) FloatEvaluation(_ context.Context, flagKey string, defaultValue float64,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.FloatResolutionDetail {
+	res, err := p.client.Float64VariationDetails(flagKey, toEvaluationContext(evalCtx), defaultValue)
+	return openfeature.FloatResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: toProviderResolutionDetail(res, err),
+	}
+}
+
+// IntEvaluation evaluates an int flag.
+func (p *Provider) IntEvaluation(_ context.Context, flagKey string, defaultValue int64,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.IntResolutionDetail {
+	res, err := p.client.IntVariationDetails(flagKey, toEvaluationContext(evalCtx), int(defaultValue))
+	return openfeature.IntResolutionDetail{
+		Value:                    int64(res.Value),
+		ProviderResolutionDetail: toProviderResolutionDetail(res, err),
+	}
+}
+
+// ObjectEvaluation evaluates a flag containing a structured value (JSON object or array).
+func (p *Provider) ObjectEvaluation(_ context.Context, flagKey string, defaultValue interface{},
+	evalCtx openfeature.FlattenedContext,
+) openfeature.InterfaceResolutionDetail {
+	res, err := p.client.ObjectVariationDetails(flagKey, toEvaluationContext(evalCtx), defaultValue)
+	return openfeature.InterfaceResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: toProviderResolutionDetail(res, err),
+	}
+}
+
+// toEvaluationContext converts the OpenFeature context into a GO Feature Flag evaluation context,
+// the targeting key is used as the key of the evaluation context and the other fields are custom attributes.
+func toEvaluationContext(evalCtx openfeature.FlattenedContext) ffcontext.Context {
+	custom := make(map[string]interface{}, len(evalCtx))
+	targetingKey := ""
+	for key, value := range evalCtx {
+		if key == openfeature.TargetingKey {
+			if stringValue, ok := value.(string); ok {
+				targetingKey = stringValue
+				continue
+			}
+		}
+		custom[key] = value
+	}
+	return utils.ConvertEvaluationCtxFromRequest(targetingKey, custom)
+}
+
+// toProviderResolutionDetail converts the result of an evaluation into an OpenFeature resolution detail.
+// The reasons of GO Feature Flag are following the OpenFeature specification, so they are used as is.
+// The error codes are mapped to the OpenFeature error codes, the error codes which are not part of
+// the specification (ex: FLAG_CONFIG) are returned as GENERAL.
+func toProviderResolutionDetail[T model.JSONType](
+	res model.VariationResult[T], err error,
+) openfeature.ProviderResolutionDetail {
+	detail := openfeature.ProviderResolutionDetail{
+		Reason:       openfeature.Reason(res.Reason),
+		Variant:      res.VariationType,
+		FlagMetadata: openfeature.FlagMetadata(res.Metadata),
+	}
+	if res.ErrorCode == "" && err == nil {
+		return detail
+	}
+
+	message := res.ErrorCode
+	if err != nil {
+		message = err.Error()
+	}
+	switch res.ErrorCode {
+	case flag.ErrorCodeFlagNotFound:
+		detail.ResolutionError = openfeature.NewFlagNotFoundResolutionError(message)
+	case flag.ErrorCodeTypeMismatch:
+		detail.ResolutionError = openfeature.NewTypeMismatchResolutionError(message)
+	case flag.ErrorCodeTargetingKeyMissing:
+		detail.ResolutionError = openfeature.NewTargetingKeyMissingResolutionError(message)
+	case flag.ErrorCodeProviderNotReady:
+		detail.ResolutionError = openfeature.NewProviderNotReadyResolutionError(message)
+	case flag.ErrorCodeParseError:
+		detail.ResolutionError = openfeature.NewParseErrorResolutionError(message)
+	case flag.ErrorCodeInvalidContext:
+		detail.ResolutionError = openfeature.NewInvalidContextResolutionError(message)
+	default:
+		detail.ResolutionError = openfeature.NewGeneralResolutionError(message)
+	}
+	return detail
+}
@@ -0,0 +1,114 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/provider"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inmemoryretriever"
+)
+
+func newOpenFeatureClient(t *testing.T) *openfeature.Client {
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"bool-flag": map[string]interface{}{
+					"variations": map[string]interface{}{"enabled": true, "disabled": false},
+					"targeting": []interface{}{
+						map[string]interface{}{"query": `key eq "user-key" and beta eq true`, "variation": "enabled"},
+					},
+					"defaultRule": map[string]interface{}{"variation": "disabled"},
+				},
+				"int-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"default": 42},
+					"defaultRule": map[string]interface{}{"variation": "default"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(goff.Close)
+
+	require.NoError(t, openfeature.SetProviderAndWait(provider.NewProvider(goff)))
+	return openfeature.NewClient("provider-test")
+}
+
+func TestProvider_BooleanEvaluation(t *testing.T) {
+	client := newOpenFeatureClient(t)
+
+	// the targeting key is used as the key of the evaluation context
+	evalCtx := openfeature.NewEvaluationContext("user-key", map[string]interface{}{"beta": true})
+	details, err := client.BooleanValueDetails(context.Background(), "bool-flag", false, evalCtx)
+	assert.NoError(t, err)
+	assert.True(t, details.Value)
+	assert.Equal(t, openfeature.TargetingMatchReason, details.Reason)
+	assert.Equal(t, "enabled", details.Variant)
+
+	evalCtx = openfeature.NewEvaluationContext("other-user", map[string]interface{}{"beta": true})
+	details, err = client.BooleanValueDetails(context.Background(), "bool-flag", true, evalCtx)
+	assert.NoError(t, err)
+	assert.False(t, details.Value)
+	assert.Equal(t, openfeature.DefaultReason, details.Reason)
+}
+
+func TestProvider_errorCodes(t *testing.T) {
+	client := newOpenFeatureClient(t)
+	evalCtx := openfeature.NewEvaluationContext("user-key", nil)
+
+	tests := []struct {
+		name          string
+		evaluate      func() (interface{}, openfeature.EvaluationDetails, error)
+		wantValue     interface{}
+		wantReason    openfeature.Reason
+		wantErrorCode openfeature.ErrorCode
+	}{
+		{
+			name: "healthy flag",
+			evaluate: func() (interface{}, openfeature.EvaluationDetails, error) {
+				details, err := client.IntValueDetails(context.Background(), "int-flag", 1, evalCtx)
+				return details.Value, details.EvaluationDetails, err
+			},
+			wantValue:  int64(42),
+			wantReason: openfeature.StaticReason,
+		},
+		{
+			name: "unknown flag",
+			evaluate: func() (interface{}, openfeature.EvaluationDetails, error) {
+				details, err := client.BooleanValueDetails(context.Background(), "not-existing-flag", true, evalCtx)
+				return details.Value, details.EvaluationDetails, err
+			},
+			wantValue:     true,
+			wantReason:    openfeature.ErrorReason,
+			wantErrorCode: openfeature.FlagNotFoundCode,
+		},
+		{
+			name: "type mismatch",
+			evaluate: func() (interface{}, openfeature.EvaluationDetails, error) {
+				details, err := client.StringValueDetails(context.Background(), "int-flag", "default", evalCtx)
+				return details.Value, details.EvaluationDetails, err
+			},
+			wantValue:     "default",
+			wantReason:    openfeature.ErrorReason,
+			wantErrorCode: openfeature.TypeMismatchCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, details, err := tt.evaluate()
+			if tt.wantErrorCode == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantReason, details.Reason)
+			assert.Equal(t, tt.wantErrorCode, details.ErrorCode)
+		})
+	}
+}
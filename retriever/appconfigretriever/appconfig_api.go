@@ -0,0 +1,18 @@
+package appconfigretriever
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+)
+
+// AppConfigDataAPI defines the functions of the AppConfig Data API used by the retriever.
+// We use this interface to test the retriever using a mocked service.
+type AppConfigDataAPI interface {
+	StartConfigurationSession(ctx context.Context,
+		params *appconfigdata.StartConfigurationSessionInput,
+		optFns ...func(*appconfigdata.Options)) (*appconfigdata.StartConfigurationSessionOutput, error)
+	GetLatestConfiguration(ctx context.Context,
+		params *appconfigdata.GetLatestConfigurationInput,
+		optFns ...func(*appconfigdata.Options)) (*appconfigdata.GetLatestConfigurationOutput, error)
+}
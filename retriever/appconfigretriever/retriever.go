@@ -0,0 +1,162 @@
+package appconfigretriever
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+)
+
+// Retriever is a configuration struct for an AWS AppConfig retriever.
+// It uses the AppConfig Data API to retrieve the flag configuration deployed in AppConfig.
+type Retriever struct {
+	// Application is the name or the ID of your AppConfig application.
+	Application string
+
+	// Environment is the name or the ID of your AppConfig environment.
+	Environment string
+
+	// ConfigurationProfile is the name or the ID of the configuration profile containing your flags.
+	ConfigurationProfile string
+
+	// AwsConfig is the AWS SDK configuration object we will use to
+	// retrieve your feature flag configuration.
+	// (optional) Default: the default AWS configuration.
+	AwsConfig *aws.Config
+
+	// RequiredMinimumPollInterval is the minimum interval between two polls of the configuration
+	// requested to AppConfig, it is rounded to the second.
+	// (optional) Default: the default of AppConfig (60 seconds).
+	RequiredMinimumPollInterval time.Duration
+
+	client AppConfigDataAPI
+	status retriever.Status
+	mutex  sync.Mutex
+	// token is the configuration token to use for the next call of GetLatestConfiguration.
+	token *string
+	// nextPoll is the date before which AppConfig does not accept a new poll.
+	nextPoll time.Time
+	// content is the last configuration returned by AppConfig.
+	content []byte
+	// contentType is the content type of the last configuration returned by AppConfig.
+	contentType string
+}
+
+// Init is creating the AppConfig Data client.
+func (r *Retriever) Init(ctx context.Context, _ *log.Logger) error {
+	r.status = retriever.RetrieverNotReady
+	if r.client == nil {
+		if r.AwsConfig == nil {
+			cfg, err := config.LoadDefaultConfig(ctx)
+			if err != nil {
+				r.status = retriever.RetrieverError
+				return fmt.Errorf("impossible to init AppConfig retriever: %v", err)
+			}
+			r.AwsConfig = &cfg
+		}
+		r.client = appconfigdata.NewFromConfig(*r.AwsConfig)
+	}
+	r.status = retriever.RetrieverReady
+	return nil
+}
+
+// Shutdown is closing the AppConfig session.
+func (r *Retriever) Shutdown(_ context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.status = retriever.RetrieverNotReady
+	r.client = nil
+	r.token = nil
+	return nil
+}
+
+// Status is the status of the retriever.
+func (r *Retriever) Status() retriever.Status {
+	return r.status
+}
+
+// Retrieve is returning the latest flag configuration deployed in AppConfig.
+// The configuration session is started on the first call and its token is kept for the next calls.
+// If the configuration has not changed, or if the poll interval returned by AppConfig is not elapsed,
+// the last configuration received is returned.
+func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.client == nil {
+		r.status = retriever.RetrieverError
+		return nil, fmt.Errorf("AppConfig client is not initialized")
+	}
+
+	if r.content != nil && time.Now().Before(r.nextPoll) {
+		return r.content, nil
+	}
+
+	if r.token == nil {
+		if err := r.startSession(ctx); err != nil {
+			r.status = retriever.RetrieverError
+			return nil, err
+		}
+	}
+
+	output, err := r.client.GetLatestConfiguration(ctx, &appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: r.token,
+	})
+	if err != nil {
+		// the token can't be reused after an error (ex: expired token), a new session is started next time.
+		r.token = nil
+		r.status = retriever.RetrieverError
+		return nil, fmt.Errorf("impossible to retrieve the configuration from AppConfig: %v", err)
+	}
+	r.token = output.NextPollConfigurationToken
+	r.nextPoll = time.Now().Add(time.Duration(output.NextPollIntervalInSeconds) * time.Second)
+
+	// an empty configuration means that the configuration has not changed since the last call.
+	if len(output.Configuration) > 0 || r.content == nil {
+		r.content = output.Configuration
+		r.contentType = aws.ToString(output.ContentType)
+	}
+	r.status = retriever.RetrieverReady
+	return r.content, nil
+}
+
+// Format returns the format of the configuration based on the content type returned by AppConfig.
+// It returns an empty string if the content type is unknown, the FileFormat of the configuration is used.
+func (r *Retriever) Format() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch contentType := strings.ToLower(r.contentType); {
+	case strings.Contains(contentType, "json"):
+		return "json"
+	case strings.Contains(contentType, "yaml"):
+		return "yaml"
+	case strings.Contains(contentType, "toml"):
+		return "toml"
+	default:
+		return ""
+	}
+}
+
+// startSession is starting a new configuration session in AppConfig.
+func (r *Retriever) startSession(ctx context.Context) error {
+	input := &appconfigdata.StartConfigurationSessionInput{
+		ApplicationIdentifier:          aws.String(r.Application),
+		EnvironmentIdentifier:          aws.String(r.Environment),
+		ConfigurationProfileIdentifier: aws.String(r.ConfigurationProfile),
+	}
+	if r.RequiredMinimumPollInterval > 0 {
+		input.RequiredMinimumPollIntervalInSeconds = aws.Int32(int32(r.RequiredMinimumPollInterval.Seconds()))
+	}
+	output, err := r.client.StartConfigurationSession(ctx, input)
+	if err != nil {
+		return fmt.Errorf("impossible to start the AppConfig configuration session: %v", err)
+	}
+	r.token = output.InitialConfigurationToken
+	return nil
+}
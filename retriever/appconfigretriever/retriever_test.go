@@ -0,0 +1,123 @@
+package appconfigretriever
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+)
+
+type fakeResponse struct {
+	configuration []byte
+	pollInterval  int32
+	err           error
+}
+
+type fakeAppConfig struct {
+	// responses are returned in order by GetLatestConfiguration.
+	responses []fakeResponse
+	// sessions contains the inputs of the calls to StartConfigurationSession.
+	sessions []*appconfigdata.StartConfigurationSessionInput
+	// tokens contains the tokens received by GetLatestConfiguration.
+	tokens []string
+}
+
+func (f *fakeAppConfig) StartConfigurationSession(_ context.Context,
+	params *appconfigdata.StartConfigurationSessionInput,
+	_ ...func(*appconfigdata.Options)) (*appconfigdata.StartConfigurationSessionOutput, error) {
+	f.sessions = append(f.sessions, params)
+	return &appconfigdata.StartConfigurationSessionOutput{
+		InitialConfigurationToken: aws.String(fmt.Sprintf("session-%d", len(f.sessions))),
+	}, nil
+}
+
+func (f *fakeAppConfig) GetLatestConfiguration(_ context.Context,
+	params *appconfigdata.GetLatestConfigurationInput,
+	_ ...func(*appconfigdata.Options)) (*appconfigdata.GetLatestConfigurationOutput, error) {
+	f.tokens = append(f.tokens, aws.ToString(params.ConfigurationToken))
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	if response.err != nil {
+		return nil, response.err
+	}
+	return &appconfigdata.GetLatestConfigurationOutput{
+		Configuration:              response.configuration,
+		ContentType:                aws.String("application/x-yaml"),
+		NextPollConfigurationToken: aws.String(fmt.Sprintf("token-%d", len(f.tokens))),
+		NextPollIntervalInSeconds:  response.pollInterval,
+	}, nil
+}
+
+func TestRetriever_Retrieve(t *testing.T) {
+	fake := &fakeAppConfig{
+		responses: []fakeResponse{
+			{configuration: []byte("flag: v1")},
+			// an empty configuration means that the configuration has not changed
+			{configuration: []byte{}},
+			{configuration: []byte("flag: v2")},
+			{err: fmt.Errorf("expired token")},
+			{configuration: []byte("flag: v3")},
+		},
+	}
+	r := Retriever{
+		Application:          "my-app",
+		Environment:          "production",
+		ConfigurationProfile: "flags",
+		client:               fake,
+	}
+	require.NoError(t, r.Init(context.Background(), nil))
+	assert.Equal(t, retriever.RetrieverReady, r.Status())
+
+	for _, want := range []string{"flag: v1", "flag: v1", "flag: v2"} {
+		got, err := r.Retrieve(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	}
+	assert.Equal(t, "yaml", r.Format())
+
+	// after an error, a new session is started
+	_, err := r.Retrieve(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, retriever.RetrieverError, r.Status())
+	got, err := r.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "flag: v3", string(got))
+	assert.Equal(t, retriever.RetrieverReady, r.Status())
+
+	require.Len(t, fake.sessions, 2)
+	assert.Equal(t, "my-app", aws.ToString(fake.sessions[0].ApplicationIdentifier))
+	assert.Equal(t, "production", aws.ToString(fake.sessions[0].EnvironmentIdentifier))
+	assert.Equal(t, "flags", aws.ToString(fake.sessions[0].ConfigurationProfileIdentifier))
+	assert.Equal(t, []string{"session-1", "token-1", "token-2", "token-3", "session-2"}, fake.tokens)
+}
+
+func TestRetriever_RetrievePollInterval(t *testing.T) {
+	fake := &fakeAppConfig{
+		responses: []fakeResponse{
+			{configuration: []byte("flag: v1"), pollInterval: 60},
+			{configuration: []byte("flag: v2")},
+		},
+	}
+	r := Retriever{client: fake}
+	require.NoError(t, r.Init(context.Background(), nil))
+
+	for i := 0; i < 3; i++ {
+		got, err := r.Retrieve(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "flag: v1", string(got))
+	}
+	// AppConfig is not called before the end of the poll interval
+	assert.Len(t, fake.tokens, 1)
+}
+
+func TestRetriever_RetrieveNotInitialized(t *testing.T) {
+	r := Retriever{}
+	_, err := r.Retrieve(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, retriever.RetrieverError, r.Status())
+}
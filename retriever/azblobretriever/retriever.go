@@ -0,0 +1,118 @@
+package azblobretriever
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+)
+
+// Retriever is a configuration struct for an Azure Blob Storage retriever.
+type Retriever struct {
+	// AccountName is the name of your Azure Storage account.
+	// It is used to build the service URL https://<AccountName>.blob.core.windows.net/
+	// when no ConnectionString and no ServiceURL are provided.
+	AccountName string
+
+	// ConnectionString (optional) is the connection string of your storage account.
+	// If empty, the DefaultAzureCredential is used to authenticate
+	// (environment variables, workload identity, managed identity, Azure CLI, ...).
+	ConnectionString string
+
+	// ServiceURL (optional) is the URL of the blob service (ex: the URL of an Azurite emulator).
+	// It is ignored if a ConnectionString is provided.
+	// Default: https://<AccountName>.blob.core.windows.net/
+	ServiceURL string
+
+	// Container is the name of the container where your flag file is stored.
+	Container string
+
+	// Object is the name of your flag file in the container.
+	Object string
+
+	client *azblob.Client
+	status retriever.Status
+}
+
+func (r *Retriever) Init(_ context.Context, _ *log.Logger) error {
+	r.status = retriever.RetrieverNotReady
+	if r.client == nil {
+		client, err := r.newClient()
+		if err != nil {
+			r.status = retriever.RetrieverError
+			return fmt.Errorf("impossible to init Azure Blob Storage retriever: %v", err)
+		}
+		r.client = client
+	}
+	r.status = retriever.RetrieverReady
+	return nil
+}
+
+func (r *Retriever) Shutdown(_ context.Context) error {
+	r.status = retriever.RetrieverNotReady
+	r.client = nil
+	return nil
+}
+
+func (r *Retriever) Status() retriever.Status {
+	return r.status
+}
+
+// Retrieve is downloading the blob from the container.
+func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
+	if r.client == nil {
+		r.status = retriever.RetrieverError
+		return nil, fmt.Errorf("azure blob client is not initialized")
+	}
+
+	resp, err := r.client.DownloadStream(ctx, r.Container, r.Object, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download blob %q from container %q, %v",
+			r.Object, r.Container, describeError(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read blob %q from container %q, %v", r.Object, r.Container, err)
+	}
+	return content, nil
+}
+
+// newClient creates the azblob client using the connection string if provided
+// and the DefaultAzureCredential otherwise.
+func (r *Retriever) newClient() (*azblob.Client, error) {
+	if r.ConnectionString != "" {
+		return azblob.NewClientFromConnectionString(r.ConnectionString, nil)
+	}
+
+	serviceURL := r.ServiceURL
+	if serviceURL == "" {
+		if r.AccountName == "" {
+			return nil, fmt.Errorf("you should provide a ConnectionString, a ServiceURL or an AccountName")
+		}
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", r.AccountName)
+	}
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return azblob.NewClient(serviceURL, credential, nil)
+}
+
+// describeError adds a hint to the authentication errors returned by Azure.
+func describeError(err error) error {
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) ||
+		bloberror.HasCode(err, bloberror.AuthenticationFailed, bloberror.AuthorizationFailure,
+			bloberror.AuthorizationPermissionMismatch, bloberror.InvalidAuthenticationInfo) {
+		return fmt.Errorf("authentication failed, check the credentials of your storage account: %w", err)
+	}
+	return err
+}
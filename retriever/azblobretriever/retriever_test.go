@@ -0,0 +1,127 @@
+package azblobretriever_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/azblobretriever"
+)
+
+// azuriteKey is the well-known account key of the Azurite emulator.
+const azuriteKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+func connectionString(serverURL string) string {
+	return fmt.Sprintf("DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=%s;BlobEndpoint=%s/devstoreaccount1;",
+		azuriteKey, serverURL)
+}
+
+func TestRetriever_Retrieve(t *testing.T) {
+	content := "test-flag:\n  variations:\n    A: true\n  defaultRule:\n    variation: A\n"
+	var requestedPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Contains(t, r.Header.Get("Authorization"), "SharedKey devstoreaccount1:")
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("x-ms-blob-type", "BlockBlob")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	r := azblobretriever.Retriever{
+		ConnectionString: connectionString(srv.URL),
+		Container:        "flags",
+		Object:           "config/flag-config.yaml",
+	}
+	err := r.Init(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, retriever.RetrieverReady, r.Status())
+
+	got, err := r.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, content, string(got))
+	assert.Equal(t, "/devstoreaccount1/flags/config/flag-config.yaml", requestedPath)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, retriever.RetrieverNotReady, r.Status())
+}
+
+func TestRetriever_RetrieveErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		errorCode    string
+		wantErrorMsg string
+	}{
+		{
+			name:         "authentication failed",
+			status:       http.StatusForbidden,
+			errorCode:    "AuthenticationFailed",
+			wantErrorMsg: "authentication failed, check the credentials of your storage account",
+		},
+		{
+			name:         "blob not found",
+			status:       http.StatusNotFound,
+			errorCode:    "BlobNotFound",
+			wantErrorMsg: "unable to download blob \"flag-config.yaml\" from container \"flags\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("x-ms-error-code", tt.errorCode)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			r := azblobretriever.Retriever{
+				ConnectionString: connectionString(srv.URL),
+				Container:        "flags",
+				Object:           "flag-config.yaml",
+			}
+			require.NoError(t, r.Init(context.Background(), nil))
+			_, err := r.Retrieve(context.Background())
+			assert.ErrorContains(t, err, tt.wantErrorMsg)
+		})
+	}
+}
+
+func TestRetriever_Init(t *testing.T) {
+	tests := []struct {
+		name      string
+		retriever azblobretriever.Retriever
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "no account, no service url and no connection string",
+			retriever: azblobretriever.Retriever{Container: "flags", Object: "flag-config.yaml"},
+			wantErr:   assert.Error,
+		},
+		{
+			name:      "invalid connection string",
+			retriever: azblobretriever.Retriever{ConnectionString: "invalid", Container: "flags"},
+			wantErr:   assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.retriever.Init(context.Background(), nil)
+			tt.wantErr(t, err)
+			assert.Equal(t, retriever.RetrieverError, tt.retriever.Status())
+		})
+	}
+}
+
+func TestRetriever_RetrieveWithoutInit(t *testing.T) {
+	r := azblobretriever.Retriever{Container: "flags", Object: "flag-config.yaml"}
+	_, err := r.Retrieve(context.Background())
+	assert.Error(t, err)
+}
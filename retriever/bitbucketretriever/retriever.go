@@ -0,0 +1,130 @@
+package bitbucketretriever
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	httpretriever "github.com/thomaspoignant/go-feature-flag/retriever/httpretriever"
+
+	"github.com/thomaspoignant/go-feature-flag/internal"
+)
+
+const bitbucketCloudAPI = "https://api.bitbucket.org"
+
+// Retriever is a configuration struct for a Bitbucket retriever.
+// It supports Bitbucket Cloud and Bitbucket Server (Data Center).
+type Retriever struct {
+	// Workspace is the Bitbucket Cloud workspace or the Bitbucket Server project key containing the repository.
+	Workspace string
+
+	// RepositorySlug is the name of your repository.
+	RepositorySlug string
+
+	// FilePath is the location of your file in the repository.
+	FilePath string
+
+	// Ref is the branch, tag or commit where to download the file.
+	// default: main
+	Ref string
+
+	// Username is used with AppPassword to authenticate with basic auth (Bitbucket Cloud app password).
+	Username string
+
+	// AppPassword is the app password of the user, it is used only if Username is set.
+	AppPassword string
+
+	// Token is an access token sent as a Bearer token, it takes precedence over the app password.
+	Token string
+
+	// BaseURL is the API URL of your Bitbucket installation.
+	// If empty or equal to https://api.bitbucket.org the Bitbucket Cloud API is used,
+	// otherwise the Bitbucket Server API is used.
+	// default: https://api.bitbucket.org
+	BaseURL string
+
+	// Timeout is the time before we timeout while retrieving the flag file.
+	// default: 10 seconds
+	Timeout time.Duration
+
+	// httpClient is the http.Client if you want to override it.
+	httpClient internal.HTTPClient
+}
+
+func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
+	if r.FilePath == "" || r.Workspace == "" || r.RepositorySlug == "" {
+		return nil, fmt.Errorf(
+			"missing mandatory information filePath=%s, workspace=%s, repositorySlug=%s",
+			r.FilePath, r.Workspace, r.RepositorySlug)
+	}
+
+	// default ref is main
+	ref := r.Ref
+	if ref == "" {
+		ref = "main"
+	}
+
+	header := http.Header{}
+	if r.Token != "" {
+		header.Add("Authorization", fmt.Sprintf("Bearer %s", r.Token))
+	} else if r.Username != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(r.Username + ":" + r.AppPassword))
+		header.Add("Authorization", fmt.Sprintf("Basic %s", credentials))
+	}
+
+	httpRetriever := httpretriever.Retriever{
+		URL:     r.fileURL(ref),
+		Method:  http.MethodGet,
+		Header:  header,
+		Timeout: r.Timeout,
+	}
+
+	if r.httpClient != nil {
+		httpRetriever.SetHTTPClient(r.httpClient)
+	}
+
+	return httpRetriever.Retrieve(ctx)
+}
+
+// fileURL returns the URL of the raw file, the shape of the path depends on the Bitbucket flavour.
+func (r *Retriever) fileURL(ref string) string {
+	baseURL := strings.TrimSuffix(r.BaseURL, "/")
+	if baseURL == "" || baseURL == bitbucketCloudAPI {
+		// Bitbucket Cloud: /2.0/repositories/{workspace}/{repo_slug}/src/{commit}/{path}
+		return strings.Join([]string{
+			bitbucketCloudAPI, "2.0/repositories",
+			url.PathEscape(r.Workspace),
+			url.PathEscape(r.RepositorySlug),
+			"src",
+			url.PathEscape(ref),
+			escapeFilePath(r.FilePath)}, "/")
+	}
+
+	// Bitbucket Server: /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/raw/{path}?at={ref}
+	return strings.Join([]string{
+		baseURL, "rest/api/1.0/projects",
+		url.PathEscape(r.Workspace),
+		"repos",
+		url.PathEscape(r.RepositorySlug),
+		"raw",
+		escapeFilePath(r.FilePath)}, "/") + "?at=" + url.QueryEscape(ref)
+}
+
+// escapeFilePath escapes each segment of the file path, keeping the / separators.
+func escapeFilePath(filePath string) string {
+	segments := strings.Split(strings.TrimPrefix(filePath, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// SetHTTPClient is here if you want to override the default http.Client we are using.
+// It is also used for the tests.
+func (r *Retriever) SetHTTPClient(client internal.HTTPClient) {
+	r.httpClient = client
+}
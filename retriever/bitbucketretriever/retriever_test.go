@@ -0,0 +1,151 @@
+package bitbucketretriever_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thomaspoignant/go-feature-flag/retriever/bitbucketretriever"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleText() string {
+	return `test-flag:
+  variations:
+    true_var: true
+    false_var: false
+  targeting:
+    - query: key eq "random-key"
+      percentage:
+        true_var: 0
+        false_var: 100
+  defaultRule:
+    variation: false_var
+`
+}
+
+func Test_bitbucket_Retrieve_cloud(t *testing.T) {
+	tests := []struct {
+		name      string
+		retriever bitbucketretriever.Retriever
+		wantURL   string
+		wantAuth  string
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Default values",
+			retriever: bitbucketretriever.Retriever{
+				Workspace:      "thomaspoignant",
+				RepositorySlug: "go-feature-flag",
+				FilePath:       "config/flag-config.yaml",
+			},
+			wantURL: "https://api.bitbucket.org/2.0/repositories/thomaspoignant/go-feature-flag/src/main/" +
+				"config/flag-config.yaml",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "With ref and token",
+			retriever: bitbucketretriever.Retriever{
+				Workspace:      "thomaspoignant",
+				RepositorySlug: "go-feature-flag",
+				FilePath:       "flag-config.yaml",
+				Ref:            "v1.0.0",
+				Token:          "XXX",
+				BaseURL:        "https://api.bitbucket.org/",
+			},
+			wantURL:  "https://api.bitbucket.org/2.0/repositories/thomaspoignant/go-feature-flag/src/v1.0.0/flag-config.yaml",
+			wantAuth: "Bearer XXX",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "With app password",
+			retriever: bitbucketretriever.Retriever{
+				Workspace:      "thomaspoignant",
+				RepositorySlug: "go-feature-flag",
+				FilePath:       "flag-config.yaml",
+				Username:       "user",
+				AppPassword:    "password",
+			},
+			wantURL:  "https://api.bitbucket.org/2.0/repositories/thomaspoignant/go-feature-flag/src/main/flag-config.yaml",
+			wantAuth: "Basic dXNlcjpwYXNzd29yZA==",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "Missing workspace",
+			retriever: bitbucketretriever.Retriever{
+				RepositorySlug: "go-feature-flag",
+				FilePath:       "flag-config.yaml",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "Missing file path",
+			retriever: bitbucketretriever.Retriever{
+				Workspace:      "thomaspoignant",
+				RepositorySlug: "go-feature-flag",
+			},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := mock.HTTP{}
+			tt.retriever.SetHTTPClient(&httpClient)
+			got, err := tt.retriever.Retrieve(context.Background())
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, strings.TrimSpace(sampleText()), strings.TrimSpace(string(got)))
+			assert.Equal(t, http.MethodGet, httpClient.Req.Method)
+			assert.Equal(t, tt.wantURL, httpClient.Req.URL.String())
+			assert.Equal(t, tt.wantAuth, httpClient.Req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func Test_bitbucket_Retrieve_server(t *testing.T) {
+	var receivedPath, receivedRef, receivedAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		receivedRef = r.URL.Query().Get("at")
+		receivedAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(sampleText()))
+	}))
+	defer srv.Close()
+
+	r := bitbucketretriever.Retriever{
+		BaseURL:        srv.URL,
+		Workspace:      "PROJ",
+		RepositorySlug: "go-feature-flag",
+		FilePath:       "config/flag config.yaml",
+		Ref:            "refs/heads/dev",
+		Token:          "XXX",
+	}
+	got, err := r.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sampleText(), string(got))
+	assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/go-feature-flag/raw/config/flag%20config.yaml", receivedPath)
+	assert.Equal(t, "refs/heads/dev", receivedRef)
+	assert.Equal(t, "Bearer XXX", receivedAuth)
+}
+
+func Test_bitbucket_Retrieve_serverError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	r := bitbucketretriever.Retriever{
+		BaseURL:        srv.URL,
+		Workspace:      "PROJ",
+		RepositorySlug: "go-feature-flag",
+		FilePath:       "flag-config.yaml",
+	}
+	_, err := r.Retrieve(context.Background())
+	assert.Error(t, err)
+}
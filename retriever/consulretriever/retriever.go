@@ -0,0 +1,120 @@
+package consulretriever
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/internal"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+)
+
+const defaultAddress = "http://127.0.0.1:8500"
+
+// Retriever is a configuration struct for a HashiCorp Consul KV retriever.
+// The flag file is read from the value of a key using the HTTP API of a Consul agent.
+type Retriever struct {
+	// Address is the address of the Consul agent (ex: "http://127.0.0.1:8500" or "consul.internal:8500").
+	// If no scheme is provided, http is used.
+	// default: http://127.0.0.1:8500
+	Address string
+
+	// Key is the key of the Consul KV store containing your flag file (ex: "go-feature-flag/flags.yaml").
+	Key string
+
+	// Token (optional) is the ACL token used to read the key, it is sent in the X-Consul-Token header.
+	Token string
+
+	// Datacenter (optional) is the datacenter to query, by default the datacenter of the agent is used.
+	Datacenter string
+
+	// Timeout is the time before we timeout while retrieving the flag file.
+	// default: 10 seconds
+	Timeout time.Duration
+
+	// httpClient is the http.Client if you want to override it.
+	httpClient internal.HTTPClient
+}
+
+func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
+	if r.Key == "" {
+		return nil, fmt.Errorf("missing mandatory information key=%s", r.Key)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	keyURL := r.keyURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keyURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.Token != "" {
+		req.Header.Set("X-Consul-Token", r.Token)
+	}
+
+	if r.httpClient == nil {
+		timeout := r.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		r.httpClient = internal.HTTPClientWithTimeout(timeout)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("key %s not found in Consul KV", r.Key)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		rateLimitErr := &retriever.RateLimitError{URL: keyURL}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			rateLimitErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, rateLimitErr
+	case resp.StatusCode > 399:
+		return nil, fmt.Errorf("request to %s failed with code %d", keyURL, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// keyURL returns the URL of the KV endpoint returning the raw value of the key.
+func (r *Retriever) keyURL() string {
+	address := strings.TrimSuffix(r.Address, "/")
+	if address == "" {
+		address = defaultAddress
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	segments := strings.Split(strings.TrimPrefix(r.Key, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	query := url.Values{}
+	if r.Datacenter != "" {
+		query.Set("dc", r.Datacenter)
+	}
+	keyURL := address + "/v1/kv/" + strings.Join(segments, "/") + "?raw"
+	if len(query) > 0 {
+		keyURL += "&" + query.Encode()
+	}
+	return keyURL
+}
+
+// SetHTTPClient is here if you want to override the default http.Client we are using.
+// It is also used for the tests.
+func (r *Retriever) SetHTTPClient(client internal.HTTPClient) {
+	r.httpClient = client
+}
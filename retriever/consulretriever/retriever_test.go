@@ -0,0 +1,122 @@
+package consulretriever_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/retriever/consulretriever"
+)
+
+const flagConfig = `test-flag:
+  variations:
+    true_var: true
+    false_var: false
+  defaultRule:
+    variation: false_var
+`
+
+// fakeConsulAgent is serving the KV endpoint of the HTTP API of a Consul agent.
+func fakeConsulAgent(t *testing.T, kv map[string]string, token string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.True(t, req.URL.Query().Has("raw"), "the value should be requested raw")
+		if token != "" && req.Header.Get("X-Consul-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		value, ok := kv[strings.TrimPrefix(req.URL.Path, "/v1/kv/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(value))
+	}))
+}
+
+func TestRetriever_Retrieve(t *testing.T) {
+	tests := []struct {
+		name    string
+		kv      map[string]string
+		token   string
+		r       consulretriever.Retriever
+		want    string
+		wantErr string
+	}{
+		{
+			name: "value without token",
+			kv:   map[string]string{"go-feature-flag/flags.yaml": flagConfig},
+			r:    consulretriever.Retriever{Key: "go-feature-flag/flags.yaml"},
+			want: flagConfig,
+		},
+		{
+			name:  "value with ACL token and datacenter",
+			kv:    map[string]string{"go-feature-flag/flags.yaml": flagConfig},
+			token: "secret-token",
+			r: consulretriever.Retriever{
+				Key:        "go-feature-flag/flags.yaml",
+				Token:      "secret-token",
+				Datacenter: "dc1",
+			},
+			want: flagConfig,
+		},
+		{
+			name:    "missing key",
+			kv:      map[string]string{},
+			r:       consulretriever.Retriever{Key: "go-feature-flag/flags.yaml"},
+			wantErr: "key go-feature-flag/flags.yaml not found in Consul KV",
+		},
+		{
+			name:    "invalid ACL token",
+			kv:      map[string]string{"go-feature-flag/flags.yaml": flagConfig},
+			token:   "secret-token",
+			r:       consulretriever.Retriever{Key: "go-feature-flag/flags.yaml", Token: "wrong-token"},
+			wantErr: "failed with code 403",
+		},
+		{
+			name:    "no key",
+			r:       consulretriever.Retriever{},
+			wantErr: "missing mandatory information key=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeConsulAgent(t, tt.kv, tt.token)
+			defer server.Close()
+			tt.r.Address = server.URL
+
+			got, err := tt.r.Retrieve(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRetriever_RetrieveRequest(t *testing.T) {
+	var gotRequest *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotRequest = req
+		_, _ = w.Write([]byte(flagConfig))
+	}))
+	defer server.Close()
+
+	r := consulretriever.Retriever{
+		// the scheme is optional
+		Address:    strings.TrimPrefix(server.URL, "http://"),
+		Key:        "/go-feature-flag/my flags.yaml",
+		Token:      "secret-token",
+		Datacenter: "dc1",
+	}
+	_, err := r.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/kv/go-feature-flag/my flags.yaml", gotRequest.URL.Path)
+	assert.Equal(t, "dc1", gotRequest.URL.Query().Get("dc"))
+	assert.Equal(t, "secret-token", gotRequest.Header.Get("X-Consul-Token"))
+}
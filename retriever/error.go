@@ -0,0 +1,29 @@
+package retriever
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitError is returned by a retriever when the source of the flags is rate limiting the requests
+// (ex: HTTP code 429).
+// It is a temporary error, the flags can be retrieved again later.
+type RateLimitError struct {
+	// URL is the location that has been rate limited.
+	URL string
+
+	// RetryAfter is the duration to wait before retrying, 0 if the source did not provide it.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("request to %s has been rate limited, retry after %s", e.URL, e.RetryAfter)
+	}
+	return fmt.Sprintf("request to %s has been rate limited", e.URL)
+}
+
+// Temporary returns true, the retriever can be called again after a rate limit.
+func (e *RateLimitError) Temporary() bool {
+	return true
+}
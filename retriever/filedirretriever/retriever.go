@@ -0,0 +1,132 @@
+package filedirretriever
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/thomaspoignant/go-feature-flag/internal/utils"
+)
+
+// DuplicateKeyPolicy is the behavior of the retriever when the same flag is defined in several files.
+type DuplicateKeyPolicy string
+
+const (
+	// DuplicateKeyError returns an error if the same flag is defined in several files.
+	DuplicateKeyError DuplicateKeyPolicy = "error"
+
+	// DuplicateKeyLastWins keeps the definition of the flag of the last file, in alphabetical order.
+	DuplicateKeyLastWins DuplicateKeyPolicy = "lastWins"
+)
+
+// Retriever is a configuration struct for a retriever loading all the flag files of a local directory.
+// The files are read in alphabetical order and their flags are merged by key.
+// The format of each file is deduced from its extension (.yaml, .yml, .json, .json5, .jsonc or .toml),
+// the files with another extension are ignored.
+//
+// Note: the merged flags are serialized in JSON, the retriever is providing its format so it works
+// whatever the file format of your configuration is.
+type Retriever struct {
+	// Path is the location of the directory containing the flag files.
+	Path string
+
+	// Pattern (optional) is the glob pattern of the files to load in the directory (ex: "*.goff.yaml").
+	// Default: "*"
+	Pattern string
+
+	// DuplicateKeyPolicy (optional) is the behavior when the same flag is defined in several files.
+	// Default: DuplicateKeyError
+	DuplicateKeyPolicy DuplicateKeyPolicy
+}
+
+// Retrieve is reading all the files of the directory and returns their flags merged and serialized in JSON.
+func (r *Retriever) Retrieve(_ context.Context) ([]byte, error) {
+	pattern := r.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	files, err := filepath.Glob(filepath.Join(r.Path, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	// the precedence of the files is the alphabetical order.
+	sort.Strings(files)
+
+	flags := map[string]interface{}{}
+	flagFiles := map[string]string{}
+	for _, file := range files {
+		format := fileFormat(file)
+		if format == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fileFlags, err := unmarshal(content, format)
+		if err != nil {
+			return nil, fmt.Errorf("impossible to parse the file %s: %w", file, err)
+		}
+
+		for key, value := range fileFlags {
+			if previousFile, ok := flagFiles[key]; ok && r.DuplicateKeyPolicy != DuplicateKeyLastWins {
+				return nil, fmt.Errorf("flag %s is defined in %s and %s", key, previousFile, file)
+			}
+			flags[key] = value
+			flagFiles[key] = file
+		}
+	}
+	return json.Marshal(flags)
+}
+
+// Format returns the format of the flags returned by the retriever, the flags are always serialized in JSON.
+func (r *Retriever) Format() string {
+	return "json"
+}
+
+// fileFormat returns the format of the file from its extension, it returns an empty string
+// if the extension is not supported.
+func fileFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".json5", ".jsonc":
+		return "json5"
+	case ".toml":
+		return "toml"
+	default:
+		return ""
+	}
+}
+
+// unmarshal decodes the flags of a file using its format.
+func unmarshal(content []byte, format string) (map[string]interface{}, error) {
+	flags := map[string]interface{}{}
+	var err error
+	switch format {
+	case "toml":
+		err = toml.Unmarshal(content, &flags)
+	case "json":
+		err = json.Unmarshal(content, &flags)
+	case "json5":
+		err = utils.UnmarshalFlagsJSON5(content, &flags)
+	default:
+		err = utils.UnmarshalFlagsYAML(content, &flags)
+	}
+	return flags, err
+}
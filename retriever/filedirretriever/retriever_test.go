@@ -0,0 +1,143 @@
+package filedirretriever_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/retriever/filedirretriever"
+)
+
+const teamAFlags = `flag-a:
+  variations:
+    enabled: true
+    disabled: false
+  defaultRule:
+    variation: enabled
+shared-flag:
+  variations:
+    enabled: true
+    disabled: false
+  defaultRule:
+    variation: enabled
+`
+
+const teamBFlags = `{
+  "flag-b": {
+    "variations": {"enabled": true, "disabled": false},
+    "defaultRule": {"variation": "disabled"}
+  }
+}
+`
+
+const teamCFlags = `[flag-c.variations]
+enabled = true
+disabled = false
+
+[flag-c.defaultRule]
+variation = "enabled"
+
+[shared-flag.variations]
+enabled = true
+disabled = false
+
+[shared-flag.defaultRule]
+variation = "disabled"
+`
+
+func createFlagDir(t *testing.T, withDuplicate bool) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team-a.yaml"), []byte(teamAFlags), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team-b.json"), []byte(teamBFlags), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# flags"), 0o600))
+	if withDuplicate {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "team-c.toml"), []byte(teamCFlags), 0o600))
+	}
+	return dir
+}
+
+func defaultVariation(t *testing.T, flags map[string]interface{}, key string) interface{} {
+	f, ok := flags[key].(map[string]interface{})
+	require.True(t, ok, "flag %s should be loaded", key)
+	return f["defaultRule"].(map[string]interface{})["variation"]
+}
+
+func TestRetriever_Retrieve(t *testing.T) {
+	r := filedirretriever.Retriever{Path: createFlagDir(t, false)}
+	content, err := r.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "json", r.Format())
+
+	var flags map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &flags))
+	assert.Len(t, flags, 3)
+	assert.Equal(t, "enabled", defaultVariation(t, flags, "flag-a"))
+	assert.Equal(t, "disabled", defaultVariation(t, flags, "flag-b"))
+	assert.Equal(t, "enabled", defaultVariation(t, flags, "shared-flag"))
+}
+
+func TestRetriever_RetrieveDuplicateKey(t *testing.T) {
+	tests := []struct {
+		name                string
+		policy              filedirretriever.DuplicateKeyPolicy
+		wantErr             assert.ErrorAssertionFunc
+		wantSharedVariation string
+	}{
+		{
+			name:    "error by default",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "error policy",
+			policy:  filedirretriever.DuplicateKeyError,
+			wantErr: assert.Error,
+		},
+		{
+			name:                "last file wins",
+			policy:              filedirretriever.DuplicateKeyLastWins,
+			wantErr:             assert.NoError,
+			wantSharedVariation: "disabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := filedirretriever.Retriever{
+				Path:               createFlagDir(t, true),
+				DuplicateKeyPolicy: tt.policy,
+			}
+			content, err := r.Retrieve(context.Background())
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+
+			var flags map[string]interface{}
+			require.NoError(t, json.Unmarshal(content, &flags))
+			assert.Len(t, flags, 4)
+			assert.Equal(t, tt.wantSharedVariation, defaultVariation(t, flags, "shared-flag"))
+			assert.Equal(t, "enabled", defaultVariation(t, flags, "flag-c"))
+		})
+	}
+}
+
+func TestRetriever_RetrievePattern(t *testing.T) {
+	r := filedirretriever.Retriever{Path: createFlagDir(t, true), Pattern: "*.yaml"}
+	content, err := r.Retrieve(context.Background())
+	require.NoError(t, err)
+
+	var flags map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &flags))
+	assert.Len(t, flags, 2)
+}
+
+func TestRetriever_RetrieveInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{invalid"), 0o600))
+	r := filedirretriever.Retriever{Path: dir}
+	_, err := r.Retrieve(context.Background())
+	assert.Error(t, err)
+}
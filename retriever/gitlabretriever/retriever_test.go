@@ -3,9 +3,12 @@ package gitlabretriever_test
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/thomaspoignant/go-feature-flag/retriever"
 	"github.com/thomaspoignant/go-feature-flag/retriever/gitlabretriever"
 	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
 
@@ -163,3 +166,49 @@ func Test_gitlab_Retrieve(t *testing.T) {
 		})
 	}
 }
+
+func Test_gitlab_Retrieve_selfHosted(t *testing.T) {
+	var receivedPath, receivedRef, receivedToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		receivedRef = r.URL.Query().Get("ref")
+		receivedToken = r.Header.Get("PRIVATE-TOKEN")
+		_, _ = w.Write([]byte(sampleText()))
+	}))
+	defer srv.Close()
+
+	r := gitlabretriever.Retriever{
+		BaseURL:        srv.URL,
+		RepositorySlug: "thomaspoignant/go-feature-flag",
+		FilePath:       "config/flag-config.yaml",
+		Branch:         "dev",
+		GitlabToken:    "XXX",
+	}
+	got, err := r.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sampleText(), string(got))
+	assert.Equal(t,
+		"/api/v4/projects/thomaspoignant%2Fgo-feature-flag/repository/files/config%2Fflag-config.yaml/raw",
+		receivedPath)
+	assert.Equal(t, "dev", receivedRef)
+	assert.Equal(t, "XXX", receivedToken)
+}
+
+func Test_gitlab_Retrieve_rateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	r := gitlabretriever.Retriever{
+		BaseURL:        srv.URL,
+		RepositorySlug: "thomaspoignant/go-feature-flag",
+		FilePath:       "flag-config.yaml",
+	}
+	_, err := r.Retrieve(context.Background())
+	var rateLimitErr *retriever.RateLimitError
+	assert.ErrorAs(t, err, &rateLimitErr)
+	assert.True(t, rateLimitErr.Temporary())
+	assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+}
@@ -2,14 +2,19 @@ package httpretriever
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/internal"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
 )
 
 // Retriever is a configuration struct for an HTTP endpoint retriever.
@@ -29,6 +34,22 @@ type Retriever struct {
 	// Timeout we should wait before failing (default: 10 seconds)
 	Timeout time.Duration
 
+	// TLSClientCertFile (optional) is the path of the PEM encoded client certificate used for mutual TLS,
+	// TLSClientKeyFile should be provided too.
+	TLSClientCertFile string
+
+	// TLSClientKeyFile (optional) is the path of the PEM encoded private key of the client certificate.
+	TLSClientKeyFile string
+
+	// TLSCACertFile (optional) is the path of the PEM encoded CA bundle used to verify the certificate
+	// of the server, if empty the CAs of the system are used.
+	TLSCACertFile string
+
+	// TLSInsecureSkipVerifyForDevOnly (optional) disables the verification of the certificate of the server.
+	// Never use it in production, it is only here to test with self-signed certificates.
+	// default: false
+	TLSInsecureSkipVerifyForDevOnly bool
+
 	httpClient internal.HTTPClient
 }
 
@@ -68,7 +89,10 @@ func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
 	}
 
 	if r.httpClient == nil {
-		r.httpClient = internal.HTTPClientWithTimeout(timeout)
+		r.httpClient, err = r.newHTTPClient(timeout)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// API call
@@ -78,6 +102,15 @@ func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
+	// The source is rate limiting us, we return a temporary error.
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateLimitErr := &retriever.RateLimitError{URL: r.URL}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			rateLimitErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, rateLimitErr
+	}
+
 	// Error if http code is more that 399
 	if resp.StatusCode > 399 {
 		return nil, fmt.Errorf("request to %s failed with code %d", r.URL, resp.StatusCode)
@@ -90,3 +123,49 @@ func (r *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
 	}
 	return body, nil
 }
+
+// newHTTPClient creates the http.Client used to call the endpoint, a custom tls.Config is used
+// only if one of the TLS options is set.
+func (r *Retriever) newHTTPClient(timeout time.Duration) (internal.HTTPClient, error) {
+	if r.TLSClientCertFile == "" && r.TLSClientKeyFile == "" && r.TLSCACertFile == "" &&
+		!r.TLSInsecureSkipVerifyForDevOnly {
+		return internal.HTTPClientWithTimeout(timeout), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// nolint: gosec
+		InsecureSkipVerify: r.TLSInsecureSkipVerifyForDevOnly,
+	}
+
+	if r.TLSClientCertFile != "" || r.TLSClientKeyFile != "" {
+		if r.TLSClientCertFile == "" || r.TLSClientKeyFile == "" {
+			return nil, errors.New("TLSClientCertFile and TLSClientKeyFile should be provided together")
+		}
+		cert, err := tls.LoadX509KeyPair(r.TLSClientCertFile, r.TLSClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("impossible to load the client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if r.TLSCACertFile != "" {
+		caBundle, err := os.ReadFile(r.TLSCACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("impossible to read the CA bundle %s: %v", r.TLSCACertFile, err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no certificate found in the CA bundle %s", r.TLSCACertFile)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
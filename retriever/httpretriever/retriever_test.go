@@ -2,13 +2,27 @@ package httpretriever_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/thomaspoignant/go-feature-flag/retriever"
 	"github.com/thomaspoignant/go-feature-flag/retriever/httpretriever"
 	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_httpRetriever_Retrieve(t *testing.T) {
@@ -151,3 +165,146 @@ func Test_httpRetriever_Retrieve(t *testing.T) {
 		})
 	}
 }
+
+func Test_httpRetriever_Retrieve_rateLimited(t *testing.T) {
+	tests := []struct {
+		name           string
+		retryAfter     string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:           "with Retry-After header",
+			retryAfter:     "10",
+			wantRetryAfter: 10 * time.Second,
+		},
+		{
+			name:           "without Retry-After header",
+			wantRetryAfter: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer srv.Close()
+
+			h := httpretriever.Retriever{URL: srv.URL}
+			got, err := h.Retrieve(context.Background())
+			assert.Nil(t, got)
+
+			var rateLimitErr *retriever.RateLimitError
+			assert.ErrorAs(t, err, &rateLimitErr)
+			assert.Equal(t, srv.URL, rateLimitErr.URL)
+			assert.Equal(t, tt.wantRetryAfter, rateLimitErr.RetryAfter)
+			assert.True(t, rateLimitErr.Temporary())
+		})
+	}
+}
+
+func Test_httpRetriever_Retrieve_mutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, clientCertFile, clientKeyFile := generateClientCertificate(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("test-flag:\n  defaultRule:\n    variation: A\n"))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	tests := []struct {
+		name    string
+		r       httpretriever.Retriever
+		wantErr string
+	}{
+		{
+			name: "client certificate and CA bundle",
+			r: httpretriever.Retriever{
+				TLSClientCertFile: clientCertFile,
+				TLSClientKeyFile:  clientKeyFile,
+				TLSCACertFile:     caFile,
+			},
+		},
+		{
+			name: "client certificate and skip verification",
+			r: httpretriever.Retriever{
+				TLSClientCertFile:               clientCertFile,
+				TLSClientKeyFile:                clientKeyFile,
+				TLSInsecureSkipVerifyForDevOnly: true,
+			},
+		},
+		{
+			name:    "no client certificate",
+			r:       httpretriever.Retriever{TLSCACertFile: caFile},
+			wantErr: "certificate",
+		},
+		{
+			name: "unknown server certificate",
+			r: httpretriever.Retriever{
+				TLSClientCertFile: clientCertFile,
+				TLSClientKeyFile:  clientKeyFile,
+			},
+			wantErr: "certificate signed by unknown authority",
+		},
+		{
+			name:    "client certificate without key",
+			r:       httpretriever.Retriever{TLSClientCertFile: clientCertFile},
+			wantErr: "TLSClientCertFile and TLSClientKeyFile should be provided together",
+		},
+		{
+			name:    "invalid CA bundle",
+			r:       httpretriever.Retriever{TLSCACertFile: clientKeyFile},
+			wantErr: "no certificate found in the CA bundle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.URL = srv.URL
+			got, err := tt.r.Retrieve(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "test-flag:\n  defaultRule:\n    variation: A\n", string(got))
+		})
+	}
+}
+
+// generateClientCertificate creates a self-signed client certificate and writes it with its key in dir.
+func generateClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "go-feature-flag-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return cert, certFile, keyFile
+}
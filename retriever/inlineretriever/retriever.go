@@ -0,0 +1,42 @@
+package inlineretriever
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Retriever is a configuration struct for a retriever returning a flag configuration
+// written inline, in a string or in an environment variable.
+// It is useful for tiny services and tests when you don't want to create a file to store your flags.
+//
+// If EnvVar is set, the configuration is read from the environment variable, otherwise Content is used.
+type Retriever struct {
+	// Content is the raw flag configuration, as you would write it in your configuration file.
+	Content string
+
+	// EnvVar is the name of an environment variable containing the raw flag configuration.
+	// If set, it takes precedence over Content.
+	EnvVar string
+
+	// FileFormat is the format of the configuration (yaml, json or toml).
+	// If empty, the FileFormat of the configuration is used.
+	FileFormat string
+}
+
+// Retrieve is returning the raw flag configuration.
+func (r *Retriever) Retrieve(_ context.Context) ([]byte, error) {
+	if r.EnvVar == "" {
+		return []byte(r.Content), nil
+	}
+	content, ok := os.LookupEnv(r.EnvVar)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", r.EnvVar)
+	}
+	return []byte(content), nil
+}
+
+// Format returns the format of the flag configuration returned by the retriever.
+func (r *Retriever) Format() string {
+	return r.FileFormat
+}
@@ -0,0 +1,54 @@
+package inlineretriever_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inlineretriever"
+)
+
+func TestRetriever_Retrieve(t *testing.T) {
+	t.Setenv("GOFF_TEST_FLAGS", `{"env-flag": {}}`)
+	tests := []struct {
+		name      string
+		retriever inlineretriever.Retriever
+		want      string
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "should return the content",
+			retriever: inlineretriever.Retriever{Content: "my-flag:\n  variations: {}\n"},
+			want:      "my-flag:\n  variations: {}\n",
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "should return the environment variable",
+			retriever: inlineretriever.Retriever{
+				Content: "my-flag:\n  variations: {}\n",
+				EnvVar:  "GOFF_TEST_FLAGS",
+			},
+			want:    `{"env-flag": {}}`,
+			wantErr: assert.NoError,
+		},
+		{
+			name:      "should return an error if the environment variable is not set",
+			retriever: inlineretriever.Retriever{EnvVar: "GOFF_TEST_NOT_SET"},
+			wantErr:   assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.retriever.Retrieve(context.Background())
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestRetriever_Format(t *testing.T) {
+	r := inlineretriever.Retriever{FileFormat: "json"}
+	assert.Equal(t, "json", r.Format())
+}
@@ -0,0 +1,44 @@
+package inmemoryretriever
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Retriever is a configuration struct for a retriever that keeps the flags in memory.
+// It is useful for testing purposes when you don't want to create a file to store your flags.
+//
+// Note: the flags are serialized in JSON, the retriever is providing its format so it works
+// whatever the file format of your configuration is.
+type Retriever struct {
+	// Flags contains the configuration of your flags, the key is the name of the flag and the value
+	// is the configuration of the flag as you would write it in your configuration file.
+	Flags map[string]interface{}
+
+	mutex sync.RWMutex
+}
+
+// Retrieve is returning the flags serialized in JSON.
+func (r *Retriever) Retrieve(_ context.Context) ([]byte, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	flags := r.Flags
+	if flags == nil {
+		flags = map[string]interface{}{}
+	}
+	return json.Marshal(flags)
+}
+
+// Format returns the format of the flags returned by the retriever, the flags are always serialized in JSON.
+func (r *Retriever) Format() string {
+	return "json"
+}
+
+// SetFlags replaces the flags returned by the retriever.
+// The new flags will be used during the next refresh of the cache.
+func (r *Retriever) SetFlags(flags map[string]interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Flags = flags
+}
@@ -0,0 +1,56 @@
+package inmemoryretriever_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inmemoryretriever"
+)
+
+func TestRetriever_Retrieve(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]interface{}
+		want  string
+	}{
+		{
+			name: "should return the flags in JSON",
+			flags: map[string]interface{}{
+				"test-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true, "disabled": false},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+			},
+			want: `{"test-flag":{"defaultRule":{"variation":"enabled"},"variations":{"disabled":false,"enabled":true}}}`,
+		},
+		{
+			name:  "should return an empty object if no flags",
+			flags: nil,
+			want:  `{}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := inmemoryretriever.Retriever{Flags: tt.flags}
+			got, err := r.Retrieve(context.Background())
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRetriever_SetFlags(t *testing.T) {
+	r := inmemoryretriever.Retriever{}
+	r.SetFlags(map[string]interface{}{
+		"test-flag": map[string]interface{}{"disable": true},
+	})
+	got, err := r.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"test-flag":{"disable":true}}`, string(got))
+}
+
+func TestRetriever_Format(t *testing.T) {
+	r := inmemoryretriever.Retriever{}
+	assert.Equal(t, "json", r.Format())
+}
@@ -19,6 +19,42 @@ type InitializableRetriever interface {
 	Status() Status
 }
 
+// FormattedRetriever is an optional interface a retriever can implement to provide the format
+// of the flag file it retrieves (yaml, json or toml).
+// It allows to use retrievers with different formats in the same configuration, if the format
+// returned is empty the FileFormat of the configuration is used.
+type FormattedRetriever interface {
+	Retrieve(ctx context.Context) ([]byte, error)
+	Format() string
+}
+
+// DeltaRetriever is an optional interface a retriever can implement to declare that it returns
+// a delta document instead of the full flag configuration.
+// A delta document contains the flags to add or replace in the "upsert" section and the keys of the
+// flags to remove in the "delete" section, it is applied on top of the flags currently loaded.
+//
+// Example (yaml):
+//
+//	upsert:
+//	  my-flag:
+//	    variations: ...
+//	delete:
+//	  - my-old-flag
+type DeltaRetriever interface {
+	Retrieve(ctx context.Context) ([]byte, error)
+	IsDelta() bool
+}
+
+// StreamRetriever is an optional interface a retriever can implement when it is able to push the
+// changes of the flag configuration instead of waiting for the next polling.
+// The callback registered with OnUpdate is called every time a new configuration is available,
+// go-feature-flag will then call Retrieve to refresh the flags without waiting for the PollingInterval.
+// The polling keeps running, so the flags are still refreshed if the stream is interrupted.
+type StreamRetriever interface {
+	Retrieve(ctx context.Context) ([]byte, error)
+	OnUpdate(callback func())
+}
+
 // Status is the status of the retriever.
 // It can be used to check if the retriever is ready to be used.
 // If not ready, we wi will not use it.
@@ -25,6 +25,15 @@ type Retriever struct {
 	// download your feature flag configuration file.
 	AwsConfig aws.Config
 
+	// Endpoint is the URL of an S3-compatible service (ex: MinIO, Ceph, LocalStack).
+	// If empty, the endpoint of AwsConfig is used.
+	Endpoint string
+
+	// ForcePathStyle forces the use of path-style URLs (https://endpoint/bucket/key)
+	// instead of virtual-hosted-style URLs (https://bucket.endpoint/key).
+	// Most S3-compatible services require it.
+	ForcePathStyle bool
+
 	// downloader is an internal field, it is the downloader use by the AWS-SDK
 	downloader s3manageriface.DownloaderAPI
 }
@@ -44,7 +53,14 @@ func (s *Retriever) Retrieve(ctx context.Context) ([]byte, error) {
 		_ = os.Remove(file.Name())
 	}()
 	// Create an AWS session
-	sess, err := session.NewSession(&s.AwsConfig)
+	awsConfig := s.AwsConfig.Copy()
+	if s.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(s.Endpoint)
+	}
+	if s.ForcePathStyle {
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+	sess, err := session.NewSession(awsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -2,10 +2,14 @@ package s3retriever
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
 	"github.com/stretchr/testify/assert"
 
@@ -80,3 +84,34 @@ func Test_s3Retriever_Retrieve(t *testing.T) {
 		})
 	}
 }
+
+func Test_s3Retriever_S3CompatibleEndpoint(t *testing.T) {
+	content, err := os.ReadFile("./testdata/flag-config.yaml")
+	assert.NoError(t, err)
+
+	var receivedHost, receivedPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHost = r.Host
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	s := Retriever{
+		Bucket: "TestBucket",
+		Item:   "flags/flag-config.yaml",
+		AwsConfig: aws.Config{
+			Region:      aws.String("us-east-1"),
+			Credentials: credentials.NewStaticCredentials("minio", "minio123", ""),
+		},
+		Endpoint:       srv.URL,
+		ForcePathStyle: true,
+	}
+	got, err := s.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, string(content), string(got))
+	assert.Equal(t, srv.Listener.Addr().String(), receivedHost, "the bucket should not be in the host")
+	assert.Equal(t, "/TestBucket/flags/flag-config.yaml", receivedPath)
+	assert.Nil(t, s.AwsConfig.Endpoint, "the AwsConfig of the retriever should not be modified")
+}
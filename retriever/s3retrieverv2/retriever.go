@@ -25,6 +25,15 @@ type Retriever struct {
 	// download your feature flag configuration file.
 	AwsConfig *aws.Config
 
+	// Endpoint is the URL of an S3-compatible service (ex: MinIO, Ceph, LocalStack).
+	// If empty, the AWS endpoint resolution is used.
+	Endpoint string
+
+	// ForcePathStyle forces the use of path-style URLs (https://endpoint/bucket/key)
+	// instead of virtual-hosted-style URLs (https://bucket.endpoint/key).
+	// Most S3-compatible services require it.
+	ForcePathStyle bool
+
 	// downloader is an internal field, it is the downloader use by the AWS-SDK
 	downloader DownloaderAPI
 	status     retriever.Status
@@ -41,7 +50,12 @@ func (s *Retriever) Init(ctx context.Context, _ *log.Logger) error {
 			}
 			s.AwsConfig = &cfg
 		}
-		client := s3.NewFromConfig(*s.AwsConfig)
+		client := s3.NewFromConfig(*s.AwsConfig, func(o *s3.Options) {
+			if s.Endpoint != "" {
+				o.BaseEndpoint = aws.String(s.Endpoint)
+			}
+			o.UsePathStyle = s.ForcePathStyle
+		})
 		s.downloader = manager.NewDownloader(client)
 	}
 	s.status = retriever.RetrieverReady
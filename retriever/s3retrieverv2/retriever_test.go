@@ -2,12 +2,17 @@ package s3retrieverv2
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/thomaspoignant/go-feature-flag/retriever"
 	"github.com/thomaspoignant/go-feature-flag/testutils"
-	"os"
-	"testing"
 )
 
 func Test_s3Retriever_Retrieve(t *testing.T) {
@@ -118,3 +123,38 @@ func TestRetriever_Init(t *testing.T) {
 		assert.Equal(t, retriever.RetrieverReady, s.Status())
 	})
 }
+
+func TestRetriever_S3CompatibleEndpoint(t *testing.T) {
+	content, err := os.ReadFile("./testdata/flag-config.yaml")
+	assert.NoError(t, err)
+
+	var receivedHost, receivedPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHost = r.Host
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	s := Retriever{
+		Bucket: "TestBucket",
+		Item:   "flags/flag-config.yaml",
+		AwsConfig: &aws.Config{
+			Region: "us-east-1",
+			Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{AccessKeyID: "minio", SecretAccessKey: "minio123"}, nil
+			}),
+		},
+		Endpoint:       srv.URL,
+		ForcePathStyle: true,
+	}
+	err = s.Init(context.Background(), nil)
+	assert.NoError(t, err)
+
+	got, err := s.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, string(content), string(got))
+	assert.Equal(t, srv.Listener.Addr().String(), receivedHost, "the bucket should not be in the host")
+	assert.Equal(t, "/TestBucket/flags/flag-config.yaml", receivedPath)
+}
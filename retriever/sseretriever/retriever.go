@@ -0,0 +1,301 @@
+package sseretriever
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/internal"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
+)
+
+const (
+	defaultEventName  = "flags"
+	defaultTimeout    = 10 * time.Second
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+	// maxEventSize is the maximum size of an event, it should be large enough to contain a full flag file.
+	maxEventSize = 10 * 1024 * 1024
+)
+
+// Retriever is a configuration struct for a Server-Sent Events retriever.
+// It keeps a connection open to the SSE endpoint and every event named EventName contains
+// a full flag configuration in its data.
+// As soon as a new configuration is received, go-feature-flag refreshes the flags without
+// waiting for the next polling.
+// If the connection is lost, the retriever reconnects with an exponential backoff and the last
+// configuration received is still served in the meantime.
+type Retriever struct {
+	// URL is the URL of the SSE endpoint.
+	URL string
+
+	// Header (optional) are the headers sent when connecting to the endpoint (ex: Authorization).
+	Header http.Header
+
+	// EventName (optional) is the name of the events containing the flag configuration.
+	// default: flags
+	EventName string
+
+	// Timeout is the time we wait for the first configuration when initializing the retriever.
+	// default: 10 seconds
+	Timeout time.Duration
+
+	// MinBackoff (optional) is the time we wait before the first reconnection, it is doubled
+	// after each failed attempt until MaxBackoff.
+	// default: 1 second
+	MinBackoff time.Duration
+
+	// MaxBackoff (optional) is the maximum time we wait between 2 reconnections.
+	// default: 30 seconds
+	MaxBackoff time.Duration
+
+	// httpClient is the http.Client if you want to override it.
+	httpClient internal.HTTPClient
+
+	mutex    sync.RWMutex
+	content  []byte
+	status   retriever.Status
+	onUpdate func()
+	logger   *log.Logger
+	// received is closed when the first configuration is received.
+	received chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// Init opens the connection to the SSE endpoint and waits for the first configuration.
+func (r *Retriever) Init(ctx context.Context, logger *log.Logger) error {
+	if r.URL == "" {
+		r.setStatus(retriever.RetrieverError)
+		return fmt.Errorf("missing mandatory information url=%s", r.URL)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r.mutex.Lock()
+	if r.received != nil {
+		// the stream is already started (Init is called again after an error), the
+		// reconnection is handled by the stream so we only check if a configuration was received.
+		defer r.mutex.Unlock()
+		if r.content == nil {
+			return fmt.Errorf("no configuration received yet from %s", r.URL)
+		}
+		return nil
+	}
+	r.logger = logger
+	r.status = retriever.RetrieverNotReady
+	r.received = make(chan struct{})
+	r.done = make(chan struct{})
+	streamCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.stream(streamCtx)
+	received := r.received
+	r.mutex.Unlock()
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-received:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("no configuration received from %s after %s", r.URL, timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns the status of the retriever, it is ready as soon as a configuration has been received.
+func (r *Retriever) Status() retriever.Status {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.status == "" {
+		return retriever.RetrieverNotReady
+	}
+	return r.status
+}
+
+// Shutdown closes the connection to the SSE endpoint.
+func (r *Retriever) Shutdown(ctx context.Context) error {
+	r.mutex.RLock()
+	cancel, done := r.cancel, r.done
+	r.mutex.RUnlock()
+	if cancel == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Retrieve returns the last configuration received from the SSE endpoint.
+func (r *Retriever) Retrieve(_ context.Context) ([]byte, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.content == nil {
+		return nil, fmt.Errorf("no configuration received yet from %s", r.URL)
+	}
+	return r.content, nil
+}
+
+// OnUpdate registers the function called every time a new configuration is received.
+func (r *Retriever) OnUpdate(callback func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onUpdate = callback
+}
+
+// SetHTTPClient is here if you want to override the default http.Client we are using.
+// The client should not have a timeout since the connection is kept open.
+// It is also used for the tests.
+func (r *Retriever) SetHTTPClient(client internal.HTTPClient) {
+	r.httpClient = client
+}
+
+func (r *Retriever) setStatus(status retriever.Status) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.status = status
+}
+
+// stream keeps the connection to the SSE endpoint open and reconnects with an exponential
+// backoff until the context is cancelled.
+func (r *Retriever) stream(ctx context.Context) {
+	defer close(r.done)
+	minBackoff, maxBackoff := r.MinBackoff, r.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultMinBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = max(defaultMaxBackoff, minBackoff)
+	}
+
+	backoff := minBackoff
+	for {
+		connected, err := r.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			// the connection was established, the next reconnection should be fast
+			backoff = minBackoff
+		}
+		if err != nil {
+			fflog.Printf(r.logger, "error: (SSE retriever) connection to %s lost: %v, reconnecting in %s\n",
+				r.URL, err, backoff)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// connect opens a connection to the SSE endpoint and reads the events until the connection is closed.
+// It returns true if the connection has been established.
+func (r *Retriever) connect(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	for name, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	client := r.httpClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 399 {
+		return false, fmt.Errorf("request to %s failed with code %d", r.URL, resp.StatusCode)
+	}
+
+	eventName := r.EventName
+	if eventName == "" {
+		eventName = defaultEventName
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	var event string
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// an empty line dispatches the event
+			if event == eventName && data.Len() > 0 {
+				r.update(bytes.TrimSuffix(data.Bytes(), []byte("\n")))
+			}
+			event = ""
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comment, usually used as a keep-alive
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return true, err
+	}
+	return true, fmt.Errorf("stream closed by the server")
+}
+
+// update stores the new configuration and notifies that a new configuration is available.
+func (r *Retriever) update(content []byte) {
+	r.mutex.Lock()
+	r.content = bytes.Clone(content)
+	r.status = retriever.RetrieverReady
+	onUpdate := r.onUpdate
+	select {
+	case <-r.received:
+	default:
+		close(r.received)
+	}
+	r.mutex.Unlock()
+
+	if onUpdate != nil {
+		onUpdate()
+	}
+}
@@ -0,0 +1,163 @@
+package sseretriever_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/sseretriever"
+)
+
+const flagConfig = `test-flag:
+  variations:
+    true_var: true
+    false_var: false
+  defaultRule:
+    variation: %s
+`
+
+// sseServer is an SSE endpoint sending the events pushed in the events channel.
+// Every connection receives the events pushed while it is open, sending a value
+// in disconnect ends the current stream.
+type sseServer struct {
+	*httptest.Server
+	events      chan string
+	disconnect  chan struct{}
+	connections atomic.Int32
+	// authorization is the Authorization header of the last connection
+	authorization atomic.Value
+}
+
+func newSSEServer(t *testing.T) *sseServer {
+	s := &sseServer{events: make(chan string, 10), disconnect: make(chan struct{}, 1)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.connections.Add(1)
+		s.authorization.Store(req.Header.Get("Authorization"))
+		assert.Equal(t, "text/event-stream", req.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case event := <-s.events:
+				_, _ = fmt.Fprint(w, event)
+				w.(http.Flusher).Flush()
+			case <-s.disconnect:
+				return
+			case <-req.Context().Done():
+				return
+			}
+		}
+	}))
+	return s
+}
+
+// config returns the flag configuration, as received from the stream (without the last line break).
+func config(defaultVariation string) string {
+	return strings.TrimSuffix(fmt.Sprintf(flagConfig, defaultVariation), "\n")
+}
+
+// flagsEvent returns an SSE event containing the configuration, each line in a data field.
+func flagsEvent(defaultVariation string) string {
+	event := "event: flags\n"
+	for _, line := range strings.Split(config(defaultVariation), "\n") {
+		event += "data: " + line + "\n"
+	}
+	return event + "\n"
+}
+
+func TestRetriever_Stream(t *testing.T) {
+	server := newSSEServer(t)
+	defer server.Close()
+
+	r := &sseretriever.Retriever{URL: server.URL, Timeout: 2 * time.Second}
+	var updates atomic.Int32
+	r.OnUpdate(func() { updates.Add(1) })
+	assert.Equal(t, retriever.RetrieverNotReady, r.Status())
+
+	server.events <- ": keep-alive\n\n"
+	server.events <- "event: other\ndata: ignored\n\n"
+	server.events <- flagsEvent("false_var")
+	err := r.Init(context.Background(), nil)
+	require.NoError(t, err)
+	defer func() { _ = r.Shutdown(context.Background()) }()
+	assert.Equal(t, retriever.RetrieverReady, r.Status())
+
+	got, err := r.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, config("false_var"), string(got))
+
+	server.events <- flagsEvent("true_var")
+	assert.Eventually(t, func() bool {
+		got, _ := r.Retrieve(context.Background())
+		return string(got) == config("true_var")
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), updates.Load())
+}
+
+func TestRetriever_Reconnect(t *testing.T) {
+	server := newSSEServer(t)
+	defer server.Close()
+
+	r := &sseretriever.Retriever{
+		URL:        server.URL,
+		Header:     http.Header{"Authorization": []string{"Bearer token"}},
+		MinBackoff: 10 * time.Millisecond,
+	}
+	server.events <- flagsEvent("false_var")
+	require.NoError(t, r.Init(context.Background(), nil))
+	defer func() { _ = r.Shutdown(context.Background()) }()
+
+	// the connection is lost, the last configuration is still served
+	server.disconnect <- struct{}{}
+	got, err := r.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, config("false_var"), string(got))
+	assert.Equal(t, retriever.RetrieverReady, r.Status())
+
+	// the retriever reconnects and receives the new configuration
+	server.events <- flagsEvent("true_var")
+	assert.Eventually(t, func() bool {
+		got, _ := r.Retrieve(context.Background())
+		return string(got) == config("true_var")
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), server.connections.Load())
+	assert.Equal(t, "Bearer token", server.authorization.Load())
+}
+
+func TestRetriever_InitErrors(t *testing.T) {
+	t.Run("no configuration received", func(t *testing.T) {
+		server := newSSEServer(t)
+		defer server.Close()
+
+		r := &sseretriever.Retriever{URL: server.URL, Timeout: 100 * time.Millisecond}
+		err := r.Init(context.Background(), nil)
+		assert.ErrorContains(t, err, "no configuration received from")
+		defer func() { _ = r.Shutdown(context.Background()) }()
+		assert.Equal(t, retriever.RetrieverNotReady, r.Status())
+		_, err = r.Retrieve(context.Background())
+		assert.Error(t, err)
+
+		// the stream is still open, the configuration can arrive later
+		server.events <- flagsEvent("true_var")
+		assert.Eventually(t, func() bool {
+			return r.Init(context.Background(), nil) == nil
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, retriever.RetrieverReady, r.Status())
+	})
+
+	t.Run("no url", func(t *testing.T) {
+		r := &sseretriever.Retriever{}
+		err := r.Init(context.Background(), nil)
+		assert.ErrorContains(t, err, "missing mandatory information url=")
+		assert.Equal(t, retriever.RetrieverError, r.Status())
+	})
+}
@@ -0,0 +1,163 @@
+package webhookretriever
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/thomaspoignant/go-feature-flag/flagvalidation"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
+)
+
+// defaultMaxBodySize is the maximum size of a configuration pushed to the handler.
+const defaultMaxBodySize = 10 * 1024 * 1024
+
+// Retriever is a configuration struct for a retriever receiving the flag configuration from an HTTP handler.
+// Instead of polling, your deployment pipeline sends the full flag configuration to the handler returned
+// by Handler (with a POST or PUT request), the configuration is validated and go-feature-flag refreshes
+// the flags without waiting for the next polling.
+//
+// Before the first configuration is pushed, the retriever is not ready and does not provide any flag.
+// The polling keeps running and serves the last configuration pushed, if you want to rely only on the
+// pushed configurations you can use a long PollingInterval.
+type Retriever struct {
+	// FileFormat (optional) is the format of the configuration pushed (yaml, json or toml).
+	// Default: yaml
+	FileFormat string
+
+	// Token (mandatory unless DisableAuth is set) the requests to the handler should have the header
+	// "Authorization: Bearer <Token>", the other requests are rejected with a 401.
+	Token string
+
+	// DisableAuth (optional) if set to true, the handler accepts the requests without checking the
+	// Authorization header. Only use it if the handler is protected by another mechanism.
+	// Default: false
+	DisableAuth bool
+
+	// MaxBodySize (optional) is the maximum size in bytes of a configuration pushed to the handler.
+	// Default: 10MB
+	MaxBodySize int64
+
+	mutex    sync.RWMutex
+	content  []byte
+	onUpdate func()
+	logger   *log.Logger
+}
+
+// Init is initializing the retriever, it is ready as soon as a configuration has been pushed.
+func (r *Retriever) Init(_ context.Context, logger *log.Logger) error {
+	if r.Token == "" && !r.DisableAuth {
+		return errors.New("webhook retriever: a Token is required to authenticate the requests (or set DisableAuth)")
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.logger = logger
+	return nil
+}
+
+// Shutdown is doing nothing, the handler should be removed from your HTTP server.
+func (r *Retriever) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// Status returns the status of the retriever, it is ready as soon as a configuration has been pushed.
+func (r *Retriever) Status() retriever.Status {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.content == nil {
+		return retriever.RetrieverNotReady
+	}
+	return retriever.RetrieverReady
+}
+
+// Retrieve returns the last configuration pushed to the handler.
+func (r *Retriever) Retrieve(_ context.Context) ([]byte, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.content == nil {
+		return nil, errors.New("no configuration pushed yet")
+	}
+	return r.content, nil
+}
+
+// Format returns the format of the configuration pushed to the handler.
+func (r *Retriever) Format() string {
+	if r.FileFormat == "" {
+		return "yaml"
+	}
+	return strings.ToLower(r.FileFormat)
+}
+
+// OnUpdate registers the function called every time a new configuration is pushed.
+func (r *Retriever) OnUpdate(callback func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onUpdate = callback
+}
+
+// Handler returns the http.Handler receiving the flag configuration.
+// It answers with:
+//   - 202 if the configuration is valid, the flags are refreshed right after,
+//   - 400 if the configuration is not valid, the error is in the body of the response,
+//   - 401 if the Token is missing or not valid,
+//   - 405 if the method is not POST or PUT.
+func (r *Retriever) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost && req.Method != http.MethodPut {
+			w.Header().Set("Allow", "POST, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !r.isAuthorized(req) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		maxBodySize := r.MaxBodySize
+		if maxBodySize <= 0 {
+			maxBodySize = defaultMaxBodySize
+		}
+		content, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("impossible to read the configuration: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := flagvalidation.ValidateConfiguration(content, r.Format()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.update(content)
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// isAuthorized checks the Authorization header of the request, without a Token every request is
+// rejected unless DisableAuth is set.
+func (r *Retriever) isAuthorized(req *http.Request) bool {
+	if r.DisableAuth {
+		return true
+	}
+	return r.Token != "" && subtle.ConstantTimeCompare(
+		[]byte(req.Header.Get("Authorization")), []byte("Bearer "+r.Token)) == 1
+}
+
+// update stores the new configuration and notifies that a new configuration is available.
+func (r *Retriever) update(content []byte) {
+	r.mutex.Lock()
+	r.content = bytes.Clone(content)
+	onUpdate, logger := r.onUpdate, r.logger
+	r.mutex.Unlock()
+
+	fflog.Printf(logger, "info: (webhook retriever) new flag configuration received\n")
+	if onUpdate != nil {
+		onUpdate()
+	}
+}
@@ -0,0 +1,143 @@
+package webhookretriever_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/retriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/webhookretriever"
+)
+
+const validConfig = `test-flag:
+  variations:
+    A: true
+  defaultRule:
+    variation: A
+`
+
+func TestRetriever_Handler(t *testing.T) {
+	tests := []struct {
+		name          string
+		retriever     *webhookretriever.Retriever
+		method        string
+		authorization string
+		body          string
+		wantStatus    int
+		wantBody      string
+		wantContent   string
+	}{
+		{
+			name:        "valid configuration",
+			retriever:   &webhookretriever.Retriever{DisableAuth: true},
+			method:      http.MethodPost,
+			body:        validConfig,
+			wantStatus:  http.StatusAccepted,
+			wantContent: validConfig,
+		},
+		{
+			name:        "valid configuration with PUT",
+			retriever:   &webhookretriever.Retriever{DisableAuth: true},
+			method:      http.MethodPut,
+			body:        validConfig,
+			wantStatus:  http.StatusAccepted,
+			wantContent: validConfig,
+		},
+		{
+			name:       "invalid configuration",
+			retriever:  &webhookretriever.Retriever{DisableAuth: true},
+			method:     http.MethodPost,
+			body:       "test-flag:\n  variations:\n    A: true\n  defaultRule:\n    variation: B\n",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "invalid flag configuration",
+		},
+		{
+			name:       "invalid format",
+			retriever:  &webhookretriever.Retriever{FileFormat: "json", DisableAuth: true},
+			method:     http.MethodPost,
+			body:       validConfig,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "configuration too large",
+			retriever:  &webhookretriever.Retriever{MaxBodySize: 10, DisableAuth: true},
+			method:     http.MethodPost,
+			body:       validConfig,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "impossible to read the configuration",
+		},
+		{
+			name:       "method not allowed",
+			retriever:  &webhookretriever.Retriever{DisableAuth: true},
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:          "valid token",
+			retriever:     &webhookretriever.Retriever{Token: "secret"},
+			method:        http.MethodPost,
+			authorization: "Bearer secret",
+			body:          validConfig,
+			wantStatus:    http.StatusAccepted,
+			wantContent:   validConfig,
+		},
+		{
+			name:          "invalid token",
+			retriever:     &webhookretriever.Retriever{Token: "secret"},
+			method:        http.MethodPost,
+			authorization: "Bearer invalid",
+			body:          validConfig,
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "missing token",
+			retriever:  &webhookretriever.Retriever{Token: "secret"},
+			method:     http.MethodPost,
+			body:       validConfig,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates := 0
+			tt.retriever.OnUpdate(func() { updates++ })
+			assert.NoError(t, tt.retriever.Init(context.Background(), nil))
+			assert.Equal(t, retriever.RetrieverNotReady, tt.retriever.Status())
+
+			req := httptest.NewRequest(tt.method, "/flags", strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			tt.retriever.Handler().ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+
+			got, err := tt.retriever.Retrieve(context.Background())
+			if tt.wantContent == "" {
+				assert.Error(t, err)
+				assert.Equal(t, 0, updates)
+				assert.Equal(t, retriever.RetrieverNotReady, tt.retriever.Status())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantContent, string(got))
+			assert.Equal(t, 1, updates)
+			assert.Equal(t, retriever.RetrieverReady, tt.retriever.Status())
+		})
+	}
+}
+
+func TestRetriever_AuthenticationByDefault(t *testing.T) {
+	r := &webhookretriever.Retriever{}
+	assert.Error(t, r.Init(context.Background(), nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/flags", strings.NewReader(validConfig))
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, retriever.RetrieverNotReady, r.Status())
+}
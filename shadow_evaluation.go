@@ -0,0 +1,141 @@
+package ffclient
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+)
+
+// EvaluationChange is a flag that returns a different value for an evaluation context
+// between 2 flag configurations.
+// When the flag does not exist in one of the configurations, its value is nil and its variation is empty.
+type EvaluationChange struct {
+	FlagKey      string
+	ContextKey   string
+	OldValue     interface{}
+	NewValue     interface{}
+	OldVariation string
+	NewVariation string
+}
+
+// DiffEvaluations evaluates the contexts against all the flags of the 2 flag configurations and returns
+// the evaluations that have a different value.
+// It is a safety tool to know which contexts would flip before rolling out a configuration change,
+// no event is collected.
+// fileFormat is the format of both configurations (yaml, json or toml), if empty yaml is used.
+func DiffEvaluations(oldConfig []byte, newConfig []byte, fileFormat string, contexts []ffcontext.Context,
+) ([]EvaluationChange, error) {
+	oldFlags, err := flagsFromConfiguration(oldConfig, fileFormat)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to read the old configuration: %v", err)
+	}
+	newFlags, err := flagsFromConfiguration(newConfig, fileFormat)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to read the new configuration: %v", err)
+	}
+	return diffEvaluations(oldFlags, newFlags, contexts, flag.Context{}, flag.Context{}), nil
+}
+
+// EvaluateAgainst evaluates the contexts against all the flags of this instance (the old configuration) and
+// of the other instance (the new configuration) and returns the evaluations that have a different value.
+// The EvaluationContextEnrichment and the Environment of each instance are used for its evaluations,
+// no event is collected.
+func (g *GoFeatureFlag) EvaluateAgainst(other *GoFeatureFlag, contexts []ffcontext.Context,
+) ([]EvaluationChange, error) {
+	if g == nil || g.cache == nil || other == nil || other.cache == nil {
+		return nil, fmt.Errorf("impossible to compare the evaluations, go-feature-flag is not initialised")
+	}
+	oldFlags, err := g.cache.AllFlags()
+	if err != nil {
+		return nil, err
+	}
+	newFlags, err := other.cache.AllFlags()
+	if err != nil {
+		return nil, err
+	}
+	return diffEvaluations(oldFlags, newFlags, contexts, g.shadowFlagContext(), other.shadowFlagContext()), nil
+}
+
+// shadowFlagContext returns the flag.Context used to evaluate the flags of this instance in a shadow evaluation.
+func (g *GoFeatureFlag) shadowFlagContext() flag.Context {
+	flagCtx := flag.Context{
+		EvaluationContextEnrichment: maps.Clone(g.config.EvaluationContextEnrichment),
+		Hasher:                      g.config.BucketingHasher,
+	}
+	flagCtx.AddIntoEvaluationContextEnrichment("env", g.config.Environment)
+	return flagCtx
+}
+
+// flagsFromConfiguration converts a flag configuration into flags ready to be evaluated.
+func flagsFromConfiguration(content []byte, fileFormat string) (map[string]flag.Flag, error) {
+	dtoFlags, err := cache.New(cache.NewNotificationService(nil), nil).ConvertToFlagStruct(content, fileFormat)
+	if err != nil {
+		return nil, err
+	}
+	flagCache := cache.NewInMemoryCache(nil)
+	flagCache.Init(dtoFlags)
+	return flagCache.All(), nil
+}
+
+// diffEvaluations evaluates every flag of both sets for each context and keeps the evaluations
+// with a different value, ordered by flag key and then in the order of the contexts.
+// oldFlagCtx and newFlagCtx are the flag.Context used to evaluate the old and the new flags.
+func diffEvaluations(oldFlags map[string]flag.Flag, newFlags map[string]flag.Flag, contexts []ffcontext.Context,
+	oldFlagCtx flag.Context, newFlagCtx flag.Context,
+) []EvaluationChange {
+	flagKeys := make([]string, 0, len(oldFlags)+len(newFlags))
+	for key := range oldFlags {
+		flagKeys = append(flagKeys, key)
+	}
+	for key := range newFlags {
+		if _, ok := oldFlags[key]; !ok {
+			flagKeys = append(flagKeys, key)
+		}
+	}
+	sort.Strings(flagKeys)
+
+	evaluate := func(flags map[string]flag.Flag, baseFlagCtx flag.Context, key string, ctx ffcontext.Context,
+	) (interface{}, string) {
+		f, ok := flags[key]
+		if !ok {
+			return nil, ""
+		}
+		flagCtx := baseFlagCtx
+		flagCtx.EvaluationContextEnrichment = maps.Clone(baseFlagCtx.EvaluationContextEnrichment)
+		flagCtx.GetPrerequisite = func(prerequisiteKey string) (flag.Flag, error) {
+			prerequisite, ok := flags[prerequisiteKey]
+			if !ok {
+				return nil, newEvaluationError(prerequisiteKey, flag.ErrorCodeFlagNotFound, ErrFlagNotFound,
+					errorFlagNotAvailable, prerequisiteKey)
+			}
+			return prerequisite, nil
+		}
+		value, details := f.Value(key, ctx, flagCtx)
+		return value, details.Variant
+	}
+
+	changes := make([]EvaluationChange, 0)
+	for _, key := range flagKeys {
+		for _, ctx := range contexts {
+			oldValue, oldVariation := evaluate(oldFlags, oldFlagCtx, key, ctx)
+			newValue, newVariation := evaluate(newFlags, newFlagCtx, key, ctx)
+			if reflect.DeepEqual(oldValue, newValue) {
+				continue
+			}
+			changes = append(changes, EvaluationChange{
+				FlagKey:      key,
+				ContextKey:   ctx.GetKey(),
+				OldValue:     oldValue,
+				NewValue:     newValue,
+				OldVariation: oldVariation,
+				NewVariation: newVariation,
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,96 @@
+package ffclient_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+)
+
+const shadowConfig = `rollout-flag:
+  variations:
+    enabled: true
+    disabled: false
+  defaultRule:
+    percentage:
+      enabled: %d
+      disabled: %d
+unchanged-flag:
+  variations:
+    A: "value-A"
+  defaultRule:
+    variation: A
+`
+
+func TestDiffEvaluations(t *testing.T) {
+	oldConfig := []byte(fmt.Sprintf(shadowConfig, 50, 50))
+	newConfig := []byte(fmt.Sprintf(shadowConfig, 100, 0))
+	contexts := make([]ffcontext.Context, 0, 100)
+	for i := 0; i < 100; i++ {
+		contexts = append(contexts, ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i)))
+	}
+
+	changes, err := ffclient.DiffEvaluations(oldConfig, newConfig, "yaml", contexts)
+	require.NoError(t, err)
+
+	// the contexts flipping are the ones that were in the disabled part of the rollout.
+	oldClient := newShadowClient(t, oldConfig)
+	defer oldClient.Close()
+	flipped := map[string]bool{}
+	for _, change := range changes {
+		assert.Equal(t, "rollout-flag", change.FlagKey)
+		assert.Equal(t, false, change.OldValue)
+		assert.Equal(t, true, change.NewValue)
+		assert.Equal(t, "disabled", change.OldVariation)
+		assert.Equal(t, "enabled", change.NewVariation)
+		flipped[change.ContextKey] = true
+	}
+	assert.NotEmpty(t, flipped)
+	for _, ctx := range contexts {
+		value, err := oldClient.BoolVariation("rollout-flag", ctx, true)
+		assert.NoError(t, err)
+		assert.Equal(t, !value, flipped[ctx.GetKey()], "context %s", ctx.GetKey())
+	}
+
+	// the same comparison can be done between 2 running instances.
+	newClient := newShadowClient(t, newConfig)
+	defer newClient.Close()
+	instanceChanges, err := oldClient.EvaluateAgainst(newClient, contexts)
+	assert.NoError(t, err)
+	assert.Equal(t, changes, instanceChanges)
+}
+
+func TestDiffEvaluationsFlagRemoved(t *testing.T) {
+	oldConfig := []byte(fmt.Sprintf(shadowConfig, 50, 50))
+	newConfig := []byte("unchanged-flag:\n  variations:\n    A: \"value-A\"\n  defaultRule:\n    variation: A\n")
+
+	changes, err := ffclient.DiffEvaluations(oldConfig, newConfig, "yaml",
+		[]ffcontext.Context{ffcontext.NewEvaluationContext("user-1")})
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, "rollout-flag", changes[0].FlagKey)
+		assert.Nil(t, changes[0].NewValue)
+		assert.Equal(t, "", changes[0].NewVariation)
+	}
+
+	_, err = ffclient.DiffEvaluations(oldConfig, []byte("invalid: [yaml"), "yaml", nil)
+	assert.ErrorContains(t, err, "impossible to read the new configuration")
+}
+
+func newShadowClient(t *testing.T, config []byte) *ffclient.GoFeatureFlag {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, config, 0o600))
+	client, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: path},
+	})
+	require.NoError(t, err)
+	return client
+}
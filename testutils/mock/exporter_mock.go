@@ -14,15 +14,27 @@ type Exporter struct {
 	ExpectedNumberErr int
 	CurrentNumberErr  int
 	Bulk              bool
+	MaxBatchBytes     int
+	// ContextAttributes are the custom attributes of the evaluation context requested in the events.
+	ContextAttributes []string
+	// ExpectedNumberPanic is the number of calls to Export that panic before exporting the events.
+	ExpectedNumberPanic int
 
-	mutex sync.Mutex
-	once  sync.Once
+	currentNumberPanic int
+	nbExport           int
+	mutex              sync.Mutex
+	once               sync.Once
 }
 
 func (m *Exporter) Export(ctx context.Context, logger *log.Logger, events []exporter.FeatureEvent) error {
 	m.once.Do(m.initMutex)
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	m.nbExport++
+	if m.ExpectedNumberPanic > m.currentNumberPanic {
+		m.currentNumberPanic++
+		panic("mock exporter panic")
+	}
 	m.ExportedEvents = append(m.ExportedEvents, events...)
 	if m.Err != nil {
 		if m.ExpectedNumberErr > m.CurrentNumberErr {
@@ -40,6 +52,22 @@ func (m *Exporter) GetExportedEvents() []exporter.FeatureEvent {
 	return m.ExportedEvents
 }
 
+// GetNbExport returns the number of calls to Export.
+func (m *Exporter) GetNbExport() int {
+	m.once.Do(m.initMutex)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.nbExport
+}
+
+func (m *Exporter) GetMaxBatchBytes() int {
+	return m.MaxBatchBytes
+}
+
+func (m *Exporter) GetContextAttributes() []string {
+	return m.ContextAttributes
+}
+
 func (m *Exporter) IsBulk() bool {
 	return m.Bulk
 }
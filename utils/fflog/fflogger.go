@@ -0,0 +1,129 @@
+package fflog
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// FFLogger is the logger used by GO Feature Flag.
+// It logs with the LeveledLogger if it is set and falls back on the LegacyLogger otherwise.
+// A nil FFLogger is valid and is not logging anything.
+type FFLogger struct {
+	LeveledLogger *slog.Logger
+	LegacyLogger  *log.Logger
+}
+
+// Debug logs a message at debug level, it is never written in the legacy logger.
+func (f *FFLogger) Debug(msg string, args ...any) {
+	if f == nil || f.LeveledLogger == nil {
+		return
+	}
+	f.LeveledLogger.Debug(msg, args...)
+}
+
+// Info logs a message at info level.
+func (f *FFLogger) Info(msg string, args ...any) {
+	f.log(slog.LevelInfo, msg, args...)
+}
+
+// Warn logs a message at warning level.
+func (f *FFLogger) Warn(msg string, args ...any) {
+	f.log(slog.LevelWarn, msg, args...)
+}
+
+// Error logs a message at error level.
+func (f *FFLogger) Error(msg string, args ...any) {
+	f.log(slog.LevelError, msg, args...)
+}
+
+func (f *FFLogger) log(level slog.Level, msg string, args ...any) {
+	if f == nil {
+		return
+	}
+	if f.LeveledLogger != nil {
+		f.LeveledLogger.Log(context.Background(), level, msg, args...)
+		return
+	}
+	if f.LegacyLogger == nil {
+		return
+	}
+	// the structured fields are written at the end of the message as key=value.
+	record := slog.NewRecord(time.Time{}, level, msg, 0)
+	record.Add(args...)
+	var attrs strings.Builder
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs.WriteString(" " + attr.String())
+		return true
+	})
+	Printf(f.LegacyLogger, "%s: %s%s", legacyLevelName(level), msg, attrs.String())
+}
+
+// GetLogLogger returns a *log.Logger for the components of GO Feature Flag
+// using a *log.Logger (exporters, retrievers, notifiers ...).
+// When the leveled logger is set, the level of each message is deduced from its prefix
+// (ex: "error: ...", "warning: ..."), the messages without prefix are logged at info level.
+func (f *FFLogger) GetLogLogger() *log.Logger {
+	if f == nil {
+		return nil
+	}
+	if f.LeveledLogger == nil {
+		return f.LegacyLogger
+	}
+	return log.New(&leveledWriter{logger: f.LeveledLogger}, "", 0)
+}
+
+// legacyLevelName returns the prefix used in the legacy logs for this level.
+func legacyLevelName(level slog.Level) string {
+	if level == slog.LevelWarn {
+		return "warning"
+	}
+	return strings.ToLower(level.String())
+}
+
+// leveledWriter is writing the messages of a *log.Logger in a *slog.Logger.
+type leveledWriter struct {
+	logger *slog.Logger
+}
+
+func (w *leveledWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimSpace(p))
+	// the date added by Printf is removed, the leveled logger has its own.
+	if strings.HasPrefix(msg, "[") {
+		if end := strings.Index(msg, "] "); end != -1 {
+			if _, err := time.Parse(LogDateFormat, msg[1:end]); err == nil {
+				msg = msg[end+2:]
+			}
+		}
+	}
+	level, msg := parseLevel(msg)
+	w.logger.Log(context.Background(), level, msg)
+	return len(p), nil
+}
+
+// parseLevel deduces the level of a message from its prefix and returns the message without it.
+func parseLevel(msg string) (slog.Level, string) {
+	prefixes := []struct {
+		name  string
+		level slog.Level
+	}{
+		{name: "error", level: slog.LevelError},
+		{name: "warning", level: slog.LevelWarn},
+		{name: "warn", level: slog.LevelWarn},
+		{name: "info", level: slog.LevelInfo},
+		{name: "debug", level: slog.LevelDebug},
+	}
+	lowerMsg := strings.ToLower(msg)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(lowerMsg, prefix.name+":") {
+			return prefix.level, strings.TrimSpace(msg[len(prefix.name)+1:])
+		}
+		if strings.HasPrefix(lowerMsg, prefix.name+" ") {
+			return prefix.level, msg
+		}
+	}
+	return slog.LevelInfo, msg
+}
@@ -0,0 +1,77 @@
+package fflog_test
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomaspoignant/go-feature-flag/utils/fflog"
+)
+
+func TestFFLogger_nil(t *testing.T) {
+	var logger *fflog.FFLogger
+	assert.NotPanics(t, func() {
+		logger.Debug("debug")
+		logger.Info("info")
+		logger.Warn("warn")
+		logger.Error("error")
+	})
+	assert.Nil(t, logger.GetLogLogger())
+	assert.NotPanics(t, func() { (&fflog.FFLogger{}).Error("error", slog.String("key", "value")) })
+}
+
+func TestFFLogger_legacyLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &fflog.FFLogger{LegacyLogger: log.New(&buf, "", 0)}
+	logger.Debug("not logged")
+	logger.Warn("impossible to load the flags", slog.String("path", "flags.yaml"))
+	assert.NotContains(t, buf.String(), "not logged")
+	assert.Contains(t, buf.String(), "warning: impossible to load the flags path=flags.yaml")
+	assert.Equal(t, logger.LegacyLogger, logger.GetLogLogger())
+}
+
+func TestFFLogger_leveledLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &fflog.FFLogger{
+		LeveledLogger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	logger.Debug("flags refreshed", slog.Int64("generation", 2))
+	assert.Contains(t, buf.String(), `level=DEBUG msg="flags refreshed" generation=2`)
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "error prefix",
+			message: "error: [cache] invalid configuration for flag my-flag",
+			want:    `level=ERROR msg="[cache] invalid configuration for flag my-flag"`,
+		},
+		{
+			name:    "warning prefix",
+			message: "warning: impossible to extract the value",
+			want:    `level=WARN msg="impossible to extract the value"`,
+		},
+		{
+			name:    "error sentence",
+			message: "error while calling the notifier",
+			want:    `level=ERROR msg="error while calling the notifier"`,
+		},
+		{
+			name:    "no prefix",
+			message: "flag my-flag added",
+			want:    `level=INFO msg="flag my-flag added"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			fflog.Printf(logger.GetLogLogger(), "%s", tt.message)
+			assert.Contains(t, buf.String(), tt.want)
+			assert.NotContains(t, buf.String(), "[20")
+		})
+	}
+}
@@ -1,21 +1,32 @@
 package ffclient
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"maps"
 	"time"
 
+	"github.com/mitchellh/copystructure"
 	"github.com/thomaspoignant/go-feature-flag/exporter"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
 	"github.com/thomaspoignant/go-feature-flag/internal/flagstate"
 	"github.com/thomaspoignant/go-feature-flag/model"
 )
 
+// KillSwitchFlagKey is the key of the flag used as a global kill switch when Config.EnableKillSwitch is true.
+const KillSwitchFlagKey = "gofeatureflag.disableAll"
+
+// objectVariationType is the expected type of ObjectVariation, it accepts only the JSON objects and arrays.
+const objectVariationType = "object"
+
 const (
 	errorFlagNotAvailable = "flag %v is not present or disabled"
 	errorWrongVariation   = "wrong variation used for flag %v"
+	errorMissingKey       = "the evaluation context of flag %v has no targeting key"
 )
 
 // BoolVariation return the value of the flag in boolean.
@@ -233,6 +244,44 @@ func (g *GoFeatureFlag) JSONVariationDetails(flagKey string, ctx ffcontext.Conte
 	return res, err
 }
 
+// ObjectVariation return the value of the flag for a flag containing a structured value
+// (map[string]interface{} for a JSON object or []interface{} for a JSON array).
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func ObjectVariation(flagKey string, ctx ffcontext.Context, defaultValue interface{}) (interface{}, error) {
+	return ff.ObjectVariation(flagKey, ctx, defaultValue)
+}
+
+// ObjectVariation return the value of the flag for a flag containing a structured value
+// (map[string]interface{} for a JSON object or []interface{} for a JSON array).
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) ObjectVariation(flagKey string, ctx ffcontext.Context, defaultValue interface{},
+) (interface{}, error) {
+	res, err := g.ObjectVariationDetails(flagKey, ctx, defaultValue)
+	return res.Value, err
+}
+
+// ObjectVariationDetails return the details of the evaluation for a flag containing a structured value.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func ObjectVariationDetails(flagKey string, ctx ffcontext.Context, defaultValue interface{},
+) (model.VariationResult[interface{}], error) {
+	return ff.ObjectVariationDetails(flagKey, ctx, defaultValue)
+}
+
+// ObjectVariationDetails return the details of the evaluation for a flag containing a structured value.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) ObjectVariationDetails(flagKey string, ctx ffcontext.Context, defaultValue interface{},
+) (model.VariationResult[interface{}], error) {
+	res, err := getVariation[interface{}](g, flagKey, ctx, defaultValue, objectVariationType)
+	notifyVariation(g, flagKey, ctx, res)
+	return res, err
+}
+
 // AllFlagsState return the values of all the flags for a specific user.
 // If valid field is false it means that we had an error when checking the flags.
 func AllFlagsState(ctx ffcontext.Context) flagstate.AllFlags {
@@ -247,6 +296,8 @@ func GetFlagsFromCache() (map[string]flag.Flag, error) {
 }
 
 // AllFlagsState return a flagstate.AllFlags that contains all the flags for a specific user.
+// A flag that fails to evaluate is part of the result and is marked as failed with its error code.
+// Every flag evaluated is sent to the data exporter, like a normal variation call.
 func (g *GoFeatureFlag) AllFlagsState(evaluationCtx ffcontext.Context) flagstate.AllFlags {
 	flags := map[string]flag.Flag{}
 	if g == nil {
@@ -263,66 +314,137 @@ func (g *GoFeatureFlag) AllFlagsState(evaluationCtx ffcontext.Context) flagstate
 		}
 	}
 
+	ruleCtx := g.ruleContext(evaluationCtx)
+	killSwitchOn := g.isKillSwitchOn(ruleCtx)
+	targetingKeyMissing := g.isTargetingKeyMissing(evaluationCtx)
+	configGeneration, configHash := g.getConfigGeneration()
+	// the prerequisites are evaluated with the flags of the same snapshot.
+	getPrerequisite := func(flagKey string) (flag.Flag, error) {
+		prerequisite, ok := flags[flagKey]
+		if !ok {
+			return nil, newEvaluationError(
+				flagKey, flag.ErrorCodeFlagNotFound, ErrFlagNotFound, errorFlagNotAvailable, flagKey)
+		}
+		return prerequisite, nil
+	}
 	allFlags := flagstate.NewAllFlags()
 	for key, currentFlag := range flags {
+		// the archived flags are still evaluated individually, but they are not part of the bulk evaluation.
+		if internalFlag, ok := currentFlag.(*flag.InternalFlag); ok && internalFlag.IsArchived() {
+			continue
+		}
 		flagCtx := flag.Context{
-			EvaluationContextEnrichment: g.config.EvaluationContextEnrichment,
+			EvaluationContextEnrichment: maps.Clone(g.config.EvaluationContextEnrichment),
 			DefaultSdkValue:             nil,
+			Disabled:                    killSwitchOn && key != KillSwitchFlagKey,
+			Hasher:                      g.config.BucketingHasher,
+			GetPrerequisite:             getPrerequisite,
 		}
 		flagCtx.AddIntoEvaluationContextEnrichment("env", g.config.Environment)
-		flagValue, resolutionDetails := currentFlag.Value(key, evaluationCtx, flagCtx)
+		flagValue, resolutionDetails, overridden := g.overrides.evaluate(key, currentFlag, evaluationCtx)
+		switch {
+		case targetingKeyMissing:
+			flagValue, resolutionDetails = nil, flag.ResolutionDetails{
+				Variant:   flag.VariationSDKDefault,
+				Reason:    flag.ReasonTargetingKeyMissing,
+				ErrorCode: flag.ErrorCodeTargetingKeyMissing,
+				Metadata:  currentFlag.GetMetadata(),
+			}
+		case !overridden:
+			flagValue, resolutionDetails = currentFlag.Value(key, ruleCtx, flagCtx)
+		}
 
-		// if the flag is disabled, we are ignoring it.
-		if resolutionDetails.Reason == flag.ReasonDisabled {
-			allFlags.AddFlag(key, flagstate.FlagState{
+		state := newFlagState(currentFlag, flagValue, resolutionDetails)
+		allFlags.AddFlag(key, state)
+		g.metrics.record(key, state.VariationType, state.Reason)
+
+		// each flag evaluated is collected like a normal evaluation.
+		if state.TrackEvents {
+			event := g.newFeatureEvent(evaluationCtx, key, state.Value,
+				state.VariationType, state.Failed, currentFlag.GetVersion())
+			event.Reason = string(state.Reason)
+			event.Metadata = state.Metadata
+			event.ConfigGeneration, event.ConfigHash = configGeneration, configHash
+			g.CollectEventData(event)
+		}
+	}
+	return allFlags
+}
+
+// newFlagState converts the result of the evaluation of a flag into the state returned by AllFlagsState.
+func newFlagState(currentFlag flag.Flag, flagValue interface{}, resolutionDetails flag.ResolutionDetails,
+) flagstate.FlagState {
+	switch v := flagValue; v.(type) {
+	case int, float64, bool, string, []interface{}, map[string]interface{}:
+		return flagstate.FlagState{
+			Value:         v,
+			Timestamp:     time.Now().Unix(),
+			VariationType: resolutionDetails.Variant,
+			TrackEvents:   currentFlag.IsTrackEvents(),
+			Failed:        resolutionDetails.ErrorCode != "",
+			ErrorCode:     resolutionDetails.ErrorCode,
+			Reason:        resolutionDetails.Reason,
+			Metadata:      resolutionDetails.Metadata,
+			Version:       currentFlag.GetVersion(),
+		}
+
+	default:
+		// if the flag is disabled, expired, if a prerequisite failed, if the user is excluded
+		// by the layer or the holdout or if the targeting key is missing, there is no value to return.
+		if resolutionDetails.Reason == flag.ReasonDisabled || resolutionDetails.Reason == flag.ReasonExpired ||
+			resolutionDetails.Reason == flag.ReasonPrerequisiteFailed ||
+			resolutionDetails.Reason == flag.ReasonLayerExcluded || resolutionDetails.Reason == flag.ReasonHoldout ||
+			resolutionDetails.Reason == flag.ReasonTargetingKeyMissing {
+			return flagstate.FlagState{
 				Timestamp:   time.Now().Unix(),
 				TrackEvents: currentFlag.IsTrackEvents(),
 				Failed:      resolutionDetails.ErrorCode != "",
 				ErrorCode:   resolutionDetails.ErrorCode,
 				Reason:      resolutionDetails.Reason,
 				Metadata:    resolutionDetails.Metadata,
-			})
-			continue
+				Version:     currentFlag.GetVersion(),
+			}
 		}
 
-		switch v := flagValue; v.(type) {
-		case int, float64, bool, string, []interface{}, map[string]interface{}:
-			allFlags.AddFlag(key, flagstate.FlagState{
-				Value:         v,
-				Timestamp:     time.Now().Unix(),
-				VariationType: resolutionDetails.Variant,
-				TrackEvents:   currentFlag.IsTrackEvents(),
-				Failed:        resolutionDetails.ErrorCode != "",
-				ErrorCode:     resolutionDetails.ErrorCode,
-				Reason:        resolutionDetails.Reason,
-				Metadata:      resolutionDetails.Metadata,
-			})
-
-		default:
-			defaultVariationName := flag.VariationSDKDefault
-			defaultVariationValue := currentFlag.GetVariationValue(defaultVariationName)
-			allFlags.AddFlag(
-				key,
-				flagstate.FlagState{
-					Value:         defaultVariationValue,
-					Timestamp:     time.Now().Unix(),
-					VariationType: defaultVariationName,
-					TrackEvents:   currentFlag.IsTrackEvents(),
-					Failed:        true,
-					ErrorCode:     flag.ErrorCodeTypeMismatch,
-					Reason:        flag.ReasonError,
-					Metadata:      resolutionDetails.Metadata,
-				})
+		defaultVariationName := flag.VariationSDKDefault
+		defaultVariationValue := currentFlag.GetVariationValue(defaultVariationName)
+		return flagstate.FlagState{
+			Value:         defaultVariationValue,
+			Timestamp:     time.Now().Unix(),
+			VariationType: defaultVariationName,
+			TrackEvents:   currentFlag.IsTrackEvents(),
+			Failed:        true,
+			ErrorCode:     flag.ErrorCodeTypeMismatch,
+			Reason:        flag.ReasonError,
+			Metadata:      resolutionDetails.Metadata,
+			Version:       currentFlag.GetVersion(),
 		}
 	}
-	return allFlags
 }
 
 // GetFlagsFromCache returns all the flags present in the cache with their
 // current state when calling this method. If cache hasn't been initialized, an
 // error reporting this is returned.
+//
+// The flags returned are a copy of the cache, modifying them has no impact on the evaluations.
+// This method is not evaluating the flags, so no event is sent to the data exporter.
 func (g *GoFeatureFlag) GetFlagsFromCache() (map[string]flag.Flag, error) {
-	return g.cache.AllFlags()
+	if g == nil {
+		return nil, fmt.Errorf("go-feature-flag is not initialised")
+	}
+	if g.config.Offline {
+		return map[string]flag.Flag{}, nil
+	}
+
+	flags, err := g.cache.AllFlags()
+	if err != nil {
+		return nil, err
+	}
+	flagsCopy, err := copystructure.Copy(flags)
+	if err != nil {
+		return nil, fmt.Errorf("impossible to copy the flags from the cache: %w", err)
+	}
+	return flagsCopy.(map[string]flag.Flag), nil
 }
 
 // RawVariation return the raw value of the flag (without any types).
@@ -341,18 +463,59 @@ func (g *GoFeatureFlag) RawVariation(flagKey string, ctx ffcontext.Context, sdkD
 // It returns an error if the cache is not init or if the flag is not present or disabled.
 func (g *GoFeatureFlag) getFlagFromCache(flagKey string) (flag.Flag, error) {
 	f, err := g.cache.GetFlag(flagKey)
+	if errors.Is(err, cache.ErrNotInitialised) {
+		return f, newEvaluationError(
+			flagKey, flag.ErrorCodeProviderNotReady, ErrConfigNotLoaded, errorFlagNotAvailable, flagKey)
+	}
 	if err != nil {
-		return f, fmt.Errorf(errorFlagNotAvailable, flagKey)
+		return f, newEvaluationError(flagKey, flag.ErrorCodeFlagNotFound, ErrFlagNotFound, errorFlagNotAvailable, flagKey)
 	}
 	return f, nil
 }
 
 // CollectEventData is collecting events and sending them to the data exporter to be stored.
 func (g *GoFeatureFlag) CollectEventData(event exporter.FeatureEvent) {
-	if g != nil && g.dataExporter != nil {
-		// Add event in the exporter
-		g.dataExporter.AddEvent(event)
+	if g != nil && (g.dataExporter != nil || len(g.additionalDataExporters) > 0) {
+		if event.Environment == "" {
+			event.Environment = g.config.Environment
+		}
+		if !g.sampler.sample(&event) {
+			return
+		}
+		// Add event in the exporters, each exporter is filtering the flags it exports.
+		if g.dataExporter != nil {
+			g.dataExporter.AddEvent(event)
+		}
+		for _, dataExporter := range g.additionalDataExporters {
+			dataExporter.AddEvent(event)
+		}
+	}
+}
+
+// newFeatureEvent creates the event of an evaluation made by this instance.
+// The custom attributes of the evaluation context are copied in the event only if an exporter asks for them.
+func (g *GoFeatureFlag) newFeatureEvent(ctx ffcontext.Context, flagKey string, value interface{},
+	variation string, failed bool, version string) exporter.FeatureEvent {
+	event := exporter.NewFeatureEventWithClock(g.config.Clock, ctx, flagKey, value, variation, failed, version, "SERVER")
+	custom := ctx.GetCustom()
+	for _, key := range g.eventContextAttributes {
+		if attribute, ok := custom[key]; ok {
+			if event.EvaluationContext == nil {
+				event.EvaluationContext = make(map[string]interface{}, len(g.eventContextAttributes))
+			}
+			event.EvaluationContext[key] = attribute
+		}
+	}
+	return event
+}
+
+// getConfigGeneration returns the generation of the flag configuration and the hash of its content,
+// they are stamped on the events of the evaluations.
+func (g *GoFeatureFlag) getConfigGeneration() (int64, string) {
+	if g == nil || g.cache == nil {
+		return 0, ""
 	}
+	return g.cache.GetGeneration()
 }
 
 // notifyVariation is logging the evaluation result for a flag
@@ -363,9 +526,14 @@ func notifyVariation[T model.JSONType](
 	ctx ffcontext.Context,
 	result model.VariationResult[T],
 ) {
+	if g != nil {
+		g.metrics.record(flagKey, result.VariationType, result.Reason)
+	}
 	if result.TrackEvents {
-		event := exporter.NewFeatureEvent(ctx, flagKey, result.Value, result.VariationType, result.Failed, result.Version,
-			"SERVER")
+		event := g.newFeatureEvent(ctx, flagKey, result.Value, result.VariationType, result.Failed, result.Version)
+		event.Reason = string(result.Reason)
+		event.Metadata = result.Metadata
+		event.ConfigGeneration, event.ConfigHash = g.getConfigGeneration()
 		g.CollectEventData(event)
 	}
 }
@@ -374,6 +542,14 @@ func notifyVariation[T model.JSONType](
 // contain a valid model.VariationResult
 func getVariation[T model.JSONType](
 	g *GoFeatureFlag, flagKey string, evaluationCtx ffcontext.Context, sdkDefaultValue T, expectedType string,
+) (model.VariationResult[T], error) {
+	return evaluateVariation[T](g, flagKey, evaluationCtx, sdkDefaultValue, expectedType, false)
+}
+
+// evaluateVariation is evaluating the flag, if dryRun is true no event is collected for the prerequisites.
+func evaluateVariation[T model.JSONType](
+	g *GoFeatureFlag, flagKey string, evaluationCtx ffcontext.Context, sdkDefaultValue T, expectedType string,
+	dryRun bool, opts ...EvaluationOption,
 ) (model.VariationResult[T], error) {
 	if g == nil {
 		return model.VariationResult[T]{
@@ -383,7 +559,8 @@ func getVariation[T model.JSONType](
 			Reason:        flag.ReasonError,
 			ErrorCode:     flag.ErrorCodeProviderNotReady,
 			Cacheable:     false,
-		}, fmt.Errorf("go-feature-flag is not initialised, default value is used")
+		}, newEvaluationError(flagKey, flag.ErrorCodeProviderNotReady, ErrConfigNotLoaded,
+			"go-feature-flag is not initialised, default value is used")
 	}
 	if g.config.Offline {
 		return model.VariationResult[T]{
@@ -395,74 +572,188 @@ func getVariation[T model.JSONType](
 		}, nil
 	}
 
-	f, err := g.getFlagFromCache(flagKey)
+	options := newEvaluationOptions(opts)
+	f, err := options.flag, error(nil)
+	if f == nil {
+		f, err = g.getFlagFromCache(flagKey)
+	}
 	if err != nil {
-		varResult := model.VariationResult[T]{
-			Value:         sdkDefaultValue,
-			VariationType: flag.VariationSDKDefault,
-			ErrorCode:     flag.ErrorCodeFlagNotFound,
-			Failed:        true,
-			Reason:        flag.ReasonError,
-			Cacheable:     false,
-		}
-		if f != nil {
-			varResult.TrackEvents = f.IsTrackEvents()
-			varResult.Version = f.GetVersion()
-			varResult.Metadata = f.GetMetadata()
+		return flagNotFoundResult[T](g, flagKey, f, err, evaluationCtx, sdkDefaultValue, expectedType)
+	}
+
+	if g.isTargetingKeyMissing(evaluationCtx) {
+		return errorResult[T](f, sdkDefaultValue, flag.ReasonTargetingKeyMissing, flag.ErrorCodeTargetingKeyMissing),
+			newEvaluationError(flagKey, flag.ErrorCodeTargetingKeyMissing, ErrTargetingKeyMissing, errorMissingKey, flagKey)
+	}
+
+	ruleCtx := g.ruleContext(evaluationCtx)
+	flagCtx := g.newFlagContext(flagKey, evaluationCtx, ruleCtx, sdkDefaultValue, options, dryRun)
+	flagValue, resolutionDetails, overridden := g.overrides.evaluate(flagKey, f, evaluationCtx)
+	cacheHit := false
+	if !overridden {
+		flagValue, resolutionDetails, cacheHit = g.evaluateWithCache(flagKey, f, evaluationCtx, ruleCtx, flagCtx)
+	}
+
+	v, ok := convertValue[T](flagValue, expectedType)
+	if !ok {
+		return errorResult[T](f, sdkDefaultValue, flag.ReasonError, flag.ErrorCodeTypeMismatch),
+			newEvaluationError(flagKey, flag.ErrorCodeTypeMismatch, ErrWrongVariationType, errorWrongVariation, flagKey)
+	}
+
+	return model.VariationResult[T]{
+		Value:         v,
+		VariationType: resolutionDetails.Variant,
+		Reason:        resolutionDetails.Reason,
+		ErrorCode:     resolutionDetails.ErrorCode,
+		Failed:        resolutionDetails.ErrorCode != "",
+		TrackEvents:   f.IsTrackEvents() && !(cacheHit && g.config.EvaluationCacheSkipEvents),
+		Version:       f.GetVersion(),
+		Cacheable:     resolutionDetails.Cacheable,
+		Metadata:      constructMetadata(f, resolutionDetails),
+		RuleIndex:     resolutionDetails.RuleIndex,
+
+		ProgressiveRollout: resolutionDetails.ProgressiveRollout,
+		VariationMetadata:  resolutionDetails.VariationMetadata,
+	}, nil
+}
+
+// flagNotFoundResult returns the result of an evaluation when the flag can't be retrieved,
+// the value of the DefaultValueProvider is used if there is one.
+func flagNotFoundResult[T model.JSONType](
+	g *GoFeatureFlag, flagKey string, f flag.Flag, err error, evaluationCtx ffcontext.Context, sdkDefaultValue T,
+	expectedType string,
+) (model.VariationResult[T], error) {
+	if g.config.DefaultValueProvider != nil {
+		if fallback, ok := getFallbackValue[T](g, flagKey, evaluationCtx, expectedType); ok {
+			return model.VariationResult[T]{
+				Value:         fallback,
+				VariationType: flag.VariationSDKDefault,
+				Reason:        flag.ReasonFallback,
+				Cacheable:     false,
+			}, nil
 		}
-		return varResult, err
 	}
+	errorCode := flag.ErrorCodeFlagNotFound
+	var evaluationErr *EvaluationError
+	if errors.As(err, &evaluationErr) {
+		errorCode = evaluationErr.ErrorCode
+	}
+	return errorResult[T](f, sdkDefaultValue, flag.ReasonError, errorCode), err
+}
+
+// errorResult returns the result of a failed evaluation, the SDK default value is used.
+// f can be nil if the flag is not available.
+func errorResult[T model.JSONType](
+	f flag.Flag, sdkDefaultValue T, reason flag.ResolutionReason, errorCode flag.ErrorCode,
+) model.VariationResult[T] {
+	result := model.VariationResult[T]{
+		Value:         sdkDefaultValue,
+		VariationType: flag.VariationSDKDefault,
+		Reason:        reason,
+		ErrorCode:     errorCode,
+		Failed:        true,
+		Cacheable:     false,
+	}
+	if f != nil {
+		result.TrackEvents = f.IsTrackEvents()
+		result.Version = f.GetVersion()
+		result.Metadata = f.GetMetadata()
+	}
+	return result
+}
 
+// newFlagContext creates the context used to evaluate the flag, the flag is disabled if the kill switch is on.
+// If dryRun is true no event is collected for the prerequisites.
+func (g *GoFeatureFlag) newFlagContext(flagKey string, evaluationCtx ffcontext.Context, ruleCtx ffcontext.Context,
+	sdkDefaultValue interface{}, options evaluationOptions, dryRun bool) flag.Context {
 	flagCtx := flag.Context{
 		DefaultSdkValue:             sdkDefaultValue,
 		EvaluationContextEnrichment: maps.Clone(g.config.EvaluationContextEnrichment),
+		GetPrerequisite:             g.getFlagFromCache,
+		EvaluationDate:              options.evaluationTime,
+		Hasher:                      g.config.BucketingHasher,
+	}
+	if g.config.TrackPrerequisiteEvents && !dryRun {
+		flagCtx.OnPrerequisiteEvaluated = func(
+			prerequisiteKey string, prerequisite flag.Flag, value interface{}, details flag.ResolutionDetails) {
+			if prerequisite.IsTrackEvents() {
+				event := g.newFeatureEvent(evaluationCtx, prerequisiteKey,
+					value, details.Variant, details.ErrorCode != "", prerequisite.GetVersion())
+				event.Reason = string(details.Reason)
+				event.Metadata = prerequisite.GetMetadata()
+				event.ConfigGeneration, event.ConfigHash = g.getConfigGeneration()
+				g.CollectEventData(event)
+			}
+		}
 	}
 	flagCtx.AddIntoEvaluationContextEnrichment("env", g.config.Environment)
-	flagValue, resolutionDetails := f.Value(flagKey, evaluationCtx, flagCtx)
-
-	var convertedValue interface{}
-	switch value := flagValue.(type) {
-	case float64:
-		// this part ensures that we convert float64 value into int if we call IntVariation on a float64 value.
-		if expectedType == "int" {
-			convertedValue = int(value)
-		} else {
-			convertedValue = value
+	flagCtx.Disabled = flagKey != KillSwitchFlagKey && g.isKillSwitchOn(ruleCtx)
+	return flagCtx
+}
+
+// evaluateWithCache evaluates the flag, the result is read from the evaluation cache when it is enabled.
+// It returns true if the result comes from the cache.
+func (g *GoFeatureFlag) evaluateWithCache(flagKey string, f flag.Flag, evaluationCtx ffcontext.Context,
+	ruleCtx ffcontext.Context, flagCtx flag.Context) (interface{}, flag.ResolutionDetails, bool) {
+	// the results are cached only for the evaluations at the current date of a context with a key.
+	useCache := g.evaluationCache != nil && flagCtx.EvaluationDate.IsZero() &&
+		evaluationCtx != nil && evaluationCtx.GetKey() != ""
+	cacheKey := evaluationCacheKey{}
+	if useCache {
+		cacheKey, useCache = newEvaluationCacheKey(flagKey, f, ruleCtx, flagCtx)
+	}
+	if useCache {
+		if flagValue, resolutionDetails, ok := g.evaluationCache.get(cacheKey); ok {
+			return flagValue, resolutionDetails, true
 		}
-	default:
-		convertedValue = value
+	}
+	flagValue, resolutionDetails := f.Value(flagKey, ruleCtx, flagCtx)
+	if useCache && resolutionDetails.Cacheable && resolutionDetails.ErrorCode == "" {
+		g.evaluationCache.set(cacheKey, flagValue, resolutionDetails)
+	}
+	return flagValue, resolutionDetails, false
+}
+
+// convertValue converts the value of a flag into the type expected by the variation call,
+// it returns false if the value is not of the expected type.
+func convertValue[T model.JSONType](value interface{}, expectedType string) (T, bool) {
+	// this part ensures that we convert float64 value into int if we call IntVariation on a float64 value.
+	if floatValue, ok := value.(float64); ok && expectedType == "int" {
+		value = int(floatValue)
 	}
 
 	var v T
-	switch val := convertedValue.(type) {
+	if expectedType == objectVariationType {
+		// an object variation accepts only the structured values.
+		switch value.(type) {
+		case map[string]interface{}, []interface{}, nil:
+		default:
+			return v, false
+		}
+	}
+
+	switch val := value.(type) {
 	case T:
 		v = val
 	default:
 		if val != nil {
-			return model.VariationResult[T]{
-				Value:         sdkDefaultValue,
-				VariationType: flag.VariationSDKDefault,
-				Reason:        flag.ReasonError,
-				ErrorCode:     flag.ErrorCodeTypeMismatch,
-				Failed:        true,
-				TrackEvents:   f.IsTrackEvents(),
-				Version:       f.GetVersion(),
-				Metadata:      f.GetMetadata(),
-			}, fmt.Errorf(errorWrongVariation, flagKey)
+			return v, false
 		}
 	}
+	return v, true
+}
 
-	return model.VariationResult[T]{
-		Value:         v,
-		VariationType: resolutionDetails.Variant,
-		Reason:        resolutionDetails.Reason,
-		ErrorCode:     resolutionDetails.ErrorCode,
-		Failed:        resolutionDetails.ErrorCode != "",
-		TrackEvents:   f.IsTrackEvents(),
-		Version:       f.GetVersion(),
-		Cacheable:     resolutionDetails.Cacheable,
-		Metadata:      constructMetadata(f, resolutionDetails),
-	}, nil
+// getFallbackValue calls the DefaultValueProvider for a flag that does not exist,
+// it returns false if the provider fails or returns a value of the wrong type.
+func getFallbackValue[T model.JSONType](
+	g *GoFeatureFlag, flagKey string, evaluationCtx ffcontext.Context, expectedType string,
+) (T, bool) {
+	var v T
+	value, err := g.config.DefaultValueProvider(flagKey, evaluationCtx)
+	if err != nil || value == nil {
+		return v, false
+	}
+	return convertValue[T](value, expectedType)
 }
 
 // constructMetadata is the internal generic func used to enhance model.VariationResult adding
@@ -479,3 +770,105 @@ func constructMetadata(f flag.Flag, resolutionDetails flag.ResolutionDetails) ma
 	metadata["evaluatedRuleName"] = *resolutionDetails.RuleName
 	return metadata
 }
+
+// ruleContext returns the evaluation context used to evaluate the rules of the flags,
+// enriched by the ContextEnricher and containing the DefaultContextAttributes.
+func (g *GoFeatureFlag) ruleContext(evaluationCtx ffcontext.Context) ffcontext.Context {
+	return g.withDefaultContextAttributes(g.enrichContext(evaluationCtx))
+}
+
+// enrichContext calls the ContextEnricher of the configuration, if the enricher fails
+// the original evaluation context is returned.
+func (g *GoFeatureFlag) enrichContext(evaluationCtx ffcontext.Context) (enrichedCtx ffcontext.Context) {
+	if g.config.ContextEnricher == nil || evaluationCtx == nil {
+		return evaluationCtx
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.logger.Error("panic in the ContextEnricher", slog.Any("panic", r))
+			enrichedCtx = evaluationCtx
+		}
+	}()
+	enrichedCtx, err := g.config.ContextEnricher(evaluationCtx)
+	if err != nil {
+		g.logger.Error("impossible to enrich the evaluation context", slog.Any("error", err))
+		return evaluationCtx
+	}
+	if enrichedCtx == nil {
+		return evaluationCtx
+	}
+	return enrichedCtx
+}
+
+// withDefaultContextAttributes returns a copy of the evaluation context containing the DefaultContextAttributes
+// of the configuration, the attributes of the evaluation context win on conflict.
+// The copy keeps the key, the anonymous status and the secondary key of the evaluation context.
+func (g *GoFeatureFlag) withDefaultContextAttributes(evaluationCtx ffcontext.Context) ffcontext.Context {
+	if len(g.config.DefaultContextAttributes) == 0 || evaluationCtx == nil {
+		return evaluationCtx
+	}
+	custom := maps.Clone(g.config.DefaultContextAttributes)
+	maps.Copy(custom, evaluationCtx.GetCustom())
+
+	if _, ok := evaluationCtx.(ffcontext.EvaluationContext); ok {
+		builder := ffcontext.NewEvaluationContextBuilder(evaluationCtx.GetKey())
+		for name, value := range custom {
+			builder.AddCustom(name, value)
+		}
+		return builder.Secondary(ffcontext.GetSecondaryKey(evaluationCtx)).Build()
+	}
+	// the other types of context (ex: MultiContext) compute their key and their anonymous status
+	// from their own attributes, so we keep them and only add the default attributes.
+	return contextWithDefaultAttributes{Context: evaluationCtx, custom: custom}
+}
+
+// contextWithDefaultAttributes is an evaluation context with the DefaultContextAttributes of the configuration.
+type contextWithDefaultAttributes struct {
+	ffcontext.Context
+	custom map[string]interface{}
+}
+
+// GetCustom return the attributes of the context merged with the DefaultContextAttributes.
+func (c contextWithDefaultAttributes) GetCustom() map[string]interface{} {
+	return c.custom
+}
+
+// AddCustomAttribute adds a custom attribute to the copy, the original context is not modified.
+func (c contextWithDefaultAttributes) AddCustomAttribute(name string, value interface{}) {
+	if name != "" {
+		c.custom[name] = value
+	}
+}
+
+// GetSecondaryKey return the secondary key of the original context.
+func (c contextWithDefaultAttributes) GetSecondaryKey() string {
+	return ffcontext.GetSecondaryKey(c.Context)
+}
+
+// isTargetingKeyMissing returns true if the configuration requires a targeting key
+// and the evaluation context has none.
+func (g *GoFeatureFlag) isTargetingKeyMissing(evaluationCtx ffcontext.Context) bool {
+	return g.config.RequireTargetingKey && (evaluationCtx == nil || evaluationCtx.GetKey() == "")
+}
+
+// isKillSwitchOn returns true if the kill switch is enabled and the flag KillSwitchFlagKey is evaluated
+// to true for this evaluation context.
+func (g *GoFeatureFlag) isKillSwitchOn(evaluationCtx ffcontext.Context) bool {
+	if !g.config.EnableKillSwitch || g.config.Offline || evaluationCtx == nil {
+		return false
+	}
+	killSwitch, err := g.getFlagFromCache(KillSwitchFlagKey)
+	if err != nil {
+		return false
+	}
+	flagCtx := flag.Context{
+		DefaultSdkValue:             false,
+		EvaluationContextEnrichment: maps.Clone(g.config.EvaluationContextEnrichment),
+		GetPrerequisite:             g.getFlagFromCache,
+		Hasher:                      g.config.BucketingHasher,
+	}
+	flagCtx.AddIntoEvaluationContextEnrichment("env", g.config.Environment)
+	value, _ := killSwitch.Value(KillSwitchFlagKey, evaluationCtx, flagCtx)
+	on, ok := value.(bool)
+	return ok && on
+}
@@ -0,0 +1,136 @@
+package ffclient
+
+import (
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/model"
+)
+
+// The Batch variations are evaluating one flag for several evaluation contexts in one call.
+// The flag is retrieved only once from the cache and each evaluation is done exactly like with the
+// other variations: the results are in the same order as the contexts and one event is sent to the
+// data exporter for each context.
+// If some evaluations fail, the default value is used for them and the error of the first failed
+// evaluation is returned.
+
+// BoolVariationBatch return the values of a boolean flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+func BoolVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue bool) ([]bool, error) {
+	return ff.BoolVariationBatch(flagKey, ctxs, defaultValue)
+}
+
+// BoolVariationBatch return the values of a boolean flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) BoolVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue bool,
+) ([]bool, error) {
+	return getVariationBatch[bool](g, flagKey, ctxs, defaultValue, "bool")
+}
+
+// IntVariationBatch return the values of an int flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+func IntVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue int) ([]int, error) {
+	return ff.IntVariationBatch(flagKey, ctxs, defaultValue)
+}
+
+// IntVariationBatch return the values of an int flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) IntVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue int,
+) ([]int, error) {
+	return getVariationBatch[int](g, flagKey, ctxs, defaultValue, "int")
+}
+
+// Float64VariationBatch return the values of a float64 flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+func Float64VariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue float64) ([]float64, error) {
+	return ff.Float64VariationBatch(flagKey, ctxs, defaultValue)
+}
+
+// Float64VariationBatch return the values of a float64 flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) Float64VariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue float64,
+) ([]float64, error) {
+	return getVariationBatch[float64](g, flagKey, ctxs, defaultValue, "float64")
+}
+
+// StringVariationBatch return the values of a string flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+func StringVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue string) ([]string, error) {
+	return ff.StringVariationBatch(flagKey, ctxs, defaultValue)
+}
+
+// StringVariationBatch return the values of a string flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) StringVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue string,
+) ([]string, error) {
+	return getVariationBatch[string](g, flagKey, ctxs, defaultValue, "string")
+}
+
+// JSONArrayVariationBatch return the values of a []interface{} flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+func JSONArrayVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue []interface{},
+) ([][]interface{}, error) {
+	return ff.JSONArrayVariationBatch(flagKey, ctxs, defaultValue)
+}
+
+// JSONArrayVariationBatch return the values of a []interface{} flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONArrayVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue []interface{},
+) ([][]interface{}, error) {
+	return getVariationBatch[[]interface{}](g, flagKey, ctxs, defaultValue, "[]interface{}")
+}
+
+// JSONVariationBatch return the values of a map[string]interface{} flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+func JSONVariationBatch(flagKey string, ctxs []ffcontext.Context, defaultValue map[string]interface{},
+) ([]map[string]interface{}, error) {
+	return ff.JSONVariationBatch(flagKey, ctxs, defaultValue)
+}
+
+// JSONVariationBatch return the values of a map[string]interface{} flag for each evaluation context.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value for each context.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONVariationBatch(flagKey string, ctxs []ffcontext.Context,
+	defaultValue map[string]interface{},
+) ([]map[string]interface{}, error) {
+	return getVariationBatch[map[string]interface{}](g, flagKey, ctxs, defaultValue, "map[string]interface{}")
+}
+
+// getVariationBatch evaluates a flag for each evaluation context, the flag is retrieved only once from the cache.
+func getVariationBatch[T model.JSONType](
+	g *GoFeatureFlag, flagKey string, ctxs []ffcontext.Context, sdkDefaultValue T, expectedType string,
+) ([]T, error) {
+	var opts []EvaluationOption
+	if g != nil && !g.config.Offline {
+		if f, err := g.getFlagFromCache(flagKey); err == nil {
+			opts = append(opts, withFlag(f))
+		}
+	}
+
+	values := make([]T, 0, len(ctxs))
+	var firstErr error
+	for _, ctx := range ctxs {
+		res, err := evaluateVariation[T](g, flagKey, ctx, sdkDefaultValue, expectedType, false, opts...)
+		notifyVariation(g, flagKey, ctx, res)
+		values = append(values, res.Value)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return values, firstErr
+}
@@ -0,0 +1,102 @@
+package ffclient_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inmemoryretriever"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+)
+
+func TestVariationBatch(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"bool-flag": map[string]interface{}{
+					"variations": map[string]interface{}{"enabled": true, "disabled": false},
+					"targeting": []interface{}{
+						map[string]interface{}{"query": `beta eq true`, "variation": "enabled"},
+					},
+					"defaultRule": map[string]interface{}{
+						"percentage": map[string]interface{}{"enabled": 30, "disabled": 70},
+					},
+				},
+				"string-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"a": "value-a", "b": "value-b"},
+					"defaultRule": map[string]interface{}{"percentage": map[string]interface{}{"a": 50, "b": 50}},
+				},
+			},
+		},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 1000,
+			Exporter:         exp,
+		},
+	})
+	require.NoError(t, err)
+
+	ctxs := make([]ffcontext.Context, 0, 50)
+	for i := 0; i < 50; i++ {
+		ctxs = append(ctxs, ffcontext.NewEvaluationContextBuilder(fmt.Sprintf("user-%d", i)).
+			AddCustom("beta", i%10 == 0).
+			Build())
+	}
+
+	boolValues, err := gffClient.BoolVariationBatch("bool-flag", ctxs, false)
+	assert.NoError(t, err)
+	stringValues, err := gffClient.StringVariationBatch("string-flag", ctxs, "default")
+	assert.NoError(t, err)
+	require.Len(t, boolValues, len(ctxs))
+	require.Len(t, stringValues, len(ctxs))
+
+	// the results are the same as the individual calls, in the same order as the contexts
+	for i, ctx := range ctxs {
+		boolValue, err := gffClient.BoolVariation("bool-flag", ctx, false)
+		assert.NoError(t, err)
+		assert.Equal(t, boolValue, boolValues[i], "bool value of %s", ctx.GetKey())
+
+		stringValue, err := gffClient.StringVariation("string-flag", ctx, "default")
+		assert.NoError(t, err)
+		assert.Equal(t, stringValue, stringValues[i], "string value of %s", ctx.GetKey())
+	}
+	gffClient.Close()
+
+	// one event per context for the batch evaluations and for the individual calls
+	assert.Len(t, exp.GetExportedEvents(), 4*len(ctxs))
+}
+
+func TestVariationBatch_errors(t *testing.T) {
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"string-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"a": "value-a"},
+					"defaultRule": map[string]interface{}{"variation": "a"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer gffClient.Close()
+	ctxs := []ffcontext.Context{ffcontext.NewEvaluationContext("user-1"), ffcontext.NewEvaluationContext("user-2")}
+
+	values, err := gffClient.BoolVariationBatch("not-existing-flag", ctxs, true)
+	assert.ErrorIs(t, err, ffclient.ErrFlagNotFound)
+	assert.Equal(t, []bool{true, true}, values)
+
+	values, err = gffClient.BoolVariationBatch("string-flag", ctxs, true)
+	assert.ErrorIs(t, err, ffclient.ErrWrongVariationType)
+	assert.Equal(t, []bool{true, true}, values)
+
+	values, err = gffClient.BoolVariationBatch("string-flag", nil, true)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
@@ -0,0 +1,139 @@
+package ffclient
+
+import (
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/model"
+)
+
+// The DryRun variations are evaluating the flag exactly like the other variations,
+// but no event is sent to the data exporter and no metric is recorded.
+// They are useful for admin tools that need to test an evaluation context without polluting the analytics.
+// With the option WithEvaluationTime, they can evaluate the flag as if it was evaluated at another time.
+
+// BoolVariationDryRun return the details of the evaluation for boolean flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func BoolVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue bool,
+	opts ...EvaluationOption,
+) (model.VariationResult[bool], error) {
+	return ff.BoolVariationDryRun(flagKey, ctx, defaultValue, opts...)
+}
+
+// BoolVariationDryRun return the details of the evaluation for boolean flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) BoolVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue bool,
+	opts ...EvaluationOption,
+) (model.VariationResult[bool], error) {
+	return evaluateVariation[bool](g, flagKey, ctx, defaultValue, "bool", true, opts...)
+}
+
+// IntVariationDryRun return the details of the evaluation for int flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func IntVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue int,
+	opts ...EvaluationOption,
+) (model.VariationResult[int], error) {
+	return ff.IntVariationDryRun(flagKey, ctx, defaultValue, opts...)
+}
+
+// IntVariationDryRun return the details of the evaluation for int flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) IntVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue int,
+	opts ...EvaluationOption,
+) (model.VariationResult[int], error) {
+	return evaluateVariation[int](g, flagKey, ctx, defaultValue, "int", true, opts...)
+}
+
+// Float64VariationDryRun return the details of the evaluation for float64 flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func Float64VariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue float64,
+	opts ...EvaluationOption,
+) (model.VariationResult[float64], error) {
+	return ff.Float64VariationDryRun(flagKey, ctx, defaultValue, opts...)
+}
+
+// Float64VariationDryRun return the details of the evaluation for float64 flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) Float64VariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue float64,
+	opts ...EvaluationOption,
+) (model.VariationResult[float64], error) {
+	return evaluateVariation[float64](g, flagKey, ctx, defaultValue, "float64", true, opts...)
+}
+
+// StringVariationDryRun return the details of the evaluation for string flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func StringVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue string,
+	opts ...EvaluationOption,
+) (model.VariationResult[string], error) {
+	return ff.StringVariationDryRun(flagKey, ctx, defaultValue, opts...)
+}
+
+// StringVariationDryRun return the details of the evaluation for string flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) StringVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue string,
+	opts ...EvaluationOption,
+) (model.VariationResult[string], error) {
+	return evaluateVariation[string](g, flagKey, ctx, defaultValue, "string", true, opts...)
+}
+
+// JSONArrayVariationDryRun return the details of the evaluation for []interface{} flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func JSONArrayVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue []interface{},
+	opts ...EvaluationOption,
+) (model.VariationResult[[]interface{}], error) {
+	return ff.JSONArrayVariationDryRun(flagKey, ctx, defaultValue, opts...)
+}
+
+// JSONArrayVariationDryRun return the details of the evaluation for []interface{} flag without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONArrayVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue []interface{},
+	opts ...EvaluationOption,
+) (model.VariationResult[[]interface{}], error) {
+	return evaluateVariation[[]interface{}](g, flagKey, ctx, defaultValue, "[]interface{}", true, opts...)
+}
+
+// JSONVariationDryRun return the details of the evaluation for map[string]interface{} flag
+// without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func JSONVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue map[string]interface{},
+	opts ...EvaluationOption,
+) (model.VariationResult[map[string]interface{}], error) {
+	return ff.JSONVariationDryRun(flagKey, ctx, defaultValue, opts...)
+}
+
+// JSONVariationDryRun return the details of the evaluation for map[string]interface{} flag
+// without collecting any event.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONVariationDryRun(flagKey string, ctx ffcontext.Context, defaultValue map[string]interface{},
+	opts ...EvaluationOption,
+) (model.VariationResult[map[string]interface{}], error) {
+	return evaluateVariation[map[string]interface{}](g, flagKey, ctx, defaultValue, "map[string]interface{}", true,
+		opts...)
+}
+
+// RawVariationDryRun return the raw value of the flag (without any types) without collecting any event.
+// This raw result is mostly used by software built on top of go-feature-flag such as
+// go-feature-flag relay proxy.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) RawVariationDryRun(flagKey string, ctx ffcontext.Context, sdkDefaultValue interface{},
+	opts ...EvaluationOption,
+) (model.RawVarResult, error) {
+	res, err := evaluateVariation[interface{}](g, flagKey, ctx, sdkDefaultValue, "interface{}", true, opts...)
+	return model.RawVarResult(res), err
+}
@@ -0,0 +1,217 @@
+package ffclient_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffcontext"
+	"github.com/thomaspoignant/go-feature-flag/internal/flag"
+	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestVariationDryRun(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	reader := sdkmetric.NewManualReader()
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval:            5 * time.Second,
+		Retriever:                  &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		OpenTelemetryMeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+	ctx := ffcontext.NewEvaluationContext("random-key")
+
+	dryRun, err := gffClient.BoolVariationDryRun("test-flag", ctx, false)
+	assert.NoError(t, err)
+	rawDryRun, err := gffClient.RawVariationDryRun("test-flag", ctx, false)
+	assert.NoError(t, err)
+	details, err := gffClient.BoolVariationDetails("test-flag", ctx, false)
+	assert.NoError(t, err)
+	gffClient.Close()
+
+	// the dry run evaluation is the same as a normal evaluation
+	assert.Equal(t, details, dryRun)
+	assert.True(t, dryRun.Value)
+	assert.Equal(t, flag.ReasonTargetingMatch, dryRun.Reason)
+	assert.Equal(t, true, rawDryRun.Value)
+	assert.Equal(t, dryRun.Reason, rawDryRun.Reason)
+
+	// only the normal evaluation has been collected
+	assert.Len(t, exp.GetExportedEvents(), 1)
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &rm))
+	if assert.Len(t, rm.ScopeMetrics, 1) {
+		sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+		assert.Len(t, sum.DataPoints, 1)
+		assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	}
+}
+
+func TestVariationDryRunWithEvaluationTime(t *testing.T) {
+	flagFile, err := os.CreateTemp("", "evaluation-time-*.yaml")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(flagFile.Name()) }()
+	_ = os.WriteFile(flagFile.Name(), []byte(`progressive-flag:
+  variations:
+    A: false
+    B: true
+  defaultRule:
+    progressiveRollout:
+      initial:
+        variation: A
+        percentage: 0
+        date: 2024-01-01T00:00:00Z
+      end:
+        variation: B
+        percentage: 100
+        date: 2024-01-11T00:00:00Z
+scheduled-flag:
+  variations:
+    A: false
+    B: true
+  defaultRule:
+    variation: A
+  scheduledRollout:
+    - date: 2100-01-01T00:00:00Z
+      defaultRule:
+        variation: B
+`), os.ModePerm)
+
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile.Name()},
+		DataExporter: ffclient.DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 10000,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	tests := []struct {
+		name           string
+		evaluationTime time.Time
+		wantPercentage float64
+	}{
+		{
+			name:           "before the rollout",
+			evaluationTime: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			wantPercentage: 0,
+		},
+		{
+			name:           "middle of the rollout",
+			evaluationTime: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC),
+			wantPercentage: 50,
+		},
+		{
+			name:           "after the rollout",
+			evaluationTime: time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+			wantPercentage: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nbUsers := 1000
+			nbB := 0
+			for i := 0; i < nbUsers; i++ {
+				ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+				res, err := gffClient.BoolVariationDryRun("progressive-flag", ctx, false,
+					ffclient.WithEvaluationTime(tt.evaluationTime))
+				assert.NoError(t, err)
+				if assert.NotNil(t, res.ProgressiveRollout) {
+					assert.InDelta(t, tt.wantPercentage, res.ProgressiveRollout.Percentage, 0.001)
+				}
+				if res.Value {
+					nbB++
+				}
+			}
+			assert.InDelta(t, tt.wantPercentage, float64(nbB)*100/float64(nbUsers), 5)
+		})
+	}
+
+	// the scheduled steps are applied for the evaluation time without changing the flag
+	ctx := ffcontext.NewEvaluationContext("user-key")
+	res, err := gffClient.BoolVariationDryRun("scheduled-flag", ctx, false,
+		ffclient.WithEvaluationTime(time.Date(2100, 1, 2, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, err)
+	assert.True(t, res.Value)
+	res, err = gffClient.BoolVariationDryRun("scheduled-flag", ctx, false)
+	assert.NoError(t, err)
+	assert.False(t, res.Value)
+
+	// no event is collected
+	assert.Len(t, exp.GetExportedEvents(), 0)
+}
+
+func TestVariationDryRunWithEvaluationTime_scheduledRollout(t *testing.T) {
+	flagFile, err := os.CreateTemp("", "evaluation-time-*.yaml")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(flagFile.Name()) }()
+	_ = os.WriteFile(flagFile.Name(), []byte(`scheduled-rollout-flag:
+  variations:
+    A: false
+    B: true
+  defaultRule:
+    percentage:
+      A: 100
+      B: 0
+  scheduledRollout:
+    - date: 2024-01-01T00:00:00Z
+      defaultRule:
+        percentage:
+          A: 50
+          B: 50
+    - date: 2024-02-01T00:00:00Z
+      defaultRule:
+        percentage:
+          A: 0
+          B: 100
+`), os.ModePerm)
+
+	gffClient, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile.Name()},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	percentageOfB := func(opts ...ffclient.EvaluationOption) float64 {
+		nbUsers := 1000
+		nbB := 0
+		for i := 0; i < nbUsers; i++ {
+			ctx := ffcontext.NewEvaluationContext(fmt.Sprintf("user-%d", i))
+			res, err := gffClient.BoolVariationDryRun("scheduled-rollout-flag", ctx, false, opts...)
+			assert.NoError(t, err)
+			if res.Value {
+				nbB++
+			}
+		}
+		return float64(nbB) * 100 / float64(nbUsers)
+	}
+
+	// the evaluations at the current date apply all the steps
+	assert.Equal(t, float64(100), percentageOfB())
+
+	// the evaluations in the past are not impacted by the steps applied at the current date
+	beforeSteps := ffclient.WithEvaluationTime(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC))
+	betweenSteps := ffclient.WithEvaluationTime(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	afterSteps := ffclient.WithEvaluationTime(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, float64(0), percentageOfB(beforeSteps))
+	assert.InDelta(t, 50, percentageOfB(betweenSteps), 5)
+	assert.Equal(t, float64(100), percentageOfB(afterSteps))
+	assert.Equal(t, float64(100), percentageOfB())
+}
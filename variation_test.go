@@ -7,13 +7,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thomaspoignant/go-feature-flag/exporter"
-	"github.com/thomaspoignant/go-feature-flag/exporter/fileexporter"
 	"github.com/thomaspoignant/go-feature-flag/exporter/logsexporter"
 	"github.com/thomaspoignant/go-feature-flag/ffcontext"
 	"github.com/thomaspoignant/go-feature-flag/internal/cache"
@@ -21,8 +21,10 @@ import (
 	"github.com/thomaspoignant/go-feature-flag/internal/flag"
 	"github.com/thomaspoignant/go-feature-flag/model"
 	"github.com/thomaspoignant/go-feature-flag/retriever/fileretriever"
+	"github.com/thomaspoignant/go-feature-flag/retriever/inmemoryretriever"
 	"github.com/thomaspoignant/go-feature-flag/testutils"
 	"github.com/thomaspoignant/go-feature-flag/testutils/flagv1"
+	"github.com/thomaspoignant/go-feature-flag/testutils/mock"
 	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
 )
 
@@ -42,17 +44,32 @@ func (c *cacheMock) GetLatestUpdateDate() time.Time {
 	return time.Now()
 }
 
+func (c *cacheMock) GetGeneration() (int64, string) {
+	return 0, ""
+}
+
 func (c *cacheMock) ConvertToFlagStruct(loadedFlags []byte, fileFormat string) (map[string]dto.DTO, error) {
 	return nil, nil
 }
+func (c *cacheMock) ConvertToDelta(loadedDelta []byte, fileFormat string) (dto.Delta, error) {
+	return dto.Delta{}, nil
+}
 func (c *cacheMock) UpdateCache(newFlags map[string]dto.DTO, log *log.Logger) error {
 	return nil
 }
+func (c *cacheMock) ApplyDeltas(deltas []dto.Delta, log *log.Logger) (map[string]dto.DTO, error) {
+	return nil, nil
+}
 func (c *cacheMock) Close() {}
 func (c *cacheMock) GetFlag(key string) (flag.Flag, error) {
 	return c.flag, c.err
 }
-func (c *cacheMock) AllFlags() (map[string]flag.Flag, error) { return nil, nil }
+func (c *cacheMock) AllFlags() (map[string]flag.Flag, error) {
+	if c.flag == nil || c.err != nil {
+		return nil, c.err
+	}
+	return map[string]flag.Flag{"test-flag": c.flag}, nil
+}
 
 func TestBoolVariation(t *testing.T) {
 	type args struct {
@@ -504,6 +521,7 @@ func TestBoolVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"true\", variation=\"True\"\n",
@@ -546,6 +564,7 @@ func TestBoolVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"true\", variation=\"True\"\n",
@@ -584,6 +603,7 @@ func TestBoolVariationDetails(t *testing.T) {
 				Value:         true,
 				TrackEvents:   true,
 				Cacheable:     true,
+				RuleIndex:     testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"true\", variation=\"True\"\n",
@@ -626,6 +646,7 @@ func TestBoolVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key-ssss1\", flag=\"test-flag\", value=\"false\", variation=\"False\"\n",
@@ -1184,6 +1205,7 @@ func TestFloat64VariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"120.12\", variation=\"True\"\n",
@@ -1226,6 +1248,7 @@ func TestFloat64VariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key-ssss1\", flag=\"test-flag\", value=\"121.12\", variation=\"False\"\n",
@@ -1764,6 +1787,7 @@ func TestJSONArrayVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"\\[true\\]\"\n",
@@ -2275,6 +2299,7 @@ func TestJSONVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"map\\[true:true\\]\", variation=\"True\"\n",
@@ -2317,6 +2342,7 @@ func TestJSONVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key-ssss1\", flag=\"test-flag\", value=\"map\\[false:true\\]\", variation=\"False\"\n",
@@ -2763,6 +2789,7 @@ func TestStringVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"true\", variation=\"True\"\n",
@@ -2805,6 +2832,7 @@ func TestStringVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key-ssss1\", flag=\"test-flag\", value=\"false\", variation=\"False\"\n",
@@ -3281,6 +3309,7 @@ func TestIntVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"120\", variation=\"True\"\n",
@@ -3323,6 +3352,7 @@ func TestIntVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key-ssss1\", flag=\"test-flag\", value=\"121\", variation=\"False\"\n",
@@ -3365,6 +3395,7 @@ func TestIntVariationDetails(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"120\", variation=\"True\"\n",
@@ -3434,6 +3465,73 @@ func TestIntVariationDetails(t *testing.T) {
 	}
 }
 
+func TestVariationDetailsReasonAndRuleIndex(t *testing.T) {
+	f := &flag.InternalFlag{
+		Variations: &map[string]*interface{}{
+			"rule1":   testconvert.Interface("value-rule1"),
+			"rule2":   testconvert.Interface("value-rule2"),
+			"default": testconvert.Interface("value-default"),
+		},
+		Rules: &[]flag.Rule{
+			{
+				Name:            testconvert.String("rule1"),
+				Query:           testconvert.String("key eq \"user-1\""),
+				VariationResult: testconvert.String("rule1"),
+			},
+			{
+				Name:            testconvert.String("rule2"),
+				Query:           testconvert.String("key eq \"user-2\""),
+				VariationResult: testconvert.String("rule2"),
+			},
+		},
+		DefaultRule: &flag.Rule{
+			VariationResult: testconvert.String("default"),
+		},
+	}
+
+	tests := []struct {
+		name          string
+		user          ffcontext.Context
+		wantValue     string
+		wantReason    flag.ResolutionReason
+		wantRuleIndex *int
+	}{
+		{
+			name:          "first rule matches",
+			user:          ffcontext.NewEvaluationContext("user-1"),
+			wantValue:     "value-rule1",
+			wantReason:    flag.ReasonTargetingMatch,
+			wantRuleIndex: testconvert.Int(0),
+		},
+		{
+			name:          "second rule matches",
+			user:          ffcontext.NewEvaluationContext("user-2"),
+			wantValue:     "value-rule2",
+			wantReason:    flag.ReasonTargetingMatch,
+			wantRuleIndex: testconvert.Int(1),
+		},
+		{
+			name:       "no rule matches, default rule is used",
+			user:       ffcontext.NewEvaluationContext("user-3"),
+			wantValue:  "value-default",
+			wantReason: flag.ReasonDefault,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goff := &GoFeatureFlag{
+				cache:  NewCacheMock(f, nil),
+				config: Config{},
+			}
+			got, err := goff.StringVariationDetails("my-flag", tt.user, "sdk-default")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValue, got.Value)
+			assert.Equal(t, tt.wantReason, got.Reason)
+			assert.Equal(t, tt.wantRuleIndex, got.RuleIndex)
+		})
+	}
+}
+
 func TestAllFlagsState(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -3441,6 +3539,7 @@ func TestAllFlagsState(t *testing.T) {
 		valid      bool
 		jsonOutput string
 		initModule bool
+		wantEvents int
 	}{
 		{
 			name: "Valid multiple types",
@@ -3452,6 +3551,7 @@ func TestAllFlagsState(t *testing.T) {
 			valid:      true,
 			jsonOutput: "./testdata/ffclient/all_flags/marshal_json/valid_multiple_types.json",
 			initModule: true,
+			wantEvents: 5,
 		},
 		{
 			name: "Error in flag-0",
@@ -3463,6 +3563,7 @@ func TestAllFlagsState(t *testing.T) {
 			valid:      false,
 			jsonOutput: "./testdata/ffclient/all_flags/marshal_json/error_in_flag_0.json",
 			initModule: true,
+			wantEvents: 5,
 		},
 		{
 			name: "module not init",
@@ -3490,12 +3591,11 @@ func TestAllFlagsState(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// init logger
-			exportDir, _ := os.MkdirTemp("", "export")
+			exp := &mock.Exporter{Bulk: true}
 			tt.config.DataExporter = DataExporter{
-				FlushInterval:    1000,
-				MaxEventInMemory: 1,
-				Exporter:         &fileexporter.Exporter{OutputDir: exportDir},
+				FlushInterval:    10 * time.Second,
+				MaxEventInMemory: 100,
+				Exporter:         exp,
 			}
 
 			var goff *GoFeatureFlag
@@ -3503,7 +3603,6 @@ func TestAllFlagsState(t *testing.T) {
 			if tt.initModule {
 				goff, err = New(tt.config)
 				assert.NoError(t, err)
-				defer goff.Close()
 			} else {
 				// we close directly so we can test with module not init
 				goff, _ = New(tt.config)
@@ -3532,13 +3631,79 @@ func TestAllFlagsState(t *testing.T) {
 			assert.NoError(t, err)
 			assert.JSONEq(t, string(expectedJSON), string(marshaled))
 
-			// no data exported
-			files, _ := os.ReadDir(exportDir)
-			assert.Equal(t, 0, len(files))
+			// closing the module flushes the events collected during the evaluation.
+			if tt.initModule {
+				goff.Close()
+			}
+			assert.Len(t, exp.GetExportedEvents(), tt.wantEvents)
 		})
 	}
 }
 
+func TestAllFlagsStateWithError(t *testing.T) {
+	exp := &mock.Exporter{Bulk: true}
+	goff, err := New(Config{
+		Retriever: &fileretriever.Retriever{
+			Path: "./testdata/ffclient/all_flags/config_flag/flag-config-with-error.yaml",
+		},
+		DataExporter: DataExporter{
+			FlushInterval:    10 * time.Second,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+
+	allFlagsState := goff.AllFlagsState(ffcontext.NewEvaluationContext("random-key"))
+	goff.Close()
+
+	assert.False(t, allFlagsState.IsValid())
+	flags := allFlagsState.GetFlags()
+	assert.Len(t, flags, 6)
+
+	// the flag in error is part of the result and marked as failed
+	assert.True(t, flags["test-flag0"].Failed)
+	assert.Equal(t, flag.ReasonError, flags["test-flag0"].Reason)
+	assert.Equal(t, flag.ErrorCodeTypeMismatch, flags["test-flag0"].ErrorCode)
+
+	// the other flags are evaluated normally
+	assert.Equal(t, "true", flags["test-flag1"].Value)
+	assert.Equal(t, []interface{}{"yo", "ya"}, flags["test-flag3"].Value)
+	assert.Equal(t, map[string]interface{}{"test": "yo"}, flags["test-flag4"].Value)
+	for _, key := range []string{"test-flag1", "test-flag2", "test-flag3", "test-flag4", "test-flag5"} {
+		assert.False(t, flags[key].Failed, key)
+		assert.Equal(t, "True", flags[key].VariationType, key)
+	}
+
+	// an event is collected for each flag tracking events
+	events := exp.GetExportedEvents()
+	assert.Len(t, events, 5)
+	for _, event := range events {
+		assert.Equal(t, "random-key", event.UserKey)
+		assert.NotEqual(t, "test-flag5", event.Key)
+		if event.Key == "test-flag0" {
+			assert.Equal(t, flag.VariationSDKDefault, event.Variation)
+		}
+	}
+}
+
+func TestAllFlagsState_doesNotMutateEnrichment(t *testing.T) {
+	enrichment := map[string]interface{}{"team": "checkout"}
+	goff, err := New(Config{
+		Retriever: &fileretriever.Retriever{
+			Path: "./testdata/ffclient/all_flags/config_flag/flag-config-all-flags.yaml",
+		},
+		Environment:                 "production",
+		EvaluationContextEnrichment: enrichment,
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+
+	allFlagsState := goff.AllFlagsState(ffcontext.NewEvaluationContext("random-key"))
+	assert.True(t, allFlagsState.IsValid())
+	assert.Equal(t, map[string]interface{}{"team": "checkout"}, enrichment)
+}
+
 func TestAllFlagsFromCache(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -3769,6 +3934,7 @@ func TestRawVariation(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key\", flag=\"test-flag\", value=\"map\\[test2:test\\]\", variation=\"True\"",
@@ -3811,6 +3977,7 @@ func TestRawVariation(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^\\[" + testutils.RFC3339Regex + "\\] user=\"random-key-ssss1\", flag=\"test-flag\", value=\"map\\[test3:test\\]\", variation=\"False\"",
@@ -3854,6 +4021,7 @@ func TestRawVariation(t *testing.T) {
 				Metadata: map[string]interface{}{
 					"evaluatedRuleName": "legacyRuleV0",
 				},
+				RuleIndex: testconvert.Int(0),
 			},
 			wantErr:     false,
 			expectedLog: "^$",
@@ -3990,3 +4158,397 @@ func Test_constructMetadataParallel(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultContextAttributes(t *testing.T) {
+	flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+	err := os.WriteFile(flagFile, []byte(`region-flag:
+  variations:
+    eu: "eu-value"
+    other: "other-value"
+  targeting:
+    - query: region eq "eu" and environment eq "production"
+      variation: eu
+  defaultRule:
+    variation: other
+`), 0o600)
+	assert.NoError(t, err)
+
+	gffClient, err := New(Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile},
+		DefaultContextAttributes: map[string]interface{}{
+			"region":      "eu",
+			"environment": "production",
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	// the rule matches on the default attributes even if the context does not have them
+	ctx := ffcontext.NewEvaluationContext("user-key")
+	got, err := gffClient.StringVariation("region-flag", ctx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-value", got)
+	assert.Empty(t, ctx.GetCustom(), "the evaluation context should not be modified")
+	allFlags := gffClient.AllFlagsState(ctx)
+	assert.Equal(t, "eu-value", allFlags.GetFlags()["region-flag"].Value)
+
+	// the attributes of the context override the default ones
+	ctx = ffcontext.NewEvaluationContextBuilder("user-key").AddCustom("region", "us").Build()
+	got, err = gffClient.StringVariation("region-flag", ctx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "other-value", got)
+	assert.Equal(t, map[string]interface{}{"region": "us"}, ctx.GetCustom())
+}
+
+func TestDefaultContextAttributes_contextType(t *testing.T) {
+	flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+	err := os.WriteFile(flagFile, []byte(`anonymous-flag:
+  variations:
+    anonymous: "anonymous-value"
+    other: "other-value"
+  targeting:
+    - query: anonymous eq true and region eq "eu"
+      variation: anonymous
+  defaultRule:
+    variation: other
+org-flag:
+  variations:
+    enterprise: "enterprise-value"
+    other: "other-value"
+  targeting:
+    - query: org.plan eq "enterprise" and region eq "eu"
+      variation: enterprise
+  defaultRule:
+    variation: other
+`), 0o600)
+	assert.NoError(t, err)
+
+	gffClient, err := New(Config{
+		PollingInterval:          10 * time.Second,
+		Retriever:                &fileretriever.Retriever{Path: flagFile},
+		DefaultContextAttributes: map[string]interface{}{"region": "eu"},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	// an anonymous context stays anonymous
+	anonymousCtx := ffcontext.NewEvaluationContextBuilder("anonymous-key").Anonymous(true).Build()
+	got, err := gffClient.StringVariation("anonymous-flag", anonymousCtx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "anonymous-value", got)
+
+	// a MultiContext keeps its kinds and the anonymous status of its targeting kind
+	multiCtx := ffcontext.NewMultiContextBuilder().
+		AddContext("user", ffcontext.NewEvaluationContextBuilder("user-key").Anonymous(true).Build()).
+		AddContext("org", ffcontext.NewEvaluationContextBuilder("org-key").AddCustom("plan", "enterprise").Build()).
+		Build()
+	got, err = gffClient.StringVariation("org-flag", multiCtx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "enterprise-value", got)
+	got, err = gffClient.StringVariation("anonymous-flag", multiCtx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "anonymous-value", got)
+	_, hasRegion := multiCtx.GetCustom()["region"]
+	assert.False(t, hasRegion, "the evaluation context should not be modified")
+}
+
+func TestKillSwitch(t *testing.T) {
+	flagConfig := `gofeatureflag.disableAll:
+  variations:
+    "on": true
+    "off": false
+  defaultRule:
+    variation: "%s"
+string-flag:
+  variations:
+    A: "value-A"
+  defaultRule:
+    variation: A
+bool-flag:
+  variations:
+    enabled: true
+  defaultRule:
+    variation: enabled
+`
+	flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+	writeFlags := func(killSwitch string) {
+		assert.NoError(t, os.WriteFile(flagFile, []byte(fmt.Sprintf(flagConfig, killSwitch)), 0o600))
+	}
+	newClient := func(enableKillSwitch bool) *GoFeatureFlag {
+		gffClient, err := New(Config{
+			PollingInterval:  10 * time.Second,
+			Retriever:        &fileretriever.Retriever{Path: flagFile},
+			EnableKillSwitch: enableKillSwitch,
+		})
+		assert.NoError(t, err)
+		return gffClient
+	}
+	ctx := ffcontext.NewEvaluationContext("user-key")
+
+	writeFlags("off")
+	gffClient := newClient(true)
+	got, err := gffClient.StringVariationDetails("string-flag", ctx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value-A", got.Value)
+	gffClient.Close()
+
+	writeFlags("on")
+	gffClient = newClient(true)
+	got, err = gffClient.StringVariationDetails("string-flag", ctx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "sdk-default", got.Value)
+	assert.Equal(t, flag.ReasonDisabled, got.Reason)
+	boolValue, err := gffClient.BoolVariation("bool-flag", ctx, false)
+	assert.NoError(t, err)
+	assert.False(t, boolValue)
+	killSwitch, err := gffClient.BoolVariation(KillSwitchFlagKey, ctx, false)
+	assert.NoError(t, err)
+	assert.True(t, killSwitch)
+	allFlags := gffClient.AllFlagsState(ctx)
+	assert.Equal(t, flag.ReasonDisabled, allFlags.GetFlags()["string-flag"].Reason)
+	assert.Equal(t, true, allFlags.GetFlags()[KillSwitchFlagKey].Value)
+	gffClient.Close()
+
+	// without the option, the flag has nothing special
+	gffClient = newClient(false)
+	defer gffClient.Close()
+	got, err = gffClient.StringVariationDetails("string-flag", ctx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value-A", got.Value)
+}
+
+func TestDefaultValueProvider(t *testing.T) {
+	gffClient, err := New(Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+		DefaultValueProvider: func(flagKey string, evaluationCtx ffcontext.Context) (interface{}, error) {
+			switch flagKey {
+			case "unknown-string-flag":
+				return "fallback-" + evaluationCtx.GetKey(), nil
+			case "unknown-int-flag":
+				return 42.0, nil
+			case "unknown-error-flag":
+				return nil, errors.New("secondary source not available")
+			default:
+				return true, nil
+			}
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+	ctx := ffcontext.NewEvaluationContext("user-key")
+
+	got, err := gffClient.StringVariationDetails("unknown-string-flag", ctx, "sdk-default")
+	assert.NoError(t, err)
+	assert.Equal(t, "fallback-user-key", got.Value)
+	assert.Equal(t, flag.ReasonFallback, got.Reason)
+	assert.False(t, got.Failed)
+
+	intValue, err := gffClient.IntVariation("unknown-int-flag", ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, intValue)
+
+	// the provider fails, the default value of the caller is used
+	got, err = gffClient.StringVariationDetails("unknown-error-flag", ctx, "sdk-default")
+	assert.Error(t, err)
+	assert.Equal(t, "sdk-default", got.Value)
+	assert.Equal(t, flag.ErrorCodeFlagNotFound, got.ErrorCode)
+
+	// the provider returns a value of the wrong type, the default value of the caller is used
+	got, err = gffClient.StringVariationDetails("unknown-bool-flag", ctx, "sdk-default")
+	assert.Error(t, err)
+	assert.Equal(t, "sdk-default", got.Value)
+
+	// the provider is not used for an existing flag
+	value, err := gffClient.BoolVariation("test-flag", ctx, true)
+	assert.NoError(t, err)
+	assert.False(t, value)
+}
+
+func TestVariationMetadata(t *testing.T) {
+	flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+	err := os.WriteFile(flagFile, []byte(`css-experiment:
+  variations:
+    control: "blue"
+    treatment: "green"
+  variationMetadata:
+    control:
+      description: current blue button
+    treatment:
+      description: new green button
+      owner: design-team
+  targeting:
+    - query: beta eq true
+      variation: treatment
+  defaultRule:
+    variation: control
+  metadata:
+    issue-link: https://jira.xxx/GOFF-01
+`), 0o600)
+	assert.NoError(t, err)
+
+	gffClient, err := New(Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &fileretriever.Retriever{Path: flagFile},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+
+	got, err := gffClient.StringVariationDetails("css-experiment", ffcontext.NewEvaluationContext("user-key"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "blue", got.Value)
+	assert.Equal(t, map[string]interface{}{"description": "current blue button"}, got.VariationMetadata)
+	assert.Equal(t, "https://jira.xxx/GOFF-01", got.Metadata["issue-link"])
+
+	betaUser := ffcontext.NewEvaluationContextBuilder("beta-user").AddCustom("beta", true).Build()
+	raw, err := gffClient.RawVariation("css-experiment", betaUser, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "green", raw.Value)
+	assert.Equal(t, map[string]interface{}{"description": "new green button", "owner": "design-team"},
+		raw.VariationMetadata)
+}
+
+func TestEventsConfigGeneration(t *testing.T) {
+	const flagConfig = `my-flag:
+  variations:
+    enabled: true
+    disabled: false
+  defaultRule:
+    variation: %s
+`
+	flagFile := filepath.Join(t.TempDir(), "flags.yaml")
+	assert.NoError(t, os.WriteFile(flagFile, []byte(fmt.Sprintf(flagConfig, "enabled")), 0o600))
+
+	exp := &mock.Exporter{Bulk: true}
+	gffClient, err := New(Config{
+		PollingInterval: 10 * time.Minute,
+		Retriever:       &fileretriever.Retriever{Path: flagFile},
+		DataExporter: DataExporter{
+			FlushInterval:    10 * time.Minute,
+			MaxEventInMemory: 100,
+			Exporter:         exp,
+		},
+	})
+	assert.NoError(t, err)
+
+	user := ffcontext.NewEvaluationContext("user-key")
+	_, _ = gffClient.BoolVariation("my-flag", user, false)
+
+	// the configuration is retrieved again without any change
+	gffClient.refreshFlags()
+	_, _ = gffClient.BoolVariation("my-flag", user, false)
+
+	// the configuration has changed
+	assert.NoError(t, os.WriteFile(flagFile, []byte(fmt.Sprintf(flagConfig, "disabled")), 0o600))
+	gffClient.refreshFlags()
+	_, _ = gffClient.BoolVariation("my-flag", user, false)
+	_ = gffClient.AllFlagsState(user)
+	gffClient.Close()
+
+	events := exp.GetExportedEvents()
+	assert.Len(t, events, 4)
+	assert.Equal(t, int64(1), events[0].ConfigGeneration)
+	assert.Equal(t, int64(1), events[1].ConfigGeneration, "an unchanged refresh should keep the generation")
+	assert.Equal(t, events[0].ConfigHash, events[1].ConfigHash)
+	assert.Equal(t, int64(2), events[2].ConfigGeneration, "a change of the configuration should increment the generation")
+	assert.NotEqual(t, events[0].ConfigHash, events[2].ConfigHash)
+	assert.Equal(t, int64(2), events[3].ConfigGeneration, "the events of AllFlagsState should be stamped too")
+	assert.Equal(t, events[2].ConfigHash, events[3].ConfigHash)
+}
+
+func TestEventsContextAttributes(t *testing.T) {
+	user := ffcontext.NewEvaluationContextBuilder("user-key").
+		AddCustom("country", "FR").
+		AddCustom("email", "john.doe@example.com").
+		Build()
+	tests := []struct {
+		name              string
+		contextAttributes []string
+		want              map[string]interface{}
+	}{
+		{
+			name: "no attribute requested",
+			want: nil,
+		},
+		{
+			name:              "only the requested attributes",
+			contextAttributes: []string{"country", "unknown"},
+			want:              map[string]interface{}{"country": "FR"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := &mock.Exporter{Bulk: true, ContextAttributes: tt.contextAttributes}
+			gffClient, err := New(Config{
+				PollingInterval: 10 * time.Minute,
+				Retriever:       &fileretriever.Retriever{Path: "testdata/flag-config.yaml"},
+				DataExporter: DataExporter{
+					FlushInterval:    10 * time.Minute,
+					MaxEventInMemory: 100,
+					Exporter:         exp,
+				},
+			})
+			assert.NoError(t, err)
+			_, _ = gffClient.BoolVariation("test-flag", user, false)
+			gffClient.Close()
+
+			events := exp.GetExportedEvents()
+			assert.Len(t, events, 1)
+			assert.Equal(t, tt.want, events[0].EvaluationContext)
+		})
+	}
+}
+
+func TestObjectVariation(t *testing.T) {
+	gffClient, err := New(Config{
+		PollingInterval: 10 * time.Second,
+		Retriever: &inmemoryretriever.Retriever{
+			Flags: map[string]interface{}{
+				"object-flag": map[string]interface{}{
+					"variations": map[string]interface{}{
+						"default": map[string]interface{}{"theme": "dark", "retries": 3, "regions": []interface{}{"eu", "us"}},
+					},
+					"defaultRule": map[string]interface{}{"variation": "default"},
+				},
+				"array-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"default": []interface{}{"a", "b"}},
+					"defaultRule": map[string]interface{}{"variation": "default"},
+				},
+				"bool-flag": map[string]interface{}{
+					"variations":  map[string]interface{}{"enabled": true},
+					"defaultRule": map[string]interface{}{"variation": "enabled"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer gffClient.Close()
+	ctx := ffcontext.NewEvaluationContext("user-key")
+
+	got, err := gffClient.ObjectVariationDetails("object-flag", ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"theme":   "dark",
+		"retries": float64(3),
+		"regions": []interface{}{"eu", "us"},
+	}, got.Value)
+	assert.Equal(t, flag.ReasonStatic, got.Reason)
+	assert.Equal(t, "default", got.VariationType)
+
+	array, err := gffClient.ObjectVariation("array-flag", ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, array)
+
+	// a scalar value is not an object, the default value is returned
+	defaultValue := map[string]interface{}{"theme": "light"}
+	got, err = gffClient.ObjectVariationDetails("bool-flag", ctx, defaultValue)
+	assert.ErrorIs(t, err, ErrWrongVariationType)
+	assert.Equal(t, defaultValue, got.Value)
+	assert.Equal(t, flag.ErrorCodeTypeMismatch, got.ErrorCode)
+
+	// the object flag can't be used as a boolean
+	boolValue, err := gffClient.BoolVariation("object-flag", ctx, false)
+	assert.ErrorIs(t, err, ErrWrongVariationType)
+	assert.False(t, boolValue)
+}